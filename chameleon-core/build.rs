@@ -19,13 +19,13 @@ fn main() {
     
     println!("cargo:warning=LALRPOP processing complete!");
     
-    // Generate C header (cbindgen)
+    // Generate C header (cbindgen, configured by cbindgen.toml)
     let crate_dir = env::var("CARGO_MANIFEST_DIR").unwrap();
+    let config = cbindgen::Config::from_root_or_default(&crate_dir);
     
     cbindgen::Builder::new()
         .with_crate(crate_dir)
-        .with_language(cbindgen::Language::C)
-        .with_include_guard("CHAMELEON_H")
+        .with_config(config)
         .generate()
         .expect("Unable to generate bindings")
         .write_to_file("include/chameleon.h");
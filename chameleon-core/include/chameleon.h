@@ -6,6 +6,20 @@
 #include <stdint.h>
 #include <stdlib.h>
 
+/**
+ * Version of the C ABI. Bumped whenever a function is removed or changes
+ * its signature, memory ownership or output format; additions are
+ * announced as capabilities instead.
+ */
+#define CHAMELEON_ABI_VERSION 1
+
+/**
+ * Payload encodings of encoded calls
+ */
+#define CHAMELEON_ENCODING_JSON 0
+
+#define CHAMELEON_ENCODING_MSGPACK 1
+
 /**
  * Result code for FFI functions
  */
@@ -16,6 +30,23 @@ typedef enum ChameleonResult {
   InternalError = 3,
 } ChameleonResult;
 
+/**
+ * A parsed schema held by the core (opaque to C)
+ */
+typedef struct ChameleonSchema ChameleonSchema;
+
+/**
+ * Output buffer of encoded calls, owned by the core. Zero it before the
+ * first call, pass it to each call after, and free it with
+ * `chameleon_buffer_free`. `data[..len]` is the last answer (or error
+ * message), valid until the next call with the buffer.
+ */
+typedef struct ChameleonBuffer {
+  uint8_t *data;
+  uintptr_t len;
+  uintptr_t cap;
+} ChameleonBuffer;
+
 /**
  * Parse a schema from a string and return JSON representation
  *
@@ -42,6 +73,18 @@ void chameleon_free_string(char *s);
  */
 const char *chameleon_version(void);
 
+/**
+ * Get the ABI version of the library (see CHAMELEON_ABI_VERSION).
+ * Callers check it before any other call.
+ */
+uint32_t chameleon_abi_version(void);
+
+/**
+ * Get the features of the library, comma separated. The string is static:
+ * do not free it.
+ */
+const char *chameleon_capabilities(void);
+
 /**
  * Generate SQL from a query JSON + schema JSON
  */
@@ -54,6 +97,100 @@ enum ChameleonResult chameleon_generate_sql(const char *query_json,
  */
 enum ChameleonResult chameleon_generate_migration(const char *schema_json, char **error_out);
 
+/**
+ * Load a schema JSON into a handle
+ *
+ * # Safety
+ * - `schema_json` must be a valid null-terminated C string
+ * - Caller must free the returned handle with `chameleon_schema_free`
+ * - Returns NULL on error, check `error_out` for details
+ */
+struct ChameleonSchema *chameleon_schema_load(const char *schema_json, char **error_out);
+
+/**
+ * Free a schema handle
+ *
+ * # Safety
+ * `handle` must come from `chameleon_schema_load` and not be used afterwards
+ */
+void chameleon_schema_free(struct ChameleonSchema *handle);
+
+/**
+ * Generate SQL from a query JSON against a schema handle
+ *
+ * # Safety
+ * - `handle` must be a live handle from `chameleon_schema_load`
+ * - `query_json` must be a valid null-terminated C string
+ * - On success `out` holds the GeneratedSQL JSON, otherwise the error;
+ *   free it with `chameleon_free_string`
+ */
+enum ChameleonResult chameleon_schema_generate_sql(const struct ChameleonSchema *handle,
+                                                   const char *query_json,
+                                                   char **out);
+
+/**
+ * Generate migration SQL for a schema handle
+ *
+ * # Safety
+ * - `handle` must be a live handle from `chameleon_schema_load`
+ * - On success `out` holds the SQL, otherwise the error;
+ *   free it with `chameleon_free_string`
+ */
+enum ChameleonResult chameleon_schema_generate_migration(const struct ChameleonSchema *handle,
+                                                         char **out);
+
+/**
+ * Generate SQL for a mutation against a schema handle
+ *
+ * # Safety
+ * - `handle` must be a live handle from `chameleon_schema_load`
+ * - `mutation_json` must be a valid null-terminated C string
+ *
+ * # Returns
+ * JSON: {"valid":true,"sql":"...","params":[...]} or {"valid":false,"error":"..."};
+ * free it with `chameleon_free_string`
+ */
+char *chameleon_schema_generate_mutation_sql(const struct ChameleonSchema *handle,
+                                             const char *mutation_json);
+
+/**
+ * Generate SQL for an encoded query against a schema handle; the answer
+ * is the GeneratedSQL in the same encoding
+ *
+ * # Safety
+ * - `handle` must be a live handle from `chameleon_schema_load`
+ * - `input` must point to `input_len` readable bytes
+ * - `out` must be a zeroed buffer or one from a previous encoded call
+ */
+enum ChameleonResult chameleon_schema_generate_sql_encoded(const struct ChameleonSchema *handle,
+                                                           uint32_t encoding,
+                                                           const uint8_t *input,
+                                                           uintptr_t input_len,
+                                                           struct ChameleonBuffer *out);
+
+/**
+ * Generate SQL for an encoded mutation against a schema handle; the
+ * answer is {"valid":true,"sql":"...","params":[...]} or
+ * {"valid":false,"error":"..."} in the same encoding
+ *
+ * # Safety
+ * Same as `chameleon_schema_generate_sql_encoded`
+ */
+enum ChameleonResult chameleon_schema_generate_mutation_sql_encoded(const struct ChameleonSchema *handle,
+                                                                    uint32_t encoding,
+                                                                    const uint8_t *input,
+                                                                    uintptr_t input_len,
+                                                                    struct ChameleonBuffer *out);
+
+/**
+ * Free the memory of a buffer from encoded calls (the struct itself
+ * belongs to the caller); the buffer is zeroed and can be used again
+ *
+ * # Safety
+ * `buf` must be a zeroed buffer or one from a previous encoded call
+ */
+void chameleon_buffer_free(struct ChameleonBuffer *buf);
+
 /**
  * Set schema cache for efficient batch operations
  *
@@ -22,6 +22,8 @@ pub struct Field {
     pub primary_key: bool,
     pub default: Option<DefaultValue>,
     pub backend: Option<BackendAnnotation>,
+    #[serde(default)]
+    pub constraints: Vec<FieldConstraint>,
 }
 
 #[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
@@ -44,6 +46,27 @@ pub enum DefaultValue {
     Literal(String),
 }
 
+/// Value constraint declared on a field, enforced by the Go validator and
+/// emitted as a CHECK constraint by migrations
+///
+///   age: int min(0) max(150),
+///   name: string length(1..255),
+///   code: string pattern("^[A-Z]{3}$"),
+///   total: decimal check("total >= 0"),
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub enum FieldConstraint {
+    /// Lower bound (inclusive) of a numeric field, as written ("0", "-1.5")
+    Min(String),
+    /// Upper bound (inclusive) of a numeric field
+    Max(String),
+    /// Character length bounds (inclusive) of a string field
+    Length { min: Option<usize>, max: Option<usize> },
+    /// Regular expression a string field must match
+    Pattern(String),
+    /// Raw SQL boolean expression, enforced by the database only
+    Check(String),
+}
+
 #[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
 pub struct Relation {
     pub name: String,
@@ -74,6 +97,7 @@ pub enum FieldModifier {
     Unique,
     Nullable,
     Default(DefaultValue),
+    Constraint(FieldConstraint),
 }
 
 #[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
@@ -113,6 +137,89 @@ impl Schema {
     pub fn get_entity_mut(&mut self, name: &str) -> Option<&mut Entity> {
         self.entities.iter_mut().find(|e| e.name == name)
     }
+
+    /// Resolve the foreign key column of a relation
+    ///
+    /// HasMany/HasOne declare it with `via`. A BelongsTo relation
+    /// (`user: User`) reuses the FK of the inverse relation on the target,
+    /// falling back to `<relation>_id` when the entity has that field.
+    /// ManyToMany relations have no single FK (see resolve_through_keys).
+    pub fn resolve_foreign_key(&self, entity_name: &str, relation: &Relation) -> Option<String> {
+        if let Some(fk) = &relation.foreign_key {
+            return Some(fk.clone());
+        }
+
+        if relation.kind != RelationKind::BelongsTo {
+            return None;
+        }
+
+        let entity = self.get_entity(entity_name)?;
+
+        // Inverse relation: User.orders: [Order] via user_id → Order.user uses user_id
+        if let Some(target) = self.get_entity(&relation.target_entity) {
+            let mut inverse: Vec<&String> = target.relations.values()
+                .filter(|r| matches!(r.kind, RelationKind::HasMany | RelationKind::HasOne))
+                .filter(|r| r.target_entity == entity_name)
+                .filter_map(|r| r.foreign_key.as_ref())
+                .filter(|fk| entity.fields.contains_key(*fk))
+                .collect();
+            inverse.sort();
+            if let Some(fk) = inverse.first() {
+                return Some((*fk).clone());
+            }
+        }
+
+        let conventional = format!("{}_id", relation.name);
+        if entity.fields.contains_key(&conventional) {
+            return Some(conventional);
+        }
+
+        None
+    }
+
+    /// Resolve the join entity columns of a ManyToMany relation
+    ///
+    /// Returns (source_key, target_key): the columns on the `through`
+    /// entity that point at the declaring entity and at the target.
+    /// Each side is taken from the join entity's BelongsTo relation to
+    /// that entity, falling back to `<snake_entity>_id`.
+    ///
+    ///   Post.tags: [Tag] through PostTag → ("post_id", "tag_id")
+    pub fn resolve_through_keys(&self, entity_name: &str, relation: &Relation) -> Option<(String, String)> {
+        if relation.kind != RelationKind::ManyToMany {
+            return None;
+        }
+
+        let join_name = relation.through.as_ref()?;
+        let join = self.get_entity(join_name)?;
+
+        let key_to = |side: &str| -> Option<String> {
+            let mut candidates: Vec<String> = join.relations.values()
+                .filter(|r| r.kind == RelationKind::BelongsTo && r.target_entity == side)
+                .filter_map(|r| self.resolve_foreign_key(join_name, r))
+                .collect();
+            candidates.sort();
+            if let Some(fk) = candidates.into_iter().next() {
+                return Some(fk);
+            }
+
+            let conventional = format!("{}_id", crate::sql::naming::pascal_to_snake(side));
+            if join.fields.contains_key(&conventional) {
+                return Some(conventional);
+            }
+            None
+        };
+
+        let source_key = key_to(entity_name)?;
+        let target_key = key_to(&relation.target_entity)?;
+
+        // Self-referencing relations need two distinct columns
+        if source_key == target_key {
+            return None;
+        }
+
+        Some((source_key, target_key))
+    }
 }
 
 impl Entity {
@@ -131,4 +238,12 @@ impl Entity {
     pub fn add_relation(&mut self, relation: Relation) {
         self.relations.insert(relation.name.clone(), relation);
     }
+
+    /// Primary key column ("id" if none is declared)
+    pub fn primary_key(&self) -> String {
+        self.fields.values()
+            .find(|f| f.primary_key)
+            .map(|f| f.name.clone())
+            .unwrap_or_else(|| "id".to_string())
+    }
 }
\ No newline at end of file
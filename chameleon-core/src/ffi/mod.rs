@@ -8,6 +8,9 @@ use crate::ChameleonError;
 use std::sync::Mutex;
 use lazy_static::lazy_static;
 use serde_json::Value;
+use serde::de::DeserializeOwned;
+
+mod msgpack;
 
 lazy_static! {
     static ref CACHED_SCHEMA: Mutex<Option<Schema>> = Mutex::new(None);
@@ -238,6 +241,37 @@ pub extern "C" fn chameleon_version() -> *const c_char {
     VERSION.as_ptr() as *const c_char
 }
 
+// ============================================================
+// ABI HANDSHAKE
+// ============================================================
+
+/// Version of the C ABI. Bumped whenever a function is removed or changes
+/// its signature, memory ownership or output format; additions are
+/// announced as capabilities instead.
+pub const CHAMELEON_ABI_VERSION: u32 = 1;
+
+/// Features of this library, comma separated:
+/// - `parse_schema`, `validate_schema`, `generate_sql`, `generate_migration`,
+///   `mutation_sql`: the functions of the same name
+/// - `schema_handle`: `chameleon_schema_*` functions
+/// - `query_params`: `{"Param": n}` filter values, generated as `$n`
+/// - `encoded_calls`: `*_encoded` functions, in JSON or MessagePack
+static CAPABILITIES: &str = "parse_schema,validate_schema,generate_sql,generate_migration,mutation_sql,schema_handle,query_params,encoded_calls\0";
+
+/// Get the ABI version of the library (see CHAMELEON_ABI_VERSION).
+/// Callers check it before any other call.
+#[no_mangle]
+pub extern "C" fn chameleon_abi_version() -> u32 {
+    CHAMELEON_ABI_VERSION
+}
+
+/// Get the features of the library, comma separated. The string is static:
+/// do not free it.
+#[no_mangle]
+pub extern "C" fn chameleon_capabilities() -> *const c_char {
+    CAPABILITIES.as_ptr() as *const c_char
+}
+
 /// Generate SQL from a query JSON + schema JSON
 #[no_mangle]
 pub unsafe extern "C" fn chameleon_generate_sql(
@@ -266,32 +300,41 @@ pub unsafe extern "C" fn chameleon_generate_sql(
         }
     };
 
-    let query: crate::query::Query = match serde_json::from_str(query_str) {
-        Ok(q) => q,
+    let schema: Schema = match serde_json::from_str(schema_str) {
+        Ok(s) => s,
         Err(e) => {
-            set_error(error_out, &format!("Query deserialization error: {}", e));
+            set_error(error_out, &format!("Schema deserialization error: {}", e));
             return ChameleonResult::InternalError;
         }
     };
 
-    let schema: Schema = match serde_json::from_str(schema_str) {
-        Ok(s) => s,
+    generate_sql_for(query_str, &schema, error_out)
+}
+
+/// Generate SQL for a query against a parsed schema.
+/// On success `out` holds the GeneratedSQL JSON, otherwise the error.
+unsafe fn generate_sql_for(
+    query_str: &str,
+    schema: &Schema,
+    out: *mut *mut c_char,
+) -> ChameleonResult {
+    let query: crate::query::Query = match serde_json::from_str(query_str) {
+        Ok(q) => q,
         Err(e) => {
-            set_error(error_out, &format!("Schema deserialization error: {}", e));
+            set_error(out, &format!("Query deserialization error: {}", e));
             return ChameleonResult::InternalError;
         }
     };
 
-    match crate::sql::generate_sql(&query, &schema) {
+    match crate::sql::generate_sql(&query, schema) {
         Ok(generated) => {
             let json = serde_json::to_string(&generated).unwrap();
             let c_str = CString::new(json).unwrap();
-            let ptr = c_str.into_raw();
-            *error_out = ptr;
+            *out = c_str.into_raw();
             ChameleonResult::Ok
         }
         Err(e) => {
-            set_error(error_out, &format!("SQL generation error: {}", e));
+            set_error(out, &format!("SQL generation error: {}", e));
             ChameleonResult::ValidationError
         }
     }
@@ -324,20 +367,359 @@ pub unsafe extern "C" fn chameleon_generate_migration(
         }
     };
 
-    match crate::migration::generator::generate_migration(&schema) {
+    generate_migration_for(&schema, error_out)
+}
+
+/// Generate migration SQL for a parsed schema.
+/// On success `out` holds the SQL, otherwise the error.
+unsafe fn generate_migration_for(schema: &Schema, out: *mut *mut c_char) -> ChameleonResult {
+    match crate::migration::generator::generate_migration(schema) {
         Ok(migration) => {
             let c_str = CString::new(migration.sql).unwrap();
-            let ptr = c_str.into_raw();
-            *error_out = ptr;
+            *out = c_str.into_raw();
             ChameleonResult::Ok
         }
         Err(e) => {
-            set_error(error_out, &format!("Migration generation error: {}", e));
+            set_error(out, &format!("Migration generation error: {}", e));
             ChameleonResult::ValidationError
         }
     }
 }
 
+// ============================================================
+// SCHEMA HANDLES
+// ============================================================
+//
+// A handle owns a schema deserialized once, so callers stop sending the
+// schema JSON with every call. Handles are immutable: any number of threads
+// may use one at a time, and each engine holds its own. Free a handle with
+// chameleon_schema_free once no call is using it.
+
+/// A parsed schema held by the core (opaque to C)
+pub struct ChameleonSchema {
+    schema: Schema,
+}
+
+/// Load a schema JSON into a handle
+///
+/// # Safety
+/// - `schema_json` must be a valid null-terminated C string
+/// - Caller must free the returned handle with `chameleon_schema_free`
+/// - Returns NULL on error, check `error_out` for details
+#[no_mangle]
+pub unsafe extern "C" fn chameleon_schema_load(
+    schema_json: *const c_char,
+    error_out: *mut *mut c_char,
+) -> *mut ChameleonSchema {
+    if schema_json.is_null() {
+        set_error(error_out, "Schema JSON is null");
+        return ptr::null_mut();
+    }
+
+    let json_str = match CStr::from_ptr(schema_json).to_str() {
+        Ok(s) => s,
+        Err(e) => {
+            set_error(error_out, &format!("Invalid UTF-8: {}", e));
+            return ptr::null_mut();
+        }
+    };
+
+    match serde_json::from_str::<Schema>(json_str) {
+        Ok(schema) => Box::into_raw(Box::new(ChameleonSchema { schema })),
+        Err(e) => {
+            set_error(error_out, &format!("Schema deserialization error: {}", e));
+            ptr::null_mut()
+        }
+    }
+}
+
+/// Free a schema handle
+///
+/// # Safety
+/// `handle` must come from `chameleon_schema_load` and not be used afterwards
+#[no_mangle]
+pub unsafe extern "C" fn chameleon_schema_free(handle: *mut ChameleonSchema) {
+    if !handle.is_null() {
+        drop(Box::from_raw(handle));
+    }
+}
+
+/// Generate SQL from a query JSON against a schema handle
+///
+/// # Safety
+/// - `handle` must be a live handle from `chameleon_schema_load`
+/// - `query_json` must be a valid null-terminated C string
+/// - On success `out` holds the GeneratedSQL JSON, otherwise the error;
+///   free it with `chameleon_free_string`
+#[no_mangle]
+pub unsafe extern "C" fn chameleon_schema_generate_sql(
+    handle: *const ChameleonSchema,
+    query_json: *const c_char,
+    out: *mut *mut c_char,
+) -> ChameleonResult {
+    if handle.is_null() || query_json.is_null() {
+        set_error(out, "Schema handle or query JSON is null");
+        return ChameleonResult::InternalError;
+    }
+
+    let query_str = match CStr::from_ptr(query_json).to_str() {
+        Ok(s) => s,
+        Err(e) => {
+            set_error(out, &format!("Invalid query JSON UTF-8: {}", e));
+            return ChameleonResult::InternalError;
+        }
+    };
+
+    generate_sql_for(query_str, &(*handle).schema, out)
+}
+
+/// Generate migration SQL for a schema handle
+///
+/// # Safety
+/// - `handle` must be a live handle from `chameleon_schema_load`
+/// - On success `out` holds the SQL, otherwise the error;
+///   free it with `chameleon_free_string`
+#[no_mangle]
+pub unsafe extern "C" fn chameleon_schema_generate_migration(
+    handle: *const ChameleonSchema,
+    out: *mut *mut c_char,
+) -> ChameleonResult {
+    if handle.is_null() {
+        set_error(out, "Schema handle is null");
+        return ChameleonResult::InternalError;
+    }
+
+    generate_migration_for(&(*handle).schema, out)
+}
+
+/// Generate SQL for a mutation against a schema handle
+///
+/// # Safety
+/// - `handle` must be a live handle from `chameleon_schema_load`
+/// - `mutation_json` must be a valid null-terminated C string
+///
+/// # Returns
+/// JSON: {"valid":true,"sql":"...","params":[...]} or {"valid":false,"error":"..."};
+/// free it with `chameleon_free_string`
+#[no_mangle]
+pub unsafe extern "C" fn chameleon_schema_generate_mutation_sql(
+    handle: *const ChameleonSchema,
+    mutation_json: *const c_char,
+) -> *mut c_char {
+    let result = if handle.is_null() || mutation_json.is_null() {
+        serde_json::json!({"valid": false, "error": "Schema handle or mutation JSON is null"})
+    } else {
+        match CStr::from_ptr(mutation_json)
+            .to_str()
+            .map_err(|e| e.to_string())
+            .and_then(|s| serde_json::from_str::<Value>(s).map_err(|e| e.to_string()))
+        {
+            Ok(mutation) => crate::mutation::generate_mutation_sql(&mutation, &(*handle).schema),
+            Err(e) => serde_json::json!({
+                "valid": false,
+                "error": format!("Invalid mutation JSON: {}", e)
+            }),
+        }
+    };
+
+    CString::new(result.to_string()).unwrap().into_raw()
+}
+
+// ============================================================
+// ENCODED CALLS
+// ============================================================
+//
+// Encoded calls take their input as bytes in an encoding chosen per call
+// (JSON for debugging, MessagePack for compactness) and answer in the same
+// encoding. The answer is written into a buffer the caller passes back on
+// the next call, so its memory is reused instead of allocated per call.
+
+/// Payload encodings of encoded calls
+pub const CHAMELEON_ENCODING_JSON: u32 = 0;
+pub const CHAMELEON_ENCODING_MSGPACK: u32 = 1;
+
+/// Output buffer of encoded calls, owned by the core. Zero it before the
+/// first call, pass it to each call after, and free it with
+/// `chameleon_buffer_free`. `data[..len]` is the last answer (or error
+/// message), valid until the next call with the buffer.
+#[repr(C)]
+pub struct ChameleonBuffer {
+    pub data: *mut u8,
+    pub len: usize,
+    pub cap: usize,
+}
+
+impl ChameleonBuffer {
+    /// Take the buffer's memory back as an empty Vec
+    unsafe fn take(&mut self) -> Vec<u8> {
+        if self.data.is_null() {
+            return Vec::new();
+        }
+        let vec = Vec::from_raw_parts(self.data, 0, self.cap);
+        self.data = ptr::null_mut();
+        self.len = 0;
+        self.cap = 0;
+        vec
+    }
+
+    /// Hand a Vec's memory to the caller
+    fn put(&mut self, vec: Vec<u8>) {
+        let mut vec = std::mem::ManuallyDrop::new(vec);
+        self.data = vec.as_mut_ptr();
+        self.len = vec.len();
+        self.cap = vec.capacity();
+    }
+}
+
+#[derive(Clone, Copy)]
+enum Encoding {
+    Json,
+    MsgPack,
+}
+
+impl Encoding {
+    fn from_raw(raw: u32) -> Result<Self, String> {
+        match raw {
+            CHAMELEON_ENCODING_JSON => Ok(Encoding::Json),
+            CHAMELEON_ENCODING_MSGPACK => Ok(Encoding::MsgPack),
+            _ => Err(format!("Unknown encoding {}", raw)),
+        }
+    }
+
+    fn decode<T: DeserializeOwned>(self, input: &[u8]) -> Result<T, String> {
+        match self {
+            Encoding::Json => serde_json::from_slice(input).map_err(|e| e.to_string()),
+            Encoding::MsgPack => msgpack::from_slice(input).map_err(|e| e.to_string()),
+        }
+    }
+
+    fn encode<T: Serialize>(self, value: &T, out: &mut Vec<u8>) -> Result<(), String> {
+        match self {
+            Encoding::Json => serde_json::to_writer(&mut *out, value).map_err(|e| e.to_string()),
+            Encoding::MsgPack => msgpack::to_writer(out, value).map_err(|e| e.to_string()),
+        }
+    }
+}
+
+type CallError = (ChameleonResult, String);
+
+/// Run an encoded call: `call` reads the input and writes its answer to
+/// the buffer's memory. On error the buffer holds the message instead.
+unsafe fn encoded_call(
+    input: *const u8,
+    input_len: usize,
+    out: *mut ChameleonBuffer,
+    call: impl FnOnce(&[u8], &mut Vec<u8>) -> Result<(), CallError>,
+) -> ChameleonResult {
+    if out.is_null() {
+        return ChameleonResult::InternalError;
+    }
+    let out = &mut *out;
+    let mut buf = out.take();
+
+    let result = if input.is_null() && input_len > 0 {
+        Err((ChameleonResult::InternalError, "Input is null".to_string()))
+    } else {
+        let input = if input_len == 0 {
+            &[][..]
+        } else {
+            std::slice::from_raw_parts(input, input_len)
+        };
+        call(input, &mut buf)
+    };
+
+    let code = match result {
+        Ok(()) => ChameleonResult::Ok,
+        Err((code, message)) => {
+            buf.clear();
+            buf.extend_from_slice(message.as_bytes());
+            code
+        }
+    };
+    out.put(buf);
+    code
+}
+
+fn internal(message: String) -> CallError {
+    (ChameleonResult::InternalError, message)
+}
+
+/// Generate SQL for an encoded query against a schema handle; the answer
+/// is the GeneratedSQL in the same encoding
+///
+/// # Safety
+/// - `handle` must be a live handle from `chameleon_schema_load`
+/// - `input` must point to `input_len` readable bytes
+/// - `out` must be a zeroed buffer or one from a previous encoded call
+#[no_mangle]
+pub unsafe extern "C" fn chameleon_schema_generate_sql_encoded(
+    handle: *const ChameleonSchema,
+    encoding: u32,
+    input: *const u8,
+    input_len: usize,
+    out: *mut ChameleonBuffer,
+) -> ChameleonResult {
+    encoded_call(input, input_len, out, |input, buf| {
+        if handle.is_null() {
+            return Err(internal("Schema handle is null".to_string()));
+        }
+        let encoding = Encoding::from_raw(encoding).map_err(internal)?;
+
+        let query: crate::query::Query = encoding
+            .decode(input)
+            .map_err(|e| internal(format!("Query deserialization error: {}", e)))?;
+        let generated = crate::sql::generate_sql(&query, &(*handle).schema).map_err(|e| {
+            (ChameleonResult::ValidationError, format!("SQL generation error: {}", e))
+        })?;
+
+        encoding.encode(&generated, buf).map_err(internal)
+    })
+}
+
+/// Generate SQL for an encoded mutation against a schema handle; the
+/// answer is {"valid":true,"sql":"...","params":[...]} or
+/// {"valid":false,"error":"..."} in the same encoding
+///
+/// # Safety
+/// Same as `chameleon_schema_generate_sql_encoded`
+#[no_mangle]
+pub unsafe extern "C" fn chameleon_schema_generate_mutation_sql_encoded(
+    handle: *const ChameleonSchema,
+    encoding: u32,
+    input: *const u8,
+    input_len: usize,
+    out: *mut ChameleonBuffer,
+) -> ChameleonResult {
+    encoded_call(input, input_len, out, |input, buf| {
+        if handle.is_null() {
+            return Err(internal("Schema handle is null".to_string()));
+        }
+        let encoding = Encoding::from_raw(encoding).map_err(internal)?;
+
+        let result = match encoding.decode::<Value>(input) {
+            Ok(mutation) => crate::mutation::generate_mutation_sql(&mutation, &(*handle).schema),
+            Err(e) => serde_json::json!({
+                "valid": false,
+                "error": format!("Invalid mutation: {}", e)
+            }),
+        };
+
+        encoding.encode(&result, buf).map_err(internal)
+    })
+}
+
+/// Free the memory of a buffer from encoded calls (the struct itself
+/// belongs to the caller); the buffer is zeroed and can be used again
+///
+/// # Safety
+/// `buf` must be a zeroed buffer or one from a previous encoded call
+#[no_mangle]
+pub unsafe extern "C" fn chameleon_buffer_free(buf: *mut ChameleonBuffer) {
+    if !buf.is_null() {
+        drop((*buf).take());
+    }
+}
+
 // ============================================================
 // NEW: MUTATION SQL GENERATION (v0.1)
 // ============================================================
@@ -587,6 +969,206 @@ mod tests {
         }
     }
 
+    const HANDLE_SCHEMA: &str = r#"{"entities":[{"name":"User","fields":{"id":{"name":"id","field_type":"UUID","nullable":false,"unique":false,"primary_key":true,"default":null,"backend":null},"email":{"name":"email","field_type":"String","nullable":false,"unique":true,"primary_key":false,"default":null,"backend":null}},"relations":{}}]}"#;
+
+    unsafe fn take_string(s: *mut c_char) -> String {
+        assert!(!s.is_null());
+        let out = CStr::from_ptr(s).to_str().unwrap().to_string();
+        chameleon_free_string(s);
+        out
+    }
+
+    #[test]
+    fn test_schema_handle() {
+        let schema_json = CString::new(HANDLE_SCHEMA).unwrap();
+        let query_json = CString::new(
+            r#"{"entity":"User","filters":[],"includes":[],"order_by":[],"limit":null,"offset":null}"#,
+        )
+        .unwrap();
+        let mut out: *mut c_char = ptr::null_mut();
+
+        unsafe {
+            let handle = chameleon_schema_load(schema_json.as_ptr(), &mut out);
+            assert!(!handle.is_null(), "Load should succeed");
+            assert!(out.is_null());
+
+            let result = chameleon_schema_generate_sql(handle, query_json.as_ptr(), &mut out);
+            assert_eq!(result, ChameleonResult::Ok);
+            assert!(take_string(out).contains("FROM users"));
+
+            out = ptr::null_mut();
+            let result = chameleon_schema_generate_migration(handle, &mut out);
+            assert_eq!(result, ChameleonResult::Ok);
+            assert!(take_string(out).contains("CREATE TABLE users"));
+
+            let mutation = CString::new(
+                r#"{"type":"delete","entity":"User","filters":{"id":"u1"}}"#,
+            )
+            .unwrap();
+            let json = take_string(chameleon_schema_generate_mutation_sql(handle, mutation.as_ptr()));
+            assert!(json.contains("\"valid\":true"), "{}", json);
+
+            chameleon_schema_free(handle);
+        }
+    }
+
+    #[test]
+    fn test_schema_handle_errors() {
+        let invalid = CString::new("{\"entities\": 42}").unwrap();
+        let mut out: *mut c_char = ptr::null_mut();
+
+        unsafe {
+            let handle = chameleon_schema_load(invalid.as_ptr(), &mut out);
+            assert!(handle.is_null());
+            assert!(take_string(out).contains("Schema deserialization error"));
+
+            out = ptr::null_mut();
+            let result = chameleon_schema_generate_migration(ptr::null(), &mut out);
+            assert_eq!(result, ChameleonResult::InternalError);
+            assert!(take_string(out).contains("null"));
+
+            chameleon_schema_free(ptr::null_mut());
+        }
+    }
+
+    #[test]
+    fn test_abi_handshake() {
+        assert_eq!(chameleon_abi_version(), CHAMELEON_ABI_VERSION);
+
+        let capabilities = unsafe { CStr::from_ptr(chameleon_capabilities()) }
+            .to_str()
+            .unwrap();
+        let list: Vec<&str> = capabilities.split(',').collect();
+        assert!(list.contains(&"schema_handle"));
+        assert!(list.contains(&"query_params"));
+        assert!(list.iter().all(|c| !c.is_empty() && !c.contains(' ')));
+    }
+
+    fn buffer_str(buf: &ChameleonBuffer) -> &str {
+        unsafe { std::str::from_utf8(std::slice::from_raw_parts(buf.data, buf.len)).unwrap() }
+    }
+
+    #[test]
+    fn test_encoded_calls() {
+        let schema_json = CString::new(HANDLE_SCHEMA).unwrap();
+        let query: Value = serde_json::from_str(
+            r#"{"entity":"User","filters":[],"includes":[],"order_by":[],"limit":null,"offset":null}"#,
+        )
+        .unwrap();
+        let mut out = ChameleonBuffer { data: ptr::null_mut(), len: 0, cap: 0 };
+
+        unsafe {
+            let handle = chameleon_schema_load(schema_json.as_ptr(), &mut ptr::null_mut());
+
+            // JSON in, JSON out
+            let input = serde_json::to_vec(&query).unwrap();
+            let result = chameleon_schema_generate_sql_encoded(
+                handle, CHAMELEON_ENCODING_JSON, input.as_ptr(), input.len(), &mut out);
+            assert_eq!(result, ChameleonResult::Ok);
+            let json_answer: Value = serde_json::from_str(buffer_str(&out)).unwrap();
+            assert!(json_answer["main_query"].as_str().unwrap().contains("FROM users"));
+
+            // MessagePack in, MessagePack out, same answer, buffer reused
+            let first_data = out.data;
+            let mut input = Vec::new();
+            msgpack::to_writer(&mut input, &query).unwrap();
+            let result = chameleon_schema_generate_sql_encoded(
+                handle, CHAMELEON_ENCODING_MSGPACK, input.as_ptr(), input.len(), &mut out);
+            assert_eq!(result, ChameleonResult::Ok);
+            let answer: Value = msgpack::from_slice(std::slice::from_raw_parts(out.data, out.len)).unwrap();
+            assert_eq!(answer, json_answer);
+            assert_eq!(out.data, first_data, "buffer memory should be reused");
+
+            // Errors are written to the buffer
+            let result = chameleon_schema_generate_sql_encoded(
+                handle, 7, input.as_ptr(), input.len(), &mut out);
+            assert_eq!(result, ChameleonResult::InternalError);
+            assert_eq!(buffer_str(&out), "Unknown encoding 7");
+
+            let ghost = br#"{"entity":"Ghost","filters":[],"includes":[],"order_by":[],"limit":null,"offset":null}"#;
+            let result = chameleon_schema_generate_sql_encoded(
+                handle, CHAMELEON_ENCODING_JSON, ghost.as_ptr(), ghost.len(), &mut out);
+            assert_eq!(result, ChameleonResult::ValidationError);
+            assert!(buffer_str(&out).starts_with("SQL generation error"));
+
+            // Mutations
+            let mut input = Vec::new();
+            msgpack::to_writer(&mut input, &serde_json::json!({"type": "delete", "entity": "User", "filters": {"id": "u1"}})).unwrap();
+            let result = chameleon_schema_generate_mutation_sql_encoded(
+                handle, CHAMELEON_ENCODING_MSGPACK, input.as_ptr(), input.len(), &mut out);
+            assert_eq!(result, ChameleonResult::Ok);
+            let answer: Value = msgpack::from_slice(std::slice::from_raw_parts(out.data, out.len)).unwrap();
+            assert_eq!(answer["valid"], true);
+
+            chameleon_buffer_free(&mut out);
+            assert!(out.data.is_null());
+            chameleon_buffer_free(&mut out);
+            chameleon_schema_free(handle);
+        }
+    }
+
+    /// Time encoded calls in both encodings, and MessagePack decoding
+    /// direct vs through a serde_json::Value tree (how it was first done):
+    /// cargo test --release --lib bench_encoded_calls -- --ignored --nocapture
+    #[test]
+    #[ignore]
+    fn bench_encoded_calls() {
+        use crate::query::{ComparisonOp, FilterExpr, FilterValue, LogicalOp, Query, SortDirection};
+        use std::time::Instant;
+
+        const ROUNDS: u32 = 100_000;
+        fn time(label: &str, mut run: impl FnMut()) {
+            let start = Instant::now();
+            for _ in 0..ROUNDS {
+                run();
+            }
+            println!("{:<32} {:>6} ns/op", label, (start.elapsed() / ROUNDS).as_nanos());
+        }
+
+        let query = Query::new("User")
+            .filter(FilterExpr::Binary {
+                left: Box::new(FilterExpr::condition("email", ComparisonOp::Like, FilterValue::Param(1))),
+                op: LogicalOp::Or,
+                right: Box::new(FilterExpr::condition("email", ComparisonOp::Eq, FilterValue::Param(2))),
+            })
+            .filter(FilterExpr::condition("id", ComparisonOp::In, FilterValue::Param(3)))
+            .select(&["id", "email"])
+            .order_by("email", SortDirection::Asc)
+            .limit(50);
+        let json = serde_json::to_vec(&query).unwrap();
+        let mut packed = Vec::new();
+        msgpack::to_writer(&mut packed, &query).unwrap();
+
+        time("decode json", || {
+            serde_json::from_slice::<crate::query::Query>(&json).unwrap();
+        });
+        time("decode msgpack via Value", || {
+            let value: Value = msgpack::from_slice(&packed).unwrap();
+            serde_json::from_value::<crate::query::Query>(value).unwrap();
+        });
+        time("decode msgpack", || {
+            msgpack::from_slice::<crate::query::Query>(&packed).unwrap();
+        });
+
+        let schema_json = CString::new(HANDLE_SCHEMA).unwrap();
+        let mut out = ChameleonBuffer { data: ptr::null_mut(), len: 0, cap: 0 };
+        unsafe {
+            let handle = chameleon_schema_load(schema_json.as_ptr(), &mut ptr::null_mut());
+            for (label, encoding, input) in [
+                ("generate_sql_encoded json", CHAMELEON_ENCODING_JSON, &json),
+                ("generate_sql_encoded msgpack", CHAMELEON_ENCODING_MSGPACK, &packed),
+            ] {
+                time(label, || {
+                    let result = chameleon_schema_generate_sql_encoded(
+                        handle, encoding, input.as_ptr(), input.len(), &mut out);
+                    assert_eq!(result, ChameleonResult::Ok);
+                });
+            }
+            chameleon_buffer_free(&mut out);
+            chameleon_schema_free(handle);
+        }
+    }
+
     #[test]
     fn test_free_null_is_safe() {
         unsafe {
@@ -0,0 +1,819 @@
+//! Minimal MessagePack codec for FFI payloads
+//!
+//! A serde serializer and deserializer, so the types crossing the FFI are
+//! read and written directly with the serde definitions they use for JSON.
+//! Only the JSON data model is supported: nil, bool, integers, floats,
+//! strings, arrays and maps with string keys. Enums are encoded as JSON
+//! does: a unit variant is its name, any other a map {name: content}.
+
+use std::fmt;
+
+use serde::de::{self, DeserializeSeed, IntoDeserializer, Visitor};
+use serde::ser::{self, Serialize};
+use serde::Deserialize;
+
+/// Maximum nesting of arrays and maps (same as serde_json)
+const MAX_DEPTH: usize = 128;
+
+/// A codec error, or an error reported by a Serialize/Deserialize impl
+#[derive(Debug)]
+pub struct Error(String);
+
+impl Error {
+    fn codec(message: impl fmt::Display) -> Self {
+        Error(format!("MessagePack: {}", message))
+    }
+}
+
+impl fmt::Display for Error {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        f.write_str(&self.0)
+    }
+}
+
+impl std::error::Error for Error {}
+
+impl de::Error for Error {
+    fn custom<T: fmt::Display>(msg: T) -> Self {
+        Error(msg.to_string())
+    }
+}
+
+impl ser::Error for Error {
+    fn custom<T: fmt::Display>(msg: T) -> Self {
+        Error(msg.to_string())
+    }
+}
+
+type Result<T> = std::result::Result<T, Error>;
+
+// ============================================================
+// ENCODING
+// ============================================================
+
+/// Append the MessagePack encoding of a value to `out`
+pub fn to_writer<T: Serialize + ?Sized>(out: &mut Vec<u8>, value: &T) -> Result<()> {
+    value.serialize(&mut Serializer { out })
+}
+
+struct Serializer<'a> {
+    out: &'a mut Vec<u8>,
+}
+
+impl<'a> Serializer<'a> {
+    fn str(&mut self, s: &str) {
+        encode_len(s.len(), 0xa0, 32, [0xd9, 0xda, 0xdb], self.out);
+        self.out.extend_from_slice(s.as_bytes());
+    }
+
+    /// Start an array or map; without a length the header is written with
+    /// 32 bits and patched by Compound::end
+    fn header<'s>(&'s mut self, len: Option<usize>, fix: u8, markers: [u8; 2]) -> Compound<'s, 'a> {
+        let patch = match len {
+            Some(len) => {
+                encode_len(len, fix, 16, [0, markers[0], markers[1]], self.out);
+                None
+            }
+            None => {
+                let at = self.out.len();
+                self.out.extend_from_slice(&[markers[1], 0, 0, 0, 0]);
+                Some(at)
+            }
+        };
+        Compound { ser: self, patch, count: 0 }
+    }
+
+    fn array(&mut self, len: Option<usize>) -> Compound<'_, 'a> {
+        self.header(len, 0x90, [0xdc, 0xdd])
+    }
+
+    fn map(&mut self, len: Option<usize>) -> Compound<'_, 'a> {
+        self.header(len, 0x80, [0xde, 0xdf])
+    }
+}
+
+impl<'s, 'a> ser::Serializer for &'s mut Serializer<'a> {
+    type Ok = ();
+    type Error = Error;
+    type SerializeSeq = Compound<'s, 'a>;
+    type SerializeTuple = Compound<'s, 'a>;
+    type SerializeTupleStruct = Compound<'s, 'a>;
+    type SerializeTupleVariant = Compound<'s, 'a>;
+    type SerializeMap = Compound<'s, 'a>;
+    type SerializeStruct = Compound<'s, 'a>;
+    type SerializeStructVariant = Compound<'s, 'a>;
+
+    fn serialize_bool(self, v: bool) -> Result<()> {
+        self.out.push(if v { 0xc3 } else { 0xc2 });
+        Ok(())
+    }
+
+    fn serialize_i8(self, v: i8) -> Result<()> {
+        self.serialize_i64(v as i64)
+    }
+
+    fn serialize_i16(self, v: i16) -> Result<()> {
+        self.serialize_i64(v as i64)
+    }
+
+    fn serialize_i32(self, v: i32) -> Result<()> {
+        self.serialize_i64(v as i64)
+    }
+
+    fn serialize_i64(self, v: i64) -> Result<()> {
+        encode_int(v, self.out);
+        Ok(())
+    }
+
+    fn serialize_u8(self, v: u8) -> Result<()> {
+        self.serialize_u64(v as u64)
+    }
+
+    fn serialize_u16(self, v: u16) -> Result<()> {
+        self.serialize_u64(v as u64)
+    }
+
+    fn serialize_u32(self, v: u32) -> Result<()> {
+        self.serialize_u64(v as u64)
+    }
+
+    fn serialize_u64(self, v: u64) -> Result<()> {
+        encode_uint(v, self.out);
+        Ok(())
+    }
+
+    fn serialize_f32(self, v: f32) -> Result<()> {
+        self.serialize_f64(v as f64)
+    }
+
+    fn serialize_f64(self, v: f64) -> Result<()> {
+        if v.is_finite() {
+            self.out.push(0xcb);
+            self.out.extend_from_slice(&v.to_be_bytes());
+        } else {
+            // Like serde_json, which writes NaN and infinities as null
+            self.out.push(0xc0);
+        }
+        Ok(())
+    }
+
+    fn serialize_char(self, v: char) -> Result<()> {
+        self.str(v.encode_utf8(&mut [0; 4]));
+        Ok(())
+    }
+
+    fn serialize_str(self, v: &str) -> Result<()> {
+        self.str(v);
+        Ok(())
+    }
+
+    /// Bytes are an array of integers, as in JSON
+    fn serialize_bytes(self, v: &[u8]) -> Result<()> {
+        encode_len(v.len(), 0x90, 16, [0, 0xdc, 0xdd], self.out);
+        for &b in v {
+            encode_uint(b as u64, self.out);
+        }
+        Ok(())
+    }
+
+    fn serialize_none(self) -> Result<()> {
+        self.serialize_unit()
+    }
+
+    fn serialize_some<T: Serialize + ?Sized>(self, value: &T) -> Result<()> {
+        value.serialize(self)
+    }
+
+    fn serialize_unit(self) -> Result<()> {
+        self.out.push(0xc0);
+        Ok(())
+    }
+
+    fn serialize_unit_struct(self, _name: &'static str) -> Result<()> {
+        self.serialize_unit()
+    }
+
+    fn serialize_unit_variant(self, _name: &'static str, _index: u32, variant: &'static str) -> Result<()> {
+        self.str(variant);
+        Ok(())
+    }
+
+    fn serialize_newtype_struct<T: Serialize + ?Sized>(self, _name: &'static str, value: &T) -> Result<()> {
+        value.serialize(self)
+    }
+
+    fn serialize_newtype_variant<T: Serialize + ?Sized>(
+        self,
+        _name: &'static str,
+        _index: u32,
+        variant: &'static str,
+        value: &T,
+    ) -> Result<()> {
+        self.out.push(0x81);
+        self.str(variant);
+        value.serialize(self)
+    }
+
+    fn serialize_seq(self, len: Option<usize>) -> Result<Compound<'s, 'a>> {
+        Ok(self.array(len))
+    }
+
+    fn serialize_tuple(self, len: usize) -> Result<Compound<'s, 'a>> {
+        Ok(self.array(Some(len)))
+    }
+
+    fn serialize_tuple_struct(self, _name: &'static str, len: usize) -> Result<Compound<'s, 'a>> {
+        Ok(self.array(Some(len)))
+    }
+
+    fn serialize_tuple_variant(
+        self,
+        _name: &'static str,
+        _index: u32,
+        variant: &'static str,
+        len: usize,
+    ) -> Result<Compound<'s, 'a>> {
+        self.out.push(0x81);
+        self.str(variant);
+        Ok(self.array(Some(len)))
+    }
+
+    fn serialize_map(self, len: Option<usize>) -> Result<Compound<'s, 'a>> {
+        Ok(self.map(len))
+    }
+
+    fn serialize_struct(self, _name: &'static str, len: usize) -> Result<Compound<'s, 'a>> {
+        Ok(self.map(Some(len)))
+    }
+
+    fn serialize_struct_variant(
+        self,
+        _name: &'static str,
+        _index: u32,
+        variant: &'static str,
+        len: usize,
+    ) -> Result<Compound<'s, 'a>> {
+        self.out.push(0x81);
+        self.str(variant);
+        Ok(self.map(Some(len)))
+    }
+}
+
+/// An array or map being written
+struct Compound<'s, 'a> {
+    ser: &'s mut Serializer<'a>,
+    /// Where the header to patch with the count starts, for unknown lengths
+    patch: Option<usize>,
+    count: u32,
+}
+
+impl Compound<'_, '_> {
+    fn element<T: Serialize + ?Sized>(&mut self, value: &T) -> Result<()> {
+        self.count += 1;
+        value.serialize(&mut *self.ser)
+    }
+
+    fn field<T: Serialize + ?Sized>(&mut self, key: &str, value: &T) -> Result<()> {
+        self.count += 1;
+        self.ser.str(key);
+        value.serialize(&mut *self.ser)
+    }
+
+    fn finish(self) -> Result<()> {
+        if let Some(at) = self.patch {
+            self.ser.out[at + 1..at + 5].copy_from_slice(&self.count.to_be_bytes());
+        }
+        Ok(())
+    }
+}
+
+impl ser::SerializeSeq for Compound<'_, '_> {
+    type Ok = ();
+    type Error = Error;
+
+    fn serialize_element<T: Serialize + ?Sized>(&mut self, value: &T) -> Result<()> {
+        self.element(value)
+    }
+
+    fn end(self) -> Result<()> {
+        self.finish()
+    }
+}
+
+impl ser::SerializeTuple for Compound<'_, '_> {
+    type Ok = ();
+    type Error = Error;
+
+    fn serialize_element<T: Serialize + ?Sized>(&mut self, value: &T) -> Result<()> {
+        self.element(value)
+    }
+
+    fn end(self) -> Result<()> {
+        self.finish()
+    }
+}
+
+impl ser::SerializeTupleStruct for Compound<'_, '_> {
+    type Ok = ();
+    type Error = Error;
+
+    fn serialize_field<T: Serialize + ?Sized>(&mut self, value: &T) -> Result<()> {
+        self.element(value)
+    }
+
+    fn end(self) -> Result<()> {
+        self.finish()
+    }
+}
+
+impl ser::SerializeTupleVariant for Compound<'_, '_> {
+    type Ok = ();
+    type Error = Error;
+
+    fn serialize_field<T: Serialize + ?Sized>(&mut self, value: &T) -> Result<()> {
+        self.element(value)
+    }
+
+    fn end(self) -> Result<()> {
+        self.finish()
+    }
+}
+
+impl ser::SerializeMap for Compound<'_, '_> {
+    type Ok = ();
+    type Error = Error;
+
+    fn serialize_key<T: Serialize + ?Sized>(&mut self, key: &T) -> Result<()> {
+        let at = self.ser.out.len();
+        self.element(key)?;
+        if !is_str(self.ser.out[at]) {
+            return Err(Error::codec("map key must be a string"));
+        }
+        Ok(())
+    }
+
+    fn serialize_value<T: Serialize + ?Sized>(&mut self, value: &T) -> Result<()> {
+        value.serialize(&mut *self.ser)
+    }
+
+    fn end(self) -> Result<()> {
+        self.finish()
+    }
+}
+
+impl ser::SerializeStruct for Compound<'_, '_> {
+    type Ok = ();
+    type Error = Error;
+
+    fn serialize_field<T: Serialize + ?Sized>(&mut self, key: &'static str, value: &T) -> Result<()> {
+        self.field(key, value)
+    }
+
+    fn end(self) -> Result<()> {
+        self.finish()
+    }
+}
+
+impl ser::SerializeStructVariant for Compound<'_, '_> {
+    type Ok = ();
+    type Error = Error;
+
+    fn serialize_field<T: Serialize + ?Sized>(&mut self, key: &'static str, value: &T) -> Result<()> {
+        self.field(key, value)
+    }
+
+    fn end(self) -> Result<()> {
+        self.finish()
+    }
+}
+
+fn encode_uint(u: u64, out: &mut Vec<u8>) {
+    if u < 0x80 {
+        out.push(u as u8);
+    } else if u <= u8::MAX as u64 {
+        out.extend_from_slice(&[0xcc, u as u8]);
+    } else if u <= u16::MAX as u64 {
+        out.push(0xcd);
+        out.extend_from_slice(&(u as u16).to_be_bytes());
+    } else if u <= u32::MAX as u64 {
+        out.push(0xce);
+        out.extend_from_slice(&(u as u32).to_be_bytes());
+    } else {
+        out.push(0xcf);
+        out.extend_from_slice(&u.to_be_bytes());
+    }
+}
+
+fn encode_int(i: i64, out: &mut Vec<u8>) {
+    if i >= 0 {
+        encode_uint(i as u64, out);
+    } else if i >= -32 {
+        out.push(i as u8);
+    } else if i >= i8::MIN as i64 {
+        out.extend_from_slice(&[0xd0, i as u8]);
+    } else if i >= i16::MIN as i64 {
+        out.push(0xd1);
+        out.extend_from_slice(&(i as i16).to_be_bytes());
+    } else if i >= i32::MIN as i64 {
+        out.push(0xd2);
+        out.extend_from_slice(&(i as i32).to_be_bytes());
+    } else {
+        out.push(0xd3);
+        out.extend_from_slice(&i.to_be_bytes());
+    }
+}
+
+/// Write a length header: the fix form below `fix_limit`, then the 8, 16
+/// and 32-bit forms (a 0 marker means the type has no 8-bit form)
+fn encode_len(len: usize, fix: u8, fix_limit: usize, markers: [u8; 3], out: &mut Vec<u8>) {
+    if len < fix_limit {
+        out.push(fix | len as u8);
+    } else if markers[0] != 0 && len <= u8::MAX as usize {
+        out.extend_from_slice(&[markers[0], len as u8]);
+    } else if len <= u16::MAX as usize {
+        out.push(markers[1]);
+        out.extend_from_slice(&(len as u16).to_be_bytes());
+    } else {
+        out.push(markers[2]);
+        out.extend_from_slice(&(len as u32).to_be_bytes());
+    }
+}
+
+fn is_str(marker: u8) -> bool {
+    matches!(marker, 0xa0..=0xbf | 0xd9..=0xdb)
+}
+
+// ============================================================
+// DECODING
+// ============================================================
+
+/// Decode a single MessagePack value spanning the whole input. Strings are
+/// borrowed from the input where the type allows it.
+pub fn from_slice<'de, T: Deserialize<'de>>(input: &'de [u8]) -> Result<T> {
+    let mut de = Deserializer { input, pos: 0, depth: 0 };
+    let value = T::deserialize(&mut de)?;
+    if de.pos != input.len() {
+        return Err(Error::codec(format!("{} trailing bytes", input.len() - de.pos)));
+    }
+    Ok(value)
+}
+
+struct Deserializer<'de> {
+    input: &'de [u8],
+    pos: usize,
+    depth: usize,
+}
+
+impl<'de> Deserializer<'de> {
+    fn take(&mut self, n: usize) -> Result<&'de [u8]> {
+        if self.input.len() - self.pos < n {
+            return Err(Error::codec("unexpected end of input"));
+        }
+        let bytes = &self.input[self.pos..self.pos + n];
+        self.pos += n;
+        Ok(bytes)
+    }
+
+    fn peek(&self) -> Result<u8> {
+        self.input
+            .get(self.pos)
+            .copied()
+            .ok_or_else(|| Error::codec("unexpected end of input"))
+    }
+
+    fn byte(&mut self) -> Result<u8> {
+        Ok(self.take(1)?[0])
+    }
+
+    fn be<const N: usize>(&mut self) -> Result<[u8; N]> {
+        let mut buf = [0u8; N];
+        buf.copy_from_slice(self.take(N)?);
+        Ok(buf)
+    }
+
+    fn len(&mut self, size: usize) -> Result<usize> {
+        Ok(match size {
+            1 => self.byte()? as usize,
+            2 => u16::from_be_bytes(self.be()?) as usize,
+            _ => u32::from_be_bytes(self.be()?) as usize,
+        })
+    }
+
+    fn str(&mut self, n: usize) -> Result<&'de str> {
+        let bytes = self.take(n)?;
+        std::str::from_utf8(bytes).map_err(|e| Error::codec(format!("invalid UTF-8: {}", e)))
+    }
+
+    /// Read the header of a map, None if the next value is not one
+    fn map_len(&mut self) -> Result<Option<usize>> {
+        let n = match self.peek()? {
+            marker @ 0x80..=0x8f => {
+                self.pos += 1;
+                (marker & 0x0f) as usize
+            }
+            0xde => {
+                self.pos += 1;
+                self.len(2)?
+            }
+            0xdf => {
+                self.pos += 1;
+                self.len(4)?
+            }
+            _ => return Ok(None),
+        };
+        Ok(Some(n))
+    }
+
+    /// Run `read` one nesting level deeper
+    fn nested<T>(&mut self, read: impl FnOnce(&mut Self) -> Result<T>) -> Result<T> {
+        if self.depth == MAX_DEPTH {
+            return Err(Error::codec("nesting too deep"));
+        }
+        self.depth += 1;
+        let result = read(self);
+        self.depth -= 1;
+        result
+    }
+
+    fn seq<V: Visitor<'de>>(&mut self, n: usize, visitor: V) -> Result<V::Value> {
+        self.nested(|de| {
+            let mut access = Access { de, remaining: n };
+            let value = visitor.visit_seq(&mut access)?;
+            if access.remaining > 0 {
+                return Err(Error::codec(format!("{} array items left over", access.remaining)));
+            }
+            Ok(value)
+        })
+    }
+
+    fn map<V: Visitor<'de>>(&mut self, n: usize, visitor: V) -> Result<V::Value> {
+        self.nested(|de| {
+            let mut access = Access { de, remaining: n };
+            let value = visitor.visit_map(&mut access)?;
+            if access.remaining > 0 {
+                return Err(Error::codec(format!("{} map entries left over", access.remaining)));
+            }
+            Ok(value)
+        })
+    }
+}
+
+impl<'de> de::Deserializer<'de> for &mut Deserializer<'de> {
+    type Error = Error;
+
+    fn deserialize_any<V: Visitor<'de>>(self, visitor: V) -> Result<V::Value> {
+        let marker = self.byte()?;
+        match marker {
+            0x00..=0x7f => visitor.visit_u64(marker as u64),
+            0xe0..=0xff => visitor.visit_i64(marker as i8 as i64),
+            0xc0 => visitor.visit_unit(),
+            0xc2 => visitor.visit_bool(false),
+            0xc3 => visitor.visit_bool(true),
+            0xcc => visitor.visit_u64(self.byte()? as u64),
+            0xcd => visitor.visit_u64(u16::from_be_bytes(self.be()?) as u64),
+            0xce => visitor.visit_u64(u32::from_be_bytes(self.be()?) as u64),
+            0xcf => visitor.visit_u64(u64::from_be_bytes(self.be()?)),
+            0xd0 => visitor.visit_i64(self.byte()? as i8 as i64),
+            0xd1 => visitor.visit_i64(i16::from_be_bytes(self.be()?) as i64),
+            0xd2 => visitor.visit_i64(i32::from_be_bytes(self.be()?) as i64),
+            0xd3 => visitor.visit_i64(i64::from_be_bytes(self.be()?)),
+            0xca => visitor.visit_f64(f32::from_be_bytes(self.be()?) as f64),
+            0xcb => visitor.visit_f64(f64::from_be_bytes(self.be()?)),
+            0xa0..=0xbf => visitor.visit_borrowed_str(self.str((marker & 0x1f) as usize)?),
+            0xd9 => {
+                let n = self.len(1)?;
+                visitor.visit_borrowed_str(self.str(n)?)
+            }
+            0xda => {
+                let n = self.len(2)?;
+                visitor.visit_borrowed_str(self.str(n)?)
+            }
+            0xdb => {
+                let n = self.len(4)?;
+                visitor.visit_borrowed_str(self.str(n)?)
+            }
+            0x90..=0x9f => self.seq((marker & 0x0f) as usize, visitor),
+            0xdc => {
+                let n = self.len(2)?;
+                self.seq(n, visitor)
+            }
+            0xdd => {
+                let n = self.len(4)?;
+                self.seq(n, visitor)
+            }
+            0x80..=0x8f => self.map((marker & 0x0f) as usize, visitor),
+            0xde => {
+                let n = self.len(2)?;
+                self.map(n, visitor)
+            }
+            0xdf => {
+                let n = self.len(4)?;
+                self.map(n, visitor)
+            }
+            _ => Err(Error::codec(format!("unsupported type 0x{:02x}", marker))),
+        }
+    }
+
+    fn deserialize_option<V: Visitor<'de>>(self, visitor: V) -> Result<V::Value> {
+        if self.peek()? == 0xc0 {
+            self.pos += 1;
+            visitor.visit_none()
+        } else {
+            visitor.visit_some(self)
+        }
+    }
+
+    fn deserialize_newtype_struct<V: Visitor<'de>>(self, _name: &'static str, visitor: V) -> Result<V::Value> {
+        visitor.visit_newtype_struct(self)
+    }
+
+    /// A unit variant is its name, any other variant a map {name: content}
+    fn deserialize_enum<V: Visitor<'de>>(
+        self,
+        _name: &'static str,
+        _variants: &'static [&'static str],
+        visitor: V,
+    ) -> Result<V::Value> {
+        if is_str(self.peek()?) {
+            let variant: &'de str = Deserialize::deserialize(&mut *self)?;
+            return visitor.visit_enum(variant.into_deserializer());
+        }
+        match self.map_len()? {
+            Some(1) => self.nested(|de| visitor.visit_enum(Variant { de })),
+            _ => Err(Error::codec("expected an enum: a string or a map with one key")),
+        }
+    }
+
+    serde::forward_to_deserialize_any! {
+        bool i8 i16 i32 i64 i128 u8 u16 u32 u64 u128 f32 f64 char str string
+        bytes byte_buf unit unit_struct seq tuple tuple_struct map struct
+        identifier ignored_any
+    }
+}
+
+/// The items of an array or the entries of a map
+struct Access<'a, 'de> {
+    de: &'a mut Deserializer<'de>,
+    remaining: usize,
+}
+
+impl<'de> de::SeqAccess<'de> for Access<'_, 'de> {
+    type Error = Error;
+
+    fn next_element_seed<T: DeserializeSeed<'de>>(&mut self, seed: T) -> Result<Option<T::Value>> {
+        if self.remaining == 0 {
+            return Ok(None);
+        }
+        self.remaining -= 1;
+        seed.deserialize(&mut *self.de).map(Some)
+    }
+
+    fn size_hint(&self) -> Option<usize> {
+        // Every item takes at least one byte
+        Some(self.remaining.min(self.de.input.len() - self.de.pos))
+    }
+}
+
+impl<'de> de::MapAccess<'de> for Access<'_, 'de> {
+    type Error = Error;
+
+    fn next_key_seed<K: DeserializeSeed<'de>>(&mut self, seed: K) -> Result<Option<K::Value>> {
+        if self.remaining == 0 {
+            return Ok(None);
+        }
+        self.remaining -= 1;
+        if !is_str(self.de.peek()?) {
+            return Err(Error::codec("map key must be a string"));
+        }
+        seed.deserialize(&mut *self.de).map(Some)
+    }
+
+    fn next_value_seed<V: DeserializeSeed<'de>>(&mut self, seed: V) -> Result<V::Value> {
+        seed.deserialize(&mut *self.de)
+    }
+
+    fn size_hint(&self) -> Option<usize> {
+        // Every entry takes at least two bytes
+        Some(self.remaining.min((self.de.input.len() - self.de.pos) / 2))
+    }
+}
+
+/// The {name: content} map of an enum variant, past its header
+struct Variant<'a, 'de> {
+    de: &'a mut Deserializer<'de>,
+}
+
+impl<'a, 'de> de::EnumAccess<'de> for Variant<'a, 'de> {
+    type Error = Error;
+    type Variant = Self;
+
+    fn variant_seed<V: DeserializeSeed<'de>>(self, seed: V) -> Result<(V::Value, Self)> {
+        if !is_str(self.de.peek()?) {
+            return Err(Error::codec("map key must be a string"));
+        }
+        let variant = seed.deserialize(&mut *self.de)?;
+        Ok((variant, self))
+    }
+}
+
+impl<'de> de::VariantAccess<'de> for Variant<'_, 'de> {
+    type Error = Error;
+
+    /// {name: nil}, which JSON accepts for a unit variant too
+    fn unit_variant(self) -> Result<()> {
+        Deserialize::deserialize(self.de)
+    }
+
+    fn newtype_variant_seed<T: DeserializeSeed<'de>>(self, seed: T) -> Result<T::Value> {
+        seed.deserialize(self.de)
+    }
+
+    fn tuple_variant<V: Visitor<'de>>(self, _len: usize, visitor: V) -> Result<V::Value> {
+        de::Deserializer::deserialize_any(self.de, visitor)
+    }
+
+    fn struct_variant<V: Visitor<'de>>(self, _fields: &'static [&'static str], visitor: V) -> Result<V::Value> {
+        de::Deserializer::deserialize_any(self.de, visitor)
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::query::{ComparisonOp, FilterExpr, FilterValue, Query};
+    use serde_json::{json, Value};
+
+    fn encode<T: Serialize>(value: &T) -> Vec<u8> {
+        let mut out = Vec::new();
+        to_writer(&mut out, value).unwrap();
+        out
+    }
+
+    fn roundtrip(value: Value) {
+        assert_eq!(from_slice::<Value>(&encode(&value)).unwrap(), value);
+    }
+
+    #[test]
+    fn test_roundtrip() {
+        roundtrip(json!(null));
+        roundtrip(json!([true, false, 0, 127, 128, 255, 256, 65536, 4294967296u64, u64::MAX]));
+        roundtrip(json!([-1, -32, -33, -128, -129, -32768, -32769, -2147483649i64, i64::MIN]));
+        roundtrip(json!([1.5, -0.25, 1e300]));
+        roundtrip(json!(["", "héllo", "x".repeat(31), "x".repeat(32), "x".repeat(300), "x".repeat(70000)]));
+        roundtrip(json!({"entity": "User", "filters": [{"Condition": {"value": {"Param": 1}}}], "limit": null}));
+        roundtrip(Value::Array(vec![json!(1); 20]));
+        roundtrip(Value::Object((0..20).map(|i| (i.to_string(), json!(i))).collect()));
+    }
+
+    #[test]
+    fn test_encoding_is_compact() {
+        assert_eq!(encode(&json!({"a": [1, "b"]})), vec![0x81, 0xa1, b'a', 0x92, 0x01, 0xa1, b'b']);
+    }
+
+    #[test]
+    fn test_typed_roundtrip() {
+        let query = Query::new("User")
+            .filter(FilterExpr::condition("email", ComparisonOp::Eq, FilterValue::Param(1)))
+            .filter(FilterExpr::condition("age", ComparisonOp::Gte, FilterValue::Float(-1.5)))
+            .filter(FilterExpr::condition("name", ComparisonOp::Eq, FilterValue::Null))
+            .include("orders")
+            .limit(300);
+
+        // Same shape as the JSON form, read straight into the query
+        let bytes = encode(&query);
+        assert_eq!(from_slice::<Value>(&bytes).unwrap(), serde_json::to_value(&query).unwrap());
+        assert_eq!(from_slice::<Query>(&bytes).unwrap(), query);
+
+        // A unit variant in map form, as JSON accepts it
+        let null = encode(&json!({"Null": null}));
+        assert_eq!(from_slice::<FilterValue>(&null).unwrap(), FilterValue::Null);
+
+        // Lengths unknown up front are patched in
+        let pairs: std::collections::BTreeMap<_, _> = (0..3).map(|i| (i.to_string(), i)).collect();
+        let mut out = Vec::new();
+        ser::Serializer::collect_map(&mut Serializer { out: &mut out }, &pairs).unwrap();
+        assert_eq!(from_slice::<Value>(&out).unwrap(), json!({"0": 0, "1": 1, "2": 2}));
+    }
+
+    #[test]
+    fn test_decode_errors() {
+        let decode = |bytes: &[u8]| from_slice::<Value>(bytes).unwrap_err().to_string();
+        assert!(decode(&[]).contains("end of input"));
+        assert!(decode(&[0xa5, b'a']).contains("end of input"));
+        assert!(decode(&[0x01, 0x02]).contains("trailing"));
+        assert!(decode(&[0x81, 0x01, 0x01]).contains("map key"));
+        assert!(decode(&[0xc4, 0x00]).contains("unsupported"));
+        assert!(decode(&[0xa1, 0xff]).contains("UTF-8"));
+        assert!(decode(&[0x91; 200]).contains("too deep"));
+        assert!(decode(&[0xdd, 0xff, 0xff, 0xff, 0xff]).contains("end of input"));
+
+        // Type errors come from the serde definitions, as with JSON
+        let err = from_slice::<Query>(&encode(&json!({"entity": "User"}))).unwrap_err();
+        assert!(err.to_string().contains("missing field `filters`"), "{}", err);
+        assert!(from_slice::<FilterValue>(&encode(&json!({"Int": 1, "Bool": true}))).is_err());
+
+        let mut out = Vec::new();
+        let err = to_writer(&mut out, &std::collections::HashMap::from([(1, 2)])).unwrap_err();
+        assert!(err.to_string().contains("map key"));
+    }
+}
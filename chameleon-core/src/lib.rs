@@ -22,5 +22,20 @@ pub use ffi::{
     chameleon_validate_schema,
     chameleon_free_string,
     chameleon_version,
+    chameleon_abi_version,
+    chameleon_capabilities,
+    chameleon_schema_load,
+    chameleon_schema_free,
+    chameleon_schema_generate_sql,
+    chameleon_schema_generate_migration,
+    chameleon_schema_generate_mutation_sql,
+    chameleon_schema_generate_sql_encoded,
+    chameleon_schema_generate_mutation_sql_encoded,
+    chameleon_buffer_free,
     ChameleonResult,
+    ChameleonSchema,
+    CHAMELEON_ABI_VERSION,
+    CHAMELEON_ENCODING_JSON,
+    CHAMELEON_ENCODING_MSGPACK,
+    ChameleonBuffer,
 };
\ No newline at end of file
@@ -1,4 +1,4 @@
-use crate::ast::{Schema, Entity, RelationKind};
+use crate::ast::{Schema, Entity, Field, FieldConstraint, RelationKind};
 use crate::sql::naming::entity_to_table;
 use super::type_map::{to_postgres_type, to_postgres_default};
 
@@ -68,6 +68,18 @@ fn generate_create_table(entity: &Entity, schema: &Schema) -> Result<String, Mig
         columns.push(col);
     }
 
+    // CHECK constraints from field constraints, sorted for stable output
+    let mut constrained: Vec<&Field> = entity.fields.values()
+        .filter(|f| !f.constraints.is_empty())
+        .collect();
+    constrained.sort_by(|a, b| a.name.cmp(&b.name));
+    for field in constrained {
+        constraints.extend(check_constraints(&table_name, field));
+    }
+
+    // Join entities hold one row per link (makes Connect idempotent)
+    constraints.extend(link_unique_constraints(&table_name, entity, schema));
+
     // Foreign key constraints from HasMany relations in OTHER entities
     // that point TO this entity
     for other_entity in &schema.entities {
@@ -115,6 +127,78 @@ fn generate_create_table(entity: &Entity, schema: &Schema) -> Result<String, Mig
     ))
 }
 
+/// Render a UNIQUE constraint on the key pair of every ManyToMany relation
+/// going through this entity. Both sides of a relation (Post.tags and
+/// Tag.posts) share one constraint; columns are sorted for stable names.
+///
+///   CONSTRAINT post_tags_post_id_tag_id_key UNIQUE (post_id, tag_id)
+fn link_unique_constraints(table_name: &str, entity: &Entity, schema: &Schema) -> Vec<String> {
+    let mut pairs: Vec<(String, String)> = Vec::new();
+
+    for other in &schema.entities {
+        let mut relations: Vec<_> = other.relations.values()
+            .filter(|r| r.kind == RelationKind::ManyToMany && r.through.as_deref() == Some(entity.name.as_str()))
+            .collect();
+        relations.sort_by(|a, b| a.name.cmp(&b.name));
+
+        for relation in relations {
+            if let Some((source, target)) = schema.resolve_through_keys(&other.name, relation) {
+                let pair = if source < target { (source, target) } else { (target, source) };
+                if !pairs.contains(&pair) {
+                    pairs.push(pair);
+                }
+            }
+        }
+    }
+
+    pairs.into_iter()
+        .map(|(a, b)| format!("    CONSTRAINT {}_{}_{}_key UNIQUE ({}, {})", table_name, a, b, a, b))
+        .collect()
+}
+
+/// Render the field constraints of a column as named CHECK constraints
+///
+///   CONSTRAINT users_age_min CHECK (age >= 0)
+///   CONSTRAINT users_name_length CHECK (char_length(name) BETWEEN 1 AND 255)
+///   CONSTRAINT users_code_pattern CHECK (code ~ '^[A-Z]+$')
+fn check_constraints(table_name: &str, field: &Field) -> Vec<String> {
+    let column = &field.name;
+    let mut checks = Vec::new();
+    let mut checks_seen = 0;
+
+    for constraint in &field.constraints {
+        let (suffix, expr) = match constraint {
+            FieldConstraint::Min(bound) => ("min".to_string(), format!("{} >= {}", column, bound)),
+            FieldConstraint::Max(bound) => ("max".to_string(), format!("{} <= {}", column, bound)),
+            FieldConstraint::Length { min, max } => {
+                let expr = match (min, max) {
+                    (Some(lo), Some(hi)) => format!("char_length({}) BETWEEN {} AND {}", column, lo, hi),
+                    (Some(lo), None) => format!("char_length({}) >= {}", column, lo),
+                    (None, Some(hi)) => format!("char_length({}) <= {}", column, hi),
+                    (None, None) => continue,
+                };
+                ("length".to_string(), expr)
+            }
+            FieldConstraint::Pattern(pattern) => (
+                "pattern".to_string(),
+                format!("{} ~ '{}'", column, pattern.replace('\'', "''")),
+            ),
+            FieldConstraint::Check(expr) => {
+                checks_seen += 1;
+                let suffix = if checks_seen == 1 { "check".to_string() } else { format!("check_{}", checks_seen) };
+                (suffix, expr.clone())
+            }
+        };
+
+        checks.push(format!(
+            "    CONSTRAINT {}_{}_{} CHECK ({})",
+            table_name, column, suffix, expr
+        ));
+    }
+
+    checks
+}
+
 /// Resolve entity creation order using topological sort
 /// Entities referenced by FKs must be created first
 fn resolve_creation_order(schema: &Schema) -> Result<Vec<String>, MigrationError> {
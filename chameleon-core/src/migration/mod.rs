@@ -19,30 +19,35 @@ mod tests {
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: true,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         user.add_field(Field {
             name: "email".to_string(),
             field_type: FieldType::String,
             nullable: false, unique: true, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         user.add_field(Field {
             name: "name".to_string(),
             field_type: FieldType::String,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         user.add_field(Field {
             name: "age".to_string(),
             field_type: FieldType::Int,
             nullable: true, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         user.add_field(Field {
             name: "created_at".to_string(),
             field_type: FieldType::Timestamp,
             nullable: false, unique: false, primary_key: false,
             default: Some(DefaultValue::Now), backend: None,
+            constraints: Vec::new(),
         });
         user.add_relation(Relation {
             name: "orders".to_string(),
@@ -60,24 +65,28 @@ mod tests {
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: true,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         order.add_field(Field {
             name: "total".to_string(),
             field_type: FieldType::Decimal,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         order.add_field(Field {
             name: "status".to_string(),
             field_type: FieldType::String,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         order.add_field(Field {
             name: "user_id".to_string(),
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         order.add_relation(Relation {
             name: "user".to_string(),
@@ -102,24 +111,28 @@ mod tests {
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: true,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         item.add_field(Field {
             name: "quantity".to_string(),
             field_type: FieldType::Int,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         item.add_field(Field {
             name: "price".to_string(),
             field_type: FieldType::Decimal,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         item.add_field(Field {
             name: "order_id".to_string(),
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         item.add_relation(Relation {
             name: "order".to_string(),
@@ -170,12 +183,14 @@ mod tests {
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: true,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         entity.add_field(Field {
             name: "email".to_string(),
             field_type: FieldType::String,
             nullable: false, unique: true, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         schema.add_entity(entity);
 
@@ -196,12 +211,14 @@ mod tests {
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: true,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         entity.add_field(Field {
             name: "age".to_string(),
             field_type: FieldType::Int,
             nullable: true, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         schema.add_entity(entity);
 
@@ -221,12 +238,14 @@ mod tests {
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: true,
             default: Some(DefaultValue::UUIDv4), backend: None,
+            constraints: Vec::new(),
         });
         entity.add_field(Field {
             name: "created_at".to_string(),
             field_type: FieldType::Timestamp,
             nullable: false, unique: false, primary_key: false,
             default: Some(DefaultValue::Now), backend: None,
+            constraints: Vec::new(),
         });
         schema.add_entity(entity);
 
@@ -303,18 +322,21 @@ mod tests {
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: true,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         entity.add_field(Field {
             name: "views".to_string(),
             field_type: FieldType::Int,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: Some(BackendAnnotation::Cache),
+            constraints: Vec::new(),
         });
         entity.add_field(Field {
             name: "sales".to_string(),
             field_type: FieldType::Decimal,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: Some(BackendAnnotation::OLAP),
+            constraints: Vec::new(),
         });
         schema.add_entity(entity);
 
@@ -327,4 +349,100 @@ mod tests {
         assert!(migration.sql.contains("views INTEGER NOT NULL"));
         assert!(migration.sql.contains("sales NUMERIC NOT NULL"));
     }
-}
\ No newline at end of file
+
+    // ─── FIELD CONSTRAINTS → CHECK ───
+
+    #[test]
+    fn test_check_constraints() {
+        let mut schema = Schema::new();
+        let mut entity = Entity::new("Product".to_string());
+        entity.add_field(Field {
+            name: "id".to_string(),
+            field_type: FieldType::UUID,
+            nullable: false, unique: false, primary_key: true,
+            default: None, backend: None,
+            constraints: Vec::new(),
+        });
+        entity.add_field(Field {
+            name: "price".to_string(),
+            field_type: FieldType::Decimal,
+            nullable: false, unique: false, primary_key: false,
+            default: None, backend: None,
+            constraints: vec![
+                FieldConstraint::Min("0".to_string()),
+                FieldConstraint::Max("9999.99".to_string()),
+                FieldConstraint::Check("price <> 13".to_string()),
+            ],
+        });
+        entity.add_field(Field {
+            name: "code".to_string(),
+            field_type: FieldType::String,
+            nullable: true, unique: false, primary_key: false,
+            default: None, backend: None,
+            constraints: vec![
+                FieldConstraint::Length { min: Some(3), max: None },
+                FieldConstraint::Pattern("^[A-Z]+'s$".to_string()),
+            ],
+        });
+        schema.add_entity(entity);
+
+        let sql = generate_migration(&schema).unwrap().sql;
+
+        assert!(sql.contains("CONSTRAINT products_price_min CHECK (price >= 0)"), "{}", sql);
+        assert!(sql.contains("CONSTRAINT products_price_max CHECK (price <= 9999.99)"), "{}", sql);
+        assert!(sql.contains("CONSTRAINT products_price_check CHECK (price <> 13)"), "{}", sql);
+        assert!(sql.contains("CONSTRAINT products_code_length CHECK (char_length(code) >= 3)"), "{}", sql);
+        assert!(sql.contains("CONSTRAINT products_code_pattern CHECK (code ~ '^[A-Z]+''s$')"), "{}", sql);
+
+        // Sorted by field: code before price
+        assert!(sql.find("products_code_length").unwrap() < sql.find("products_price_min").unwrap());
+    }
+
+    // ─── JOIN ENTITIES ───
+
+    #[test]
+    fn test_join_entity_unique_key() {
+        let mut schema = Schema::new();
+        let field = |name: &str, primary_key: bool| Field {
+            name: name.to_string(),
+            field_type: FieldType::UUID,
+            nullable: false, unique: false, primary_key,
+            default: None, backend: None,
+            constraints: Vec::new(),
+        };
+        let relation = |name: &str, kind: RelationKind, target: &str, through: Option<&str>| Relation {
+            name: name.to_string(),
+            kind,
+            target_entity: target.to_string(),
+            foreign_key: None,
+            through: through.map(|t| t.to_string()),
+        };
+
+        let mut post = Entity::new("Post".to_string());
+        post.add_field(field("id", true));
+        post.add_relation(relation("tags", RelationKind::ManyToMany, "Tag", Some("PostTag")));
+        schema.add_entity(post);
+
+        let mut tag = Entity::new("Tag".to_string());
+        tag.add_field(field("id", true));
+        tag.add_relation(relation("posts", RelationKind::ManyToMany, "Post", Some("PostTag")));
+        schema.add_entity(tag);
+
+        // Surrogate key: the pair of foreign keys is not unique by itself
+        let mut post_tag = Entity::new("PostTag".to_string());
+        post_tag.add_field(field("id", true));
+        post_tag.add_field(field("post_id", false));
+        post_tag.add_field(field("tag_id", false));
+        post_tag.add_relation(relation("post", RelationKind::BelongsTo, "Post", None));
+        post_tag.add_relation(relation("tag", RelationKind::BelongsTo, "Tag", None));
+        schema.add_entity(post_tag);
+
+        let migration = generate_migration(&schema).unwrap();
+        let post_tags = &migration.statements.iter().find(|(name, _)| name == "PostTag").unwrap().1;
+
+        // One constraint for both sides of the relation
+        let constraint = "CONSTRAINT post_tags_post_id_tag_id_key UNIQUE (post_id, tag_id)";
+        assert!(post_tags.contains(constraint), "{}", post_tags);
+        assert_eq!(migration.sql.matches("UNIQUE (").count(), 1, "{}", migration.sql);
+    }
+}
@@ -202,6 +202,7 @@ mod tests {
             primary_key: true,
             default: None,
             backend: None,
+            constraints: Vec::new(),
         });
         user.add_field(Field {
             name: "email".to_string(),
@@ -211,6 +212,7 @@ mod tests {
             primary_key: false,
             default: None,
             backend: None,
+            constraints: Vec::new(),
         });
         user.add_field(Field {
             name: "name".to_string(),
@@ -220,6 +222,7 @@ mod tests {
             primary_key: false,
             default: None,
             backend: None,
+            constraints: Vec::new(),
         });
         user.add_field(Field {
             name: "age".to_string(),
@@ -229,6 +232,7 @@ mod tests {
             primary_key: false,
             default: None,
             backend: None,
+            constraints: Vec::new(),
         });
         schema.add_entity(user);
 
@@ -170,7 +170,7 @@ fn add_suggestions(mut detail: ParseErrorDetail) -> ParseErrorDetail {
 #[cfg(test)]
 mod tests {
     use super::*;
-    use crate::ast::{BackendAnnotation, FieldType};
+    use crate::ast::{BackendAnnotation, FieldConstraint, FieldType, RelationKind};
     use pretty_assertions::assert_eq;
 
     #[test]
@@ -218,6 +218,47 @@ mod tests {
         assert_eq!(orders_rel.target_entity, "Order");
     }
 
+    #[test]
+    fn test_many_to_many_relation() {
+        let input = r#"
+            entity Post {
+                id: uuid primary,
+                tags: [Tag] through PostTag,
+            }
+        "#;
+
+        let schema = parse_schema(input).unwrap();
+        let tags = schema.get_entity("Post").unwrap().relations.get("tags").unwrap();
+        assert_eq!(tags.kind, RelationKind::ManyToMany);
+        assert_eq!(tags.target_entity, "Tag");
+        assert_eq!(tags.through.as_deref(), Some("PostTag"));
+        assert!(tags.foreign_key.is_none());
+    }
+
+    #[test]
+    fn test_through_as_name() {
+        let input = r#"
+            entity Trip {
+                id: uuid primary,
+                through: string,
+                stops: [Stop] through through,
+            }
+
+            entity Stop {
+                id: uuid primary,
+                through: Trip,
+            }
+        "#;
+
+        let schema = parse_schema(input).unwrap();
+        let trip = schema.get_entity("Trip").unwrap();
+        assert_eq!(trip.fields.get("through").unwrap().field_type, FieldType::String);
+        assert_eq!(trip.relations.get("stops").unwrap().through.as_deref(), Some("through"));
+
+        let stop = schema.get_entity("Stop").unwrap();
+        assert_eq!(stop.relations.get("through").unwrap().kind, RelationKind::BelongsTo);
+    }
+
     #[test]
 fn test_backend_annotations() {
     let input = r#"
@@ -312,4 +353,79 @@ fn test_complex_multi_backend_schema() {
     assert_eq!(product.fields.get("embedding").unwrap().field_type, FieldType::Vector(384));
     assert_eq!(product.fields.get("tags").unwrap().field_type, FieldType::Array(Box::new(FieldType::String)));
 }
-}
\ No newline at end of file
+
+#[test]
+fn test_field_constraints() {
+    let input = r#"
+        entity Product {
+            id: uuid primary,
+            name: string length(1..255),
+            code: string nullable length(..8) pattern("^[A-Z]+$"),
+            price: decimal min(0.01) max(9999.99),
+            stock: int min(-10) check("stock % 2 = 0"),
+            length: int min(0),
+        }
+    "#;
+
+    let schema = parse_schema(input).unwrap();
+    let product = schema.get_entity("Product").unwrap();
+
+    assert_eq!(product.fields.get("name").unwrap().constraints,
+        vec![FieldConstraint::Length { min: Some(1), max: Some(255) }]);
+    assert_eq!(product.fields.get("code").unwrap().constraints, vec![
+        FieldConstraint::Length { min: None, max: Some(8) },
+        FieldConstraint::Pattern("^[A-Z]+$".to_string()),
+    ]);
+    assert!(product.fields.get("code").unwrap().nullable);
+    assert_eq!(product.fields.get("price").unwrap().constraints, vec![
+        FieldConstraint::Min("0.01".to_string()),
+        FieldConstraint::Max("9999.99".to_string()),
+    ]);
+    assert_eq!(product.fields.get("stock").unwrap().constraints, vec![
+        FieldConstraint::Min("-10".to_string()),
+        FieldConstraint::Check("stock % 2 = 0".to_string()),
+    ]);
+
+    // Constraint keywords remain valid field names
+    assert_eq!(product.fields.get("length").unwrap().field_type, FieldType::Int);
+}
+
+#[test]
+fn test_constraint_keywords_as_names() {
+    let input = r#"
+        entity Reading {
+            id: uuid primary,
+            min: decimal min(-0.5) max(100),
+            max: decimal nullable,
+            check: string check("check <> ''"),
+            pattern: string pattern("^[a-z]+$") length(1..),
+            length: int,
+            sensor: Sensor,
+        }
+
+        entity Sensor {
+            id: uuid primary,
+            min: [Reading] via sensor_id,
+        }
+    "#;
+
+    let schema = parse_schema(input).unwrap();
+    let reading = schema.get_entity("Reading").unwrap();
+
+    assert_eq!(reading.fields.get("min").unwrap().constraints, vec![
+        FieldConstraint::Min("-0.5".to_string()),
+        FieldConstraint::Max("100".to_string()),
+    ]);
+    assert!(reading.fields.get("max").unwrap().nullable);
+    assert_eq!(reading.fields.get("check").unwrap().constraints,
+        vec![FieldConstraint::Check("check <> ''".to_string())]);
+    assert_eq!(reading.fields.get("pattern").unwrap().constraints, vec![
+        FieldConstraint::Pattern("^[a-z]+$".to_string()),
+        FieldConstraint::Length { min: Some(1), max: None },
+    ]);
+    assert_eq!(reading.fields.get("length").unwrap().field_type, FieldType::Int);
+
+    let sensor = schema.get_entity("Sensor").unwrap();
+    assert_eq!(sensor.relations.get("min").unwrap().foreign_key.as_deref(), Some("sensor_id"));
+}
+}
@@ -1,5 +1,5 @@
 use serde::{Deserialize, Serialize};
-use super::filter::FilterExpr;
+use super::filter::{FilterExpr, FieldPath};
 
 /// Sort direction
 #[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
@@ -21,12 +21,80 @@ pub struct OrderByClause {
 #[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
 pub struct IncludePath {
     pub path: Vec<String>,
+    /// Fields to load for the last relation (empty = all fields)
+    #[serde(default)]
+    pub fields: Vec<String>,
 }
 
 impl IncludePath {
     pub fn parse(path: &str) -> Self {
         IncludePath {
             path: path.split('.').map(|s| s.to_string()).collect(),
+            fields: Vec::new(),
+        }
+    }
+}
+
+/// Aggregate functions
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub enum AggregateFunc {
+    Count,
+    Sum,
+    Avg,
+    Min,
+    Max,
+}
+
+/// A single aggregate: func(field)
+/// field is None only for COUNT of the root entity
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct Aggregate {
+    pub func: AggregateFunc,
+    pub field: Option<FieldPath>,
+}
+
+impl Aggregate {
+    pub fn new(func: AggregateFunc, field: Option<&str>) -> Self {
+        Aggregate {
+            func,
+            field: field.map(FieldPath::parse),
+        }
+    }
+
+    /// Result column name (mirrored by aggregateAlias in pkg/engine/aggregate.go)
+    /// Count → "count", Sum("orders.total") → "sum_orders_total"
+    pub fn alias(&self) -> String {
+        let func = match self.func {
+            AggregateFunc::Count => "count",
+            AggregateFunc::Sum => "sum",
+            AggregateFunc::Avg => "avg",
+            AggregateFunc::Min => "min",
+            AggregateFunc::Max => "max",
+        };
+        match &self.field {
+            Some(field) => format!("{}_{}", func, field.segments.join("_")),
+            None => func.to_string(),
+        }
+    }
+}
+
+/// A LIMIT or OFFSET: a count, or a bound parameter ({"Param": n}) whose
+/// value is sent with the query, so every page shares one statement
+#[derive(Debug, Clone, Copy, PartialEq, Serialize, Deserialize)]
+#[serde(untagged)]
+pub enum RowCount {
+    Count(u64),
+    Param {
+        #[serde(rename = "Param")]
+        param: usize,
+    },
+}
+
+impl std::fmt::Display for RowCount {
+    fn fmt(&self, f: &mut std::fmt::Formatter<'_>) -> std::fmt::Result {
+        match self {
+            RowCount::Count(n) => write!(f, "{}", n),
+            RowCount::Param { param } => write!(f, "${}", param),
         }
     }
 }
@@ -38,6 +106,10 @@ pub struct Query {
     /// Target entity name (e.g., "User")
     pub entity: String,
 
+    /// Fields to select (empty = all fields)
+    #[serde(default)]
+    pub select: Vec<String>,
+
     /// Filter conditions (combined with AND by default)
     pub filters: Vec<FilterExpr>,
 
@@ -48,10 +120,22 @@ pub struct Query {
     pub order_by: Vec<OrderByClause>,
 
     /// Maximum number of results
-    pub limit: Option<u64>,
+    pub limit: Option<RowCount>,
 
     /// Number of results to skip
-    pub offset: Option<u64>,
+    pub offset: Option<RowCount>,
+
+    /// Aggregates to compute instead of returning rows
+    #[serde(default)]
+    pub aggregates: Vec<Aggregate>,
+
+    /// Fields to group aggregates by ("status", "user.email")
+    #[serde(default)]
+    pub group_by: Vec<FieldPath>,
+
+    /// Conditions on aggregates (fields name an aggregate alias or a group field)
+    #[serde(default)]
+    pub having: Vec<FilterExpr>,
 }
 
 impl Query {
@@ -59,26 +143,49 @@ impl Query {
     pub fn new(entity: &str) -> Self {
         Query {
             entity: entity.to_string(),
+            select: Vec::new(),
             filters: Vec::new(),
             includes: Vec::new(),
             order_by: Vec::new(),
             limit: None,
             offset: None,
+            aggregates: Vec::new(),
+            group_by: Vec::new(),
+            having: Vec::new(),
         }
     }
 
+    /// Returns true if this query computes aggregates instead of rows
+    pub fn is_aggregate(&self) -> bool {
+        !self.aggregates.is_empty() || !self.group_by.is_empty()
+    }
+
     /// Add a filter condition
     pub fn filter(mut self, expr: FilterExpr) -> Self {
         self.filters.push(expr);
         self
     }
 
+    /// Select only some fields
+    pub fn select(mut self, fields: &[&str]) -> Self {
+        self.select.extend(fields.iter().map(|f| f.to_string()));
+        self
+    }
+
     /// Add an include path
     pub fn include(mut self, path: &str) -> Self {
         self.includes.push(IncludePath::parse(path));
         self
     }
 
+    /// Add an include path loading only some fields of the relation
+    pub fn include_fields(mut self, path: &str, fields: &[&str]) -> Self {
+        let mut include = IncludePath::parse(path);
+        include.fields = fields.iter().map(|f| f.to_string()).collect();
+        self.includes.push(include);
+        self
+    }
+
     /// Add an order-by clause
     pub fn order_by(mut self, field: &str, direction: SortDirection) -> Self {
         self.order_by.push(OrderByClause {
@@ -90,13 +197,31 @@ impl Query {
 
     /// Set limit
     pub fn limit(mut self, n: u64) -> Self {
-        self.limit = Some(n);
+        self.limit = Some(RowCount::Count(n));
         self
     }
 
     /// Set offset
     pub fn offset(mut self, n: u64) -> Self {
-        self.offset = Some(n);
+        self.offset = Some(RowCount::Count(n));
+        self
+    }
+
+    /// Add an aggregate
+    pub fn aggregate(mut self, func: AggregateFunc, field: Option<&str>) -> Self {
+        self.aggregates.push(Aggregate::new(func, field));
+        self
+    }
+
+    /// Add a group-by field
+    pub fn group_by(mut self, field: &str) -> Self {
+        self.group_by.push(FieldPath::parse(field));
+        self
+    }
+
+    /// Add a condition on aggregates
+    pub fn having(mut self, expr: FilterExpr) -> Self {
+        self.having.push(expr);
         self
     }
 }
\ No newline at end of file
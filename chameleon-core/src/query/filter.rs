@@ -8,6 +8,8 @@ pub enum FilterValue {
     Float(f64),
     Bool(bool),
     Null,
+    /// Bound parameter: rendered as `$n`, the value is sent with the query
+    Param(usize),
 }
 
 /// Comparison operators
@@ -68,7 +70,7 @@ pub struct FilterCondition {
 }
 
 /// A filter expression tree
-/// Supports combining conditions with AND/OR
+/// Supports combining conditions with AND/OR and negating groups
 #[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
 pub enum FilterExpr {
     /// A single condition: field op value
@@ -79,6 +81,8 @@ pub enum FilterExpr {
         op: LogicalOp,
         right: Box<FilterExpr>,
     },
+    /// Negates an expression: NOT (expr)
+    Not(Box<FilterExpr>),
 }
 
 impl FilterExpr {
@@ -108,4 +112,9 @@ impl FilterExpr {
             right: Box::new(other),
         }
     }
+
+    /// Negate
+    pub fn not(self) -> Self {
+        FilterExpr::Not(Box::new(self))
+    }
 }
\ No newline at end of file
@@ -1,7 +1,7 @@
 pub mod ast;
 pub mod filter;
 
-pub use ast::{Query, IncludePath, OrderByClause, SortDirection};
+pub use ast::{Query, IncludePath, OrderByClause, SortDirection, Aggregate, AggregateFunc, RowCount};
 pub use filter::{FilterExpr, FilterValue, ComparisonOp, LogicalOp, FieldPath, FilterCondition};
 
 #[cfg(test)]
@@ -84,6 +84,14 @@ mod tests {
         assert!(matches!(combined, FilterExpr::Binary { op: LogicalOp::Or, .. }));
     }
 
+    #[test]
+    fn test_not_filter() {
+        let f = FilterExpr::condition("status", ComparisonOp::Eq, FilterValue::String("cancelled".to_string()));
+        let negated = f.clone().not();
+
+        assert_eq!(negated, FilterExpr::Not(Box::new(f)));
+    }
+
     // ─── QUERY BUILDER ───
 
     #[test]
@@ -145,8 +153,8 @@ mod tests {
             .limit(10)
             .offset(20);
 
-        assert_eq!(query.limit, Some(10));
-        assert_eq!(query.offset, Some(20));
+        assert_eq!(query.limit, Some(RowCount::Count(10)));
+        assert_eq!(query.offset, Some(RowCount::Count(20)));
     }
 
     #[test]
@@ -172,8 +180,8 @@ mod tests {
         assert_eq!(query.filters.len(), 2);
         assert_eq!(query.includes.len(), 2);
         assert_eq!(query.order_by.len(), 1);
-        assert_eq!(query.limit, Some(10));
-        assert_eq!(query.offset, Some(0));
+        assert_eq!(query.limit, Some(RowCount::Count(10)));
+        assert_eq!(query.offset, Some(RowCount::Count(0)));
     }
 
     // ─── SERIALIZATION ───
@@ -199,4 +207,15 @@ mod tests {
         let restored: Query = serde_json::from_str(&json).unwrap();
         assert_eq!(query, restored);
     }
+
+    #[test]
+    fn test_row_count_serialization() {
+        let json = r#"{"entity":"User","filters":[],"includes":[],"order_by":[],
+            "limit":{"Param":2},"offset":40}"#;
+
+        let query: Query = serde_json::from_str(json).unwrap();
+        assert_eq!(query.limit, Some(RowCount::Param { param: 2 }));
+        assert_eq!(query.offset, Some(RowCount::Count(40)));
+        assert_eq!(serde_json::to_value(query.limit).unwrap(), serde_json::json!({"Param": 2}));
+    }
 }
\ No newline at end of file
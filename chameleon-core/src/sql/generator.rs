@@ -1,8 +1,9 @@
 use crate::ast::Schema;
 use crate::query::{
-    Query, FilterExpr, FilterCondition, FilterValue,
-    ComparisonOp, LogicalOp, SortDirection,
+    Query, FilterExpr, FilterCondition, FilterValue, FieldPath,
+    ComparisonOp, LogicalOp, SortDirection, Aggregate, AggregateFunc, RowCount,
 };
+use crate::ast::RelationKind;
 use super::naming::entity_to_table;
 use serde::{Deserialize, Serialize};
 
@@ -13,11 +14,15 @@ use serde::{Deserialize, Serialize};
 pub struct GeneratedSQL {
     /// The main SELECT query
     pub main_query: String,
-    /// Eager loading queries (one per include level)
-    /// Each tuple is (relation_name, sql)
+    /// Eager loading queries (one per include level, parents first)
+    /// Each tuple is (include_path, sql), e.g. ("orders.items", ...)
     pub eager_queries: Vec<(String, String)>,
 }
 
+/// Column alias carrying the parent key on ManyToMany eager rows
+/// (mirrored by throughParentKey in pkg/engine/eager.go)
+pub const THROUGH_PARENT_KEY: &str = "__parent_key";
+
 /// Generate SQL from a Query + Schema
 pub fn generate_sql(query: &Query, schema: &Schema) -> Result<GeneratedSQL, SqlGenError> {
     let entity = schema.get_entity(&query.entity)
@@ -25,15 +30,44 @@ pub fn generate_sql(query: &Query, schema: &Schema) -> Result<GeneratedSQL, SqlG
 
     let table_name = entity_to_table(&query.entity);
 
+    // Aggregates replace the row query entirely
+    if query.is_aggregate() {
+        if !query.includes.is_empty() {
+            return Err(SqlGenError::InvalidAggregate(
+                "includes cannot be combined with aggregates".to_string(),
+            ));
+        }
+        if !query.select.is_empty() {
+            return Err(SqlGenError::InvalidAggregate(
+                "select cannot be combined with aggregates".to_string(),
+            ));
+        }
+        return Ok(GeneratedSQL {
+            main_query: build_aggregate_query(query, &table_name, entity, schema)?,
+            eager_queries: Vec::new(),
+        });
+    }
+
     // Determine if we need JOINs (filters on relations)
     let join_filters = extract_join_filters(query);
     let needs_join = !join_filters.is_empty();
 
+    // Selected fields plus the keys eager loading reads from root rows
+    let mut keys = eager_parent_keys(&query.entity, &[], &query.includes, schema)?;
+
+    // DISTINCT over joined rows must still tell entities apart, and Postgres
+    // only orders a DISTINCT result by columns it selects
+    if needs_join {
+        keys.push(entity.primary_key());
+        keys.extend(query.order_by.iter().map(|o| o.field.clone()));
+    }
+    let columns = project_columns(&query.entity, entity, &query.select, &keys)?;
+
     // Build main query
     let main_query = build_main_query(
         &table_name,
         &query.entity,
-        entity,
+        &columns,
         &query.filters,
         &join_filters,
         needs_join,
@@ -56,40 +90,34 @@ pub fn generate_sql(query: &Query, schema: &Schema) -> Result<GeneratedSQL, SqlG
     })
 }
 
-/// Extract filters that target relations (e.g., "orders.total")
+/// Extract top-level filters that target relations (e.g., "orders.total")
+///
+/// Only plain conditions are joined. Relation conditions inside
+/// OR / NOT groups become EXISTS subqueries instead, since an INNER JOIN
+/// would drop rows without related records and change the group's meaning.
 fn extract_join_filters(query: &Query) -> Vec<&FilterExpr> {
     query.filters.iter()
-        .filter(|f| filter_expr_is_nested(f))
+        .filter(|f| matches!(f, FilterExpr::Condition(cond) if cond.field.is_nested()))
         .collect()
 }
 
-/// Check if a filter expression references a nested path
-fn filter_expr_is_nested(expr: &FilterExpr) -> bool {
-    match expr {
-        FilterExpr::Condition(cond) => cond.field.is_nested(),
-        FilterExpr::Binary { left, right, .. } => {
-            filter_expr_is_nested(left) || filter_expr_is_nested(right)
-        }
-    }
-}
-
 /// Build the main SELECT query
 fn build_main_query(
     table_name: &str,
     entity_name: &str,
-    entity: &crate::ast::Entity,
+    columns: &[String],
     filters: &[FilterExpr],
     join_filters: &[&FilterExpr],
     needs_join: bool,
     order_by: &[crate::query::OrderByClause],
-    limit: Option<u64>,
-    offset: Option<u64>,
+    limit: Option<RowCount>,
+    offset: Option<RowCount>,
     schema: &Schema,
 ) -> Result<String, SqlGenError> {
     let mut parts: Vec<String> = Vec::new();
 
     // SELECT
-    let columns = build_select_columns(table_name, entity, needs_join);
+    let columns = build_select_columns(table_name, columns, needs_join);
     let distinct = if needs_join { "DISTINCT " } else { "" };
     parts.push(format!("SELECT {}{}", distinct, columns));
 
@@ -127,9 +155,352 @@ fn build_main_query(
     Ok(parts.join("\n"))
 }
 
+/// Build an aggregate query
+///
+///   SELECT users.name, COUNT(*) AS count, SUM(users.age) AS sum_age,
+///          SUM(orders.sum_orders_total) AS sum_orders_total
+///   FROM users
+///   LEFT JOIN (SELECT orders.user_id AS __parent_key, SUM(orders.total) AS sum_orders_total
+///              FROM orders GROUP BY orders.user_id) AS orders ON orders.__parent_key = users.id
+///   GROUP BY users.name
+///
+/// Relations referenced by aggregates or group fields are LEFT JOINed, so
+/// entities without related rows still count. To-many relations that are
+/// only aggregated are joined pre-aggregated, one row per parent (see
+/// plan_aggregate_joins), and relation filters become EXISTS subqueries:
+/// neither multiplies the aggregated rows.
+fn build_aggregate_query(
+    query: &Query,
+    table_name: &str,
+    entity: &crate::ast::Entity,
+    schema: &Schema,
+) -> Result<String, SqlGenError> {
+    let entity_name = query.entity.as_str();
+    let mut parts: Vec<String> = Vec::new();
+
+    // Relations to join for aggregate and group fields
+    let joins = plan_aggregate_joins(query, entity)?;
+
+    // SELECT group fields, then aggregates
+    let mut columns = Vec::new();
+    let mut group_columns = Vec::new();
+    for field in &query.group_by {
+        let column = field_to_sql(field, table_name, true, schema, entity_name)?;
+        if field.is_nested() {
+            // Keep the path as the result key: users.email AS "user.email"
+            columns.push(format!("{} AS \"{}\"", column, field.segments.join(".")));
+        } else {
+            columns.push(column.clone());
+        }
+        group_columns.push(column);
+    }
+
+    for aggregate in &query.aggregates {
+        let expr = aggregate_to_sql(aggregate, table_name, &joins, entity, schema, entity_name)?;
+        columns.push(format!("{} AS {}", expr, aggregate.alias()));
+    }
+    parts.push(format!("SELECT {}", columns.join(", ")));
+
+    // FROM + JOINs
+    parts.push(format!("FROM {}", table_name));
+    if !joins.joined.is_empty() {
+        parts.push(build_relation_joins(entity_name, &joins.joined, "LEFT JOIN", schema)?);
+    }
+    for rel_name in &joins.rolled_up {
+        parts.push(rolled_up_join(rel_name, query, table_name, entity, schema)?);
+    }
+
+    // WHERE (relation conditions always as EXISTS)
+    if !query.filters.is_empty() {
+        let conditions: Vec<String> = query.filters.iter()
+            .map(|f| filter_expr_to_sql(f, table_name, true, schema, entity_name, true))
+            .collect::<Result<Vec<_>, _>>()?;
+        parts.push(format!("WHERE {}", conditions.join(" AND ")));
+    }
+
+    // GROUP BY
+    if !group_columns.is_empty() {
+        parts.push(format!("GROUP BY {}", group_columns.join(", ")));
+    }
+
+    // HAVING
+    if !query.having.is_empty() {
+        let conditions: Vec<String> = query.having.iter()
+            .map(|h| having_expr_to_sql(h, query, table_name, &joins, entity, schema))
+            .collect::<Result<Vec<_>, _>>()?;
+        parts.push(format!("HAVING {}", conditions.join(" AND ")));
+    }
+
+    // ORDER BY (aggregate aliases or fields)
+    if !query.order_by.is_empty() {
+        let clauses: Vec<String> = query.order_by.iter()
+            .map(|o| {
+                let field = if query.aggregates.iter().any(|a| a.alias() == o.field) {
+                    o.field.clone()
+                } else {
+                    field_to_sql(&FieldPath::parse(&o.field), table_name, true, schema, entity_name)?
+                };
+                let dir = match o.direction {
+                    SortDirection::Asc  => "ASC",
+                    SortDirection::Desc => "DESC",
+                };
+                Ok(format!("{} {}", field, dir))
+            })
+            .collect::<Result<Vec<_>, SqlGenError>>()?;
+        parts.push(format!("ORDER BY {}", clauses.join(", ")));
+    }
+
+    if let Some(limit) = query.limit {
+        parts.push(format!("LIMIT {}", limit));
+    }
+    if let Some(offset) = query.offset {
+        parts.push(format!("OFFSET {}", offset));
+    }
+
+    Ok(parts.join("\n"))
+}
+
+/// Relations of an aggregate query, by how they are joined
+struct AggregateJoins {
+    /// Joined row by row: to-one relations, and to-many ones grouped on
+    joined: Vec<String>,
+    /// To-many relations only aggregated: one pre-aggregated row per parent
+    rolled_up: Vec<String>,
+}
+
+/// Sort the relations of an aggregate query into joins
+///
+/// Grouping by a to-many relation repeats every other value once per
+/// related row, so SUM, AVG and COUNT(field) are only accepted over that
+/// relation's own fields (COUNT, MIN and MAX are not affected).
+fn plan_aggregate_joins(query: &Query, entity: &crate::ast::Entity) -> Result<AggregateJoins, SqlGenError> {
+    let mut relations: Vec<&str> = query.aggregates.iter()
+        .filter_map(|a| a.field.as_ref())
+        .chain(query.group_by.iter())
+        .filter(|f| f.is_nested())
+        .map(|f| f.root())
+        .collect();
+    relations.sort();
+    relations.dedup();
+
+    let mut joins = AggregateJoins { joined: Vec::new(), rolled_up: Vec::new() };
+    let mut fanned_out = Vec::new();
+    for rel_name in relations {
+        let relation = entity.relations.get(rel_name)
+            .ok_or_else(|| SqlGenError::UnknownRelation {
+                entity: query.entity.clone(),
+                relation: rel_name.to_string(),
+            })?;
+        let to_many = matches!(relation.kind, RelationKind::HasMany | RelationKind::ManyToMany);
+        let grouped = query.group_by.iter().any(|f| f.is_nested() && f.root() == rel_name);
+
+        if to_many && !grouped {
+            joins.rolled_up.push(rel_name.to_string());
+        } else {
+            if to_many {
+                fanned_out.push(rel_name);
+            }
+            joins.joined.push(rel_name.to_string());
+        }
+    }
+
+    for aggregate in &query.aggregates {
+        let repeats_count = match aggregate.func {
+            AggregateFunc::Sum | AggregateFunc::Avg => true,
+            AggregateFunc::Count => aggregate.field.is_some(),
+            AggregateFunc::Min | AggregateFunc::Max => false,
+        };
+        let own_relation = aggregate.field.as_ref()
+            .filter(|f| f.is_nested())
+            .map(|f| f.root());
+        let other = fanned_out.iter().find(|rel| Some(**rel) != own_relation);
+
+        if let (true, Some(rel_name)) = (repeats_count, other) {
+            return Err(SqlGenError::InvalidAggregate(format!(
+                "{} cannot be combined with grouping by '{}': each {} would be counted once per related row",
+                aggregate.alias(), rel_name, query.entity,
+            )));
+        }
+    }
+
+    Ok(joins)
+}
+
+/// Convert an aggregate to its SQL expression
+/// COUNT of the root entity counts distinct keys once relations are joined
+fn aggregate_to_sql(
+    aggregate: &Aggregate,
+    table_name: &str,
+    joins: &AggregateJoins,
+    entity: &crate::ast::Entity,
+    schema: &Schema,
+    entity_name: &str,
+) -> Result<String, SqlGenError> {
+    let field = match &aggregate.field {
+        Some(field) if field.is_nested() && joins.rolled_up.iter().any(|r| r == field.root()) => {
+            let relation = &entity.relations[field.root()];
+            return Ok(rolled_up_to_sql(aggregate, &entity_to_table(&relation.target_entity)));
+        }
+        Some(field) => field_to_sql(field, table_name, true, schema, entity_name)?,
+        None if aggregate.func == AggregateFunc::Count => {
+            return Ok(if !joins.joined.is_empty() {
+                format!("COUNT(DISTINCT {}.{})", table_name, entity.primary_key())
+            } else {
+                "COUNT(*)".to_string()
+            });
+        }
+        None => {
+            return Err(SqlGenError::InvalidAggregate(
+                format!("{:?} requires a field", aggregate.func),
+            ));
+        }
+    };
+
+    let func = match aggregate.func {
+        AggregateFunc::Count => "COUNT",
+        AggregateFunc::Sum => "SUM",
+        AggregateFunc::Avg => "AVG",
+        AggregateFunc::Min => "MIN",
+        AggregateFunc::Max => "MAX",
+    };
+    Ok(format!("{}({})", func, field))
+}
+
+/// LEFT JOIN a to-many relation as one row per parent, holding the
+/// relation's aggregates (combined by rolled_up_to_sql)
+///
+///   LEFT JOIN (SELECT orders.user_id AS __parent_key, SUM(orders.total) AS sum_orders_total
+///              FROM orders GROUP BY orders.user_id) AS orders ON orders.__parent_key = users.id
+fn rolled_up_join(
+    rel_name: &str,
+    query: &Query,
+    table_name: &str,
+    entity: &crate::ast::Entity,
+    schema: &Schema,
+) -> Result<String, SqlGenError> {
+    let entity_name = query.entity.as_str();
+    let relation = entity.relations.get(rel_name)
+        .ok_or_else(|| SqlGenError::UnknownRelation {
+            entity: entity_name.to_string(),
+            relation: rel_name.to_string(),
+        })?;
+    let target = schema.get_entity(&relation.target_entity)
+        .ok_or_else(|| SqlGenError::UnknownEntity(relation.target_entity.clone()))?;
+    let target_table = entity_to_table(&relation.target_entity);
+
+    let missing_fk = || SqlGenError::MissingForeignKey {
+        entity: entity_name.to_string(),
+        relation: rel_name.to_string(),
+    };
+
+    let (parent_key, from) = if relation.kind == RelationKind::ManyToMany {
+        let (source_key, target_key) = schema.resolve_through_keys(entity_name, relation)
+            .ok_or_else(missing_fk)?;
+        let join_table = entity_to_table(relation.through.as_deref().unwrap_or_default());
+        (
+            format!("{}.{}", join_table, source_key),
+            format!(
+                "{} INNER JOIN {} ON {}.{} = {}.{}",
+                join_table, target_table, target_table, target.primary_key(), join_table, target_key
+            ),
+        )
+    } else {
+        let fk = schema.resolve_foreign_key(entity_name, relation)
+            .ok_or_else(missing_fk)?;
+        (format!("{}.{}", target_table, fk), target_table.clone())
+    };
+
+    let mut columns = vec![format!("{} AS {}", parent_key, THROUGH_PARENT_KEY)];
+    for aggregate in &query.aggregates {
+        let field = match &aggregate.field {
+            Some(field) if field.is_nested() && field.root() == rel_name => field,
+            _ => continue,
+        };
+        let column = format!("{}.{}", target_table, field.segments[1]);
+        let alias = aggregate.alias();
+
+        let parts = match aggregate.func {
+            // AVG is not combinable: carry sum and count
+            AggregateFunc::Avg => vec![
+                format!("SUM({}) AS {}_sum", column, alias),
+                format!("COUNT({}) AS {}_count", column, alias),
+            ],
+            AggregateFunc::Count => vec![format!("COUNT({}) AS {}", column, alias)],
+            AggregateFunc::Sum => vec![format!("SUM({}) AS {}", column, alias)],
+            AggregateFunc::Min => vec![format!("MIN({}) AS {}", column, alias)],
+            AggregateFunc::Max => vec![format!("MAX({}) AS {}", column, alias)],
+        };
+        for part in parts {
+            if !columns.contains(&part) {
+                columns.push(part);
+            }
+        }
+    }
+
+    Ok(format!(
+        "LEFT JOIN (SELECT {} FROM {} GROUP BY {}) AS {} ON {}.{} = {}.{}",
+        columns.join(", "), from, parent_key,
+        target_table, target_table, THROUGH_PARENT_KEY, table_name, entity.primary_key()
+    ))
+}
+
+/// Combine the per-parent values of a rolled-up relation (see rolled_up_join)
+fn rolled_up_to_sql(aggregate: &Aggregate, target_table: &str) -> String {
+    let column = format!("{}.{}", target_table, aggregate.alias());
+    match aggregate.func {
+        AggregateFunc::Count => format!("COALESCE(SUM({}), 0)", column),
+        AggregateFunc::Sum => format!("SUM({})", column),
+        AggregateFunc::Avg => format!("SUM({c}_sum)::numeric / NULLIF(SUM({c}_count), 0)", c = column),
+        AggregateFunc::Min => format!("MIN({})", column),
+        AggregateFunc::Max => format!("MAX({})", column),
+    }
+}
+
+/// Convert a HAVING expression to SQL
+/// Conditions on an aggregate alias ("count", "sum_total") compare the
+/// aggregate expression; anything else is treated as a group field.
+fn having_expr_to_sql(
+    expr: &FilterExpr,
+    query: &Query,
+    table_name: &str,
+    joins: &AggregateJoins,
+    entity: &crate::ast::Entity,
+    schema: &Schema,
+) -> Result<String, SqlGenError> {
+    let entity_name = query.entity.as_str();
+    match expr {
+        FilterExpr::Condition(cond) => {
+            let aggregate = query.aggregates.iter()
+                .find(|a| !cond.field.is_nested() && a.alias() == cond.field.root());
+            let field_sql = match aggregate {
+                Some(aggregate) => aggregate_to_sql(aggregate, table_name, joins, entity, schema, entity_name)?,
+                None => field_to_sql(&cond.field, table_name, true, schema, entity_name)?,
+            };
+            Ok(comparison_to_sql(&field_sql, cond))
+        }
+        FilterExpr::Binary { left, op, right } => {
+            let left_sql = having_expr_to_sql(left, query, table_name, joins, entity, schema)?;
+            let right_sql = having_expr_to_sql(right, query, table_name, joins, entity, schema)?;
+            let op_sql = match op {
+                LogicalOp::And => "AND",
+                LogicalOp::Or => "OR",
+            };
+            Ok(format!("({} {} {})", left_sql, op_sql, right_sql))
+        }
+        FilterExpr::Not(inner) => {
+            let inner_sql = having_expr_to_sql(inner, query, table_name, joins, entity, schema)?;
+            if matches!(**inner, FilterExpr::Binary { .. }) {
+                Ok(format!("NOT {}", inner_sql))
+            } else {
+                Ok(format!("NOT ({})", inner_sql))
+            }
+        }
+    }
+}
+
 /// Build SELECT column list
-fn build_select_columns(table_name: &str, entity: &crate::ast::Entity, qualify: bool) -> String {
-    let columns: Vec<String> = entity.fields.keys()
+fn build_select_columns(table_name: &str, columns: &[String], qualify: bool) -> String {
+    let columns: Vec<String> = columns.iter()
         .map(|name| {
             if qualify {
                 format!("{}.{}", table_name, name)
@@ -142,14 +513,94 @@ fn build_select_columns(table_name: &str, entity: &crate::ast::Entity, qualify:
     columns.join(", ")
 }
 
+/// Resolve the columns to load for an entity
+///
+/// An empty selection loads every field. Otherwise the selected fields are
+/// validated and the required keys are appended, so eager loading can still
+/// match parents and children.
+fn project_columns(
+    entity_name: &str,
+    entity: &crate::ast::Entity,
+    selected: &[String],
+    required: &[String],
+) -> Result<Vec<String>, SqlGenError> {
+    if selected.is_empty() {
+        return Ok(entity.fields.keys().cloned().collect());
+    }
+
+    let mut columns: Vec<String> = Vec::new();
+    for name in selected.iter().chain(required.iter()) {
+        if !entity.fields.contains_key(name) {
+            return Err(SqlGenError::UnknownField {
+                entity: entity_name.to_string(),
+                field: name.clone(),
+            });
+        }
+        if !columns.contains(name) {
+            columns.push(name.clone());
+        }
+    }
+    Ok(columns)
+}
+
+/// Keys that includes one level below `prefix` read from the rows at `prefix`:
+/// the primary key for HasMany/HasOne/ManyToMany, the FK for BelongsTo
+fn eager_parent_keys(
+    entity_name: &str,
+    prefix: &[String],
+    includes: &[crate::query::IncludePath],
+    schema: &Schema,
+) -> Result<Vec<String>, SqlGenError> {
+    let entity = schema.get_entity(entity_name)
+        .ok_or_else(|| SqlGenError::UnknownEntity(entity_name.to_string()))?;
+
+    let mut keys = Vec::new();
+    for include in includes {
+        if include.path.len() <= prefix.len() || !include.path.starts_with(prefix) {
+            continue;
+        }
+
+        let rel_name = &include.path[prefix.len()];
+        let relation = entity.relations.get(rel_name)
+            .ok_or_else(|| SqlGenError::UnknownRelation {
+                entity: entity_name.to_string(),
+                relation: rel_name.clone(),
+            })?;
+
+        let key = match relation.kind {
+            RelationKind::BelongsTo => schema.resolve_foreign_key(entity_name, relation)
+                .ok_or_else(|| SqlGenError::MissingForeignKey {
+                    entity: entity_name.to_string(),
+                    relation: rel_name.clone(),
+                })?,
+            _ => entity.primary_key(),
+        };
+        if !keys.contains(&key) {
+            keys.push(key);
+        }
+    }
+    Ok(keys)
+}
+
+/// Fields selected for an include path (union of every include naming it)
+fn include_projection(includes: &[crate::query::IncludePath], path: &[String]) -> Vec<String> {
+    let mut fields: Vec<String> = Vec::new();
+    for include in includes.iter().filter(|i| i.path == path) {
+        for field in &include.fields {
+            if !fields.contains(field) {
+                fields.push(field.clone());
+            }
+        }
+    }
+    fields
+}
+
 /// Build JOIN clauses for relation filters
 fn build_joins(
     entity_name: &str,
     join_filters: &[&FilterExpr],
     schema: &Schema,
 ) -> Result<String, SqlGenError> {
-    let entity = schema.get_entity(entity_name).unwrap();
-    let mut joins = Vec::new();
     let mut joined_relations: Vec<String> = Vec::new();
 
     for filter in join_filters {
@@ -160,26 +611,73 @@ fn build_joins(
     joined_relations.sort();
     joined_relations.dedup();
 
-    for rel_name in &joined_relations {
+    build_relation_joins(entity_name, &joined_relations, "INNER JOIN", schema)
+}
+
+/// Build one JOIN per relation of the entity (two for ManyToMany)
+fn build_relation_joins(
+    entity_name: &str,
+    relations: &[String],
+    join_kind: &str,
+    schema: &Schema,
+) -> Result<String, SqlGenError> {
+    let entity = schema.get_entity(entity_name)
+        .ok_or_else(|| SqlGenError::UnknownEntity(entity_name.to_string()))?;
+    let mut joins = Vec::new();
+
+    let source_table = entity_to_table(entity_name);
+    let source_pk = entity.primary_key();
+
+    for rel_name in relations {
         let relation = entity.relations.get(rel_name)
             .ok_or_else(|| SqlGenError::UnknownRelation {
                 entity: entity_name.to_string(),
                 relation: rel_name.clone(),
             })?;
 
+        let target = schema.get_entity(&relation.target_entity)
+            .ok_or_else(|| SqlGenError::UnknownEntity(relation.target_entity.clone()))?;
         let target_table = entity_to_table(&relation.target_entity);
-        let source_table = entity_to_table(entity_name);
 
-        let fk = relation.foreign_key.as_ref()
-            .ok_or_else(|| SqlGenError::MissingForeignKey {
-                entity: entity_name.to_string(),
-                relation: rel_name.clone(),
-            })?;
+        let missing_fk = || SqlGenError::MissingForeignKey {
+            entity: entity_name.to_string(),
+            relation: rel_name.clone(),
+        };
 
-        joins.push(format!(
-            "INNER JOIN {} ON {}.{} = {}.id",
-            target_table, target_table, fk, source_table
-        ));
+        match relation.kind {
+            RelationKind::ManyToMany => {
+                let (source_key, target_key) = schema.resolve_through_keys(entity_name, relation)
+                    .ok_or_else(missing_fk)?;
+                let join_table = entity_to_table(relation.through.as_deref().unwrap_or_default());
+
+                joins.push(format!(
+                    "{} {} ON {}.{} = {}.{}",
+                    join_kind, join_table, join_table, source_key, source_table, source_pk
+                ));
+                joins.push(format!(
+                    "{} {} ON {}.{} = {}.{}",
+                    join_kind, target_table, target_table, target.primary_key(), join_table, target_key
+                ));
+            }
+            RelationKind::BelongsTo => {
+                let fk = schema.resolve_foreign_key(entity_name, relation)
+                    .ok_or_else(missing_fk)?;
+
+                joins.push(format!(
+                    "{} {} ON {}.{} = {}.{}",
+                    join_kind, target_table, target_table, target.primary_key(), source_table, fk
+                ));
+            }
+            RelationKind::HasMany | RelationKind::HasOne => {
+                let fk = schema.resolve_foreign_key(entity_name, relation)
+                    .ok_or_else(missing_fk)?;
+
+                joins.push(format!(
+                    "{} {} ON {}.{} = {}.{}",
+                    join_kind, target_table, target_table, fk, source_table, source_pk
+                ));
+            }
+        }
     }
 
     Ok(joins.join("\n"))
@@ -197,6 +695,7 @@ fn collect_join_relations(expr: &FilterExpr, relations: &mut Vec<String>) {
             collect_join_relations(left, relations);
             collect_join_relations(right, relations);
         }
+        FilterExpr::Not(inner) => collect_join_relations(inner, relations),
     }
 }
 
@@ -213,36 +712,122 @@ fn build_where(
     }
 
     let conditions: Vec<String> = filters.iter()
-        .map(|f| filter_expr_to_sql(f, table_name, qualify, schema, entity_name))
+        .map(|f| filter_expr_to_sql(f, table_name, qualify, schema, entity_name, false))
         .collect::<Result<Vec<_>, _>>()?;
 
     Ok(conditions.join(" AND "))
 }
 
 /// Convert a FilterExpr to SQL
+///
+/// Every group is parenthesized, so precedence always follows the tree:
+///   Or(a, And(b, c)) → (a OR (b AND c))
+///   Not(Or(a, b))    → NOT (a OR b)
 fn filter_expr_to_sql(
     expr: &FilterExpr,
     table_name: &str,
     qualify: bool,
     schema: &Schema,
     entity_name: &str,
+    grouped: bool,
 ) -> Result<String, SqlGenError> {
     match expr {
+        FilterExpr::Condition(cond) if grouped && cond.field.is_nested() => {
+            relation_exists_sql(cond, schema, entity_name)
+        }
         FilterExpr::Condition(cond) => {
             condition_to_sql(cond, table_name, qualify, schema, entity_name)
         }
         FilterExpr::Binary { left, op, right } => {
-            let left_sql = filter_expr_to_sql(left, table_name, qualify, schema, entity_name)?;
-            let right_sql = filter_expr_to_sql(right, table_name, qualify, schema, entity_name)?;
+            let left_sql = filter_expr_to_sql(left, table_name, qualify, schema, entity_name, true)?;
+            let right_sql = filter_expr_to_sql(right, table_name, qualify, schema, entity_name, true)?;
             let op_sql = match op {
                 LogicalOp::And => "AND",
                 LogicalOp::Or => "OR",
             };
             Ok(format!("({} {} {})", left_sql, op_sql, right_sql))
         }
+        FilterExpr::Not(inner) => {
+            let inner_sql = filter_expr_to_sql(inner, table_name, qualify, schema, entity_name, true)?;
+            // Binary groups already carry their own parentheses
+            if matches!(**inner, FilterExpr::Binary { .. }) {
+                Ok(format!("NOT {}", inner_sql))
+            } else {
+                Ok(format!("NOT ({})", inner_sql))
+            }
+        }
     }
 }
 
+/// Convert a relation condition to a correlated EXISTS subquery
+///
+///   orders.total > 100 (User) →
+///   EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id AND orders.total > 100)
+fn relation_exists_sql(
+    cond: &FilterCondition,
+    schema: &Schema,
+    entity_name: &str,
+) -> Result<String, SqlGenError> {
+    let entity = schema.get_entity(entity_name)
+        .ok_or_else(|| SqlGenError::UnknownEntity(entity_name.to_string()))?;
+
+    let rel_name = cond.field.root();
+    let relation = entity.relations.get(rel_name)
+        .ok_or_else(|| SqlGenError::UnknownRelation {
+            entity: entity_name.to_string(),
+            relation: rel_name.to_string(),
+        })?;
+
+    let target = schema.get_entity(&relation.target_entity)
+        .ok_or_else(|| SqlGenError::UnknownEntity(relation.target_entity.clone()))?;
+
+    let source_table = entity_to_table(entity_name);
+    let target_table = entity_to_table(&relation.target_entity);
+
+    let missing_fk = || SqlGenError::MissingForeignKey {
+        entity: entity_name.to_string(),
+        relation: rel_name.to_string(),
+    };
+
+    let (from, correlate) = match relation.kind {
+        RelationKind::ManyToMany => {
+            let (source_key, target_key) = schema.resolve_through_keys(entity_name, relation)
+                .ok_or_else(missing_fk)?;
+            let join_table = entity_to_table(relation.through.as_deref().unwrap_or_default());
+            (
+                format!(
+                    "{} INNER JOIN {} ON {}.{} = {}.{}",
+                    join_table, target_table, target_table, target.primary_key(), join_table, target_key
+                ),
+                format!("{}.{} = {}.{}", join_table, source_key, source_table, entity.primary_key()),
+            )
+        }
+        RelationKind::BelongsTo => {
+            let fk = schema.resolve_foreign_key(entity_name, relation)
+                .ok_or_else(missing_fk)?;
+            (
+                target_table.clone(),
+                format!("{}.{} = {}.{}", target_table, target.primary_key(), source_table, fk),
+            )
+        }
+        RelationKind::HasMany | RelationKind::HasOne => {
+            let fk = schema.resolve_foreign_key(entity_name, relation)
+                .ok_or_else(missing_fk)?;
+            (
+                target_table.clone(),
+                format!("{}.{} = {}.{}", target_table, fk, source_table, entity.primary_key()),
+            )
+        }
+    };
+
+    let predicate = condition_to_sql(cond, &source_table, true, schema, entity_name)?;
+
+    Ok(format!(
+        "EXISTS (SELECT 1 FROM {} WHERE {} AND {})",
+        from, correlate, predicate
+    ))
+}
+
 /// Convert a single condition to SQL
 fn condition_to_sql(
     cond: &FilterCondition,
@@ -251,28 +836,52 @@ fn condition_to_sql(
     schema: &Schema,
     entity_name: &str,
 ) -> Result<String, SqlGenError> {
-    let field_sql = if cond.field.is_nested() {
-        // Nested: "orders.total" → "orders.total"
-        let rel_name = cond.field.root();
-        let entity = schema.get_entity(entity_name).unwrap();
+    let field_sql = field_to_sql(&cond.field, table_name, qualify, schema, entity_name)?;
+    Ok(comparison_to_sql(&field_sql, cond))
+}
+
+/// Convert a field path to a column reference
+/// "email" → "email" (or "users.email" when qualified)
+/// "orders.total" → "orders.total"
+fn field_to_sql(
+    field: &FieldPath,
+    table_name: &str,
+    qualify: bool,
+    schema: &Schema,
+    entity_name: &str,
+) -> Result<String, SqlGenError> {
+    if field.is_nested() {
+        let rel_name = field.root();
+        let entity = schema.get_entity(entity_name)
+            .ok_or_else(|| SqlGenError::UnknownEntity(entity_name.to_string()))?;
         let relation = entity.relations.get(rel_name)
             .ok_or_else(|| SqlGenError::UnknownRelation {
                 entity: entity_name.to_string(),
                 relation: rel_name.to_string(),
             })?;
         let target_table = entity_to_table(&relation.target_entity);
-        let field_name = &cond.field.segments[1];
-        format!("{}.{}", target_table, field_name)
+        Ok(format!("{}.{}", target_table, field.segments[1]))
     } else if qualify {
-        format!("{}.{}", table_name, cond.field.root())
+        Ok(format!("{}.{}", table_name, field.root()))
     } else {
-        cond.field.root().to_string()
-    };
+        Ok(field.root().to_string())
+    }
+}
 
+/// Render "<field_sql> <op> <value>" for a condition
+fn comparison_to_sql(field_sql: &str, cond: &FilterCondition) -> String {
     let (op_sql, value_sql) = match (&cond.op, &cond.value) {
         (ComparisonOp::Like, FilterValue::String(s)) => {
             ("LIKE".to_string(), format!("'%{}%'", s))
         }
+        (ComparisonOp::Like, FilterValue::Param(n)) => {
+            // The caller binds the value with its wildcards
+            ("LIKE".to_string(), format!("${}", n))
+        }
+        (ComparisonOp::In, FilterValue::Param(n)) => {
+            // The caller binds the list as one array
+            ("=".to_string(), format!("ANY(${})", n))
+        }
         (ComparisonOp::In, _) => {
             // In is handled specially - value should be a list
             // For now, placeholder
@@ -292,7 +901,7 @@ fn condition_to_sql(
         }
     };
 
-    Ok(format!("{} {} {}", field_sql, op_sql, value_sql))
+    format!("{} {} {}", field_sql, op_sql, value_sql)
 }
 
 /// Convert a FilterValue to SQL literal
@@ -303,6 +912,7 @@ fn value_to_sql(value: &FilterValue) -> String {
         FilterValue::Float(f)  => f.to_string(),
         FilterValue::Bool(b)   => if *b { "true".to_string() } else { "false".to_string() },
         FilterValue::Null      => "NULL".to_string(),
+        FilterValue::Param(n)  => format!("${}", n),
     }
 }
 
@@ -342,7 +952,9 @@ fn build_eager_queries(
     for include in includes {
         build_eager_query_for_path(
             root_entity,
+            &[],
             &include.path,
+            includes,
             schema,
             &mut queries,
             &mut processed,
@@ -353,9 +965,14 @@ fn build_eager_queries(
 }
 
 /// Build a single eager loading query for a given path
+///
+/// Queries are named by their full include path ("orders.items") so the
+/// caller can attach children to the right parent level.
 fn build_eager_query_for_path(
     current_entity: &str,
+    parent_path: &[String],
     path: &[String],
+    includes: &[crate::query::IncludePath],
     schema: &Schema,
     queries: &mut Vec<(String, String)>,
     processed: &mut Vec<String>,
@@ -365,24 +982,9 @@ fn build_eager_query_for_path(
     }
 
     let rel_name = &path[0];
-    let full_path = format!("{}.{}", current_entity, rel_name);
-
-    // Skip if already processed
-    if processed.contains(&full_path) {
-        // But continue processing deeper paths
-        if path.len() > 1 {
-            let entity = schema.get_entity(current_entity).unwrap();
-            let relation = entity.relations.get(rel_name).unwrap();
-            return build_eager_query_for_path(
-                &relation.target_entity,
-                &path[1..],
-                schema,
-                queries,
-                processed,
-            );
-        }
-        return Ok(());
-    }
+    let mut segments = parent_path.to_vec();
+    segments.push(rel_name.clone());
+    let full_path = segments.join(".");
 
     let entity = schema.get_entity(current_entity)
         .ok_or_else(|| SqlGenError::UnknownEntity(current_entity.to_string()))?;
@@ -393,43 +995,82 @@ fn build_eager_query_for_path(
             relation: rel_name.clone(),
         })?;
 
-    let target_entity = schema.get_entity(&relation.target_entity)
-        .ok_or_else(|| SqlGenError::UnknownEntity(relation.target_entity.clone()))?;
+    // Skip if already processed, but continue with deeper paths
+    if !processed.contains(&full_path) {
+        let target_entity = schema.get_entity(&relation.target_entity)
+            .ok_or_else(|| SqlGenError::UnknownEntity(relation.target_entity.clone()))?;
 
-    let target_table = entity_to_table(&relation.target_entity);
+        let target_table = entity_to_table(&relation.target_entity);
 
-    let fk = relation.foreign_key.as_ref()
-        .ok_or_else(|| SqlGenError::MissingForeignKey {
-            entity: current_entity.to_string(),
-            relation: rel_name.clone(),
-        })?;
+        // Selected fields plus the keys deeper includes read from these rows
+        let selected = include_projection(includes, &segments);
+        let mut required = eager_parent_keys(&relation.target_entity, &segments, includes, schema)?;
 
-    // Build columns
-    let columns: Vec<String> = target_entity.fields.keys().cloned().collect();
+        let sql = if relation.kind == RelationKind::ManyToMany {
+            // Load through the join entity; each row carries the parent key
+            // it was loaded for, so children can be attached to the right parent
+            let (source_key, target_key) = schema.resolve_through_keys(current_entity, relation)
+                .ok_or_else(|| SqlGenError::MissingForeignKey {
+                    entity: current_entity.to_string(),
+                    relation: rel_name.clone(),
+                })?;
+            let join_table = entity_to_table(relation.through.as_deref().unwrap_or_default());
 
-    // Generate eager query with placeholder for IN clause
-    let sql = format!(
-        "SELECT {}\nFROM {}\nWHERE {} IN ($PARENT_IDS)",
-        columns.join(", "),
-        target_table,
-        fk,
-    );
+            // Columns are qualified since the join entity may share names
+            let mut columns: Vec<String> = project_columns(&relation.target_entity, target_entity, &selected, &required)?
+                .iter()
+                .map(|name| format!("{}.{}", target_table, name))
+                .collect();
+            columns.push(format!("{}.{} AS {}", join_table, source_key, THROUGH_PARENT_KEY));
 
-    queries.push((rel_name.clone(), sql));
-    processed.push(full_path);
+            format!(
+                "SELECT {}\nFROM {}\nINNER JOIN {} ON {}.{} = {}.{}\nWHERE {}.{} = ANY($1)",
+                columns.join(", "),
+                target_table,
+                join_table, join_table, target_key, target_table, target_entity.primary_key(),
+                join_table, source_key,
+            )
+        } else {
+            let fk = schema.resolve_foreign_key(current_entity, relation)
+                .ok_or_else(|| SqlGenError::MissingForeignKey {
+                    entity: current_entity.to_string(),
+                    relation: rel_name.clone(),
+                })?;
 
-    // Process deeper paths
-    if path.len() > 1 {
-        build_eager_query_for_path(
-            &relation.target_entity,
-            &path[1..],
-            schema,
-            queries,
-            processed,
-        )?;
+            // HasMany/HasOne: FK lives on the target, matched against parent PKs
+            // BelongsTo: FK lives on the parent, matched against target PKs
+            let match_column = match relation.kind {
+                RelationKind::BelongsTo => target_entity.primary_key(),
+                _ => fk,
+            };
+
+            // Children are matched to parents on match_column
+            required.push(match_column.clone());
+            let columns = project_columns(&relation.target_entity, target_entity, &selected, &required)?;
+
+            // Parent keys are bound by the caller as a single array parameter
+            format!(
+                "SELECT {}\nFROM {}\nWHERE {} = ANY($1)",
+                columns.join(", "),
+                target_table,
+                match_column,
+            )
+        };
+
+        queries.push((full_path.clone(), sql));
+        processed.push(full_path.clone());
     }
 
-    Ok(())
+    // Process deeper paths
+    build_eager_query_for_path(
+        &relation.target_entity,
+        &segments,
+        &path[1..],
+        includes,
+        schema,
+        queries,
+        processed,
+    )
 }
 
 /// Errors during SQL generation
@@ -438,6 +1079,8 @@ pub enum SqlGenError {
     UnknownEntity(String),
     UnknownRelation { entity: String, relation: String },
     MissingForeignKey { entity: String, relation: String },
+    UnknownField { entity: String, field: String },
+    InvalidAggregate(String),
 }
 
 impl std::fmt::Display for SqlGenError {
@@ -449,6 +1092,10 @@ impl std::fmt::Display for SqlGenError {
                 write!(f, "Unknown relation '{}' in entity '{}'", relation, entity),
             SqlGenError::MissingForeignKey { entity, relation } =>
                 write!(f, "Missing foreign key for relation '{}' in '{}'", relation, entity),
+            SqlGenError::UnknownField { entity, field } =>
+                write!(f, "Unknown field '{}' in entity '{}'", field, entity),
+            SqlGenError::InvalidAggregate(msg) =>
+                write!(f, "Invalid aggregate query: {}", msg),
         }
     }
 }
\ No newline at end of file
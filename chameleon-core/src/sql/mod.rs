@@ -21,24 +21,28 @@ mod tests {
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: true,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         user.add_field(Field {
             name: "email".to_string(),
             field_type: FieldType::String,
             nullable: false, unique: true, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         user.add_field(Field {
             name: "name".to_string(),
             field_type: FieldType::String,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         user.add_field(Field {
             name: "age".to_string(),
             field_type: FieldType::Int,
             nullable: true, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         user.add_relation(Relation {
             name: "orders".to_string(),
@@ -56,24 +60,28 @@ mod tests {
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: true,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         order.add_field(Field {
             name: "total".to_string(),
             field_type: FieldType::Decimal,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         order.add_field(Field {
             name: "status".to_string(),
             field_type: FieldType::String,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         order.add_field(Field {
             name: "user_id".to_string(),
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         order.add_relation(Relation {
             name: "user".to_string(),
@@ -98,24 +106,28 @@ mod tests {
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: true,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         item.add_field(Field {
             name: "quantity".to_string(),
             field_type: FieldType::Int,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         item.add_field(Field {
             name: "price".to_string(),
             field_type: FieldType::Decimal,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         item.add_field(Field {
             name: "order_id".to_string(),
             field_type: FieldType::UUID,
             nullable: false, unique: false, primary_key: false,
             default: None, backend: None,
+            constraints: Vec::new(),
         });
         item.add_relation(Relation {
             name: "order".to_string(),
@@ -129,6 +141,55 @@ mod tests {
         schema
     }
 
+    /// Helper: Post ←→ Tag through PostTag
+    fn m2m_schema() -> Schema {
+        let mut schema = Schema::new();
+
+        let id = || Field {
+            name: "id".to_string(),
+            field_type: FieldType::UUID,
+            nullable: false, unique: false, primary_key: true,
+            default: None, backend: None,
+            constraints: Vec::new(),
+        };
+        let column = |name: &str, field_type: FieldType| Field {
+            name: name.to_string(),
+            field_type,
+            nullable: false, unique: false, primary_key: false,
+            default: None, backend: None,
+            constraints: Vec::new(),
+        };
+        let relation = |name: &str, kind: RelationKind, target: &str, through: Option<&str>| Relation {
+            name: name.to_string(),
+            kind,
+            target_entity: target.to_string(),
+            foreign_key: None,
+            through: through.map(|t| t.to_string()),
+        };
+
+        let mut post = Entity::new("Post".to_string());
+        post.add_field(id());
+        post.add_field(column("title", FieldType::String));
+        post.add_relation(relation("tags", RelationKind::ManyToMany, "Tag", Some("PostTag")));
+        schema.add_entity(post);
+
+        let mut tag = Entity::new("Tag".to_string());
+        tag.add_field(id());
+        tag.add_field(column("name", FieldType::String));
+        tag.add_relation(relation("posts", RelationKind::ManyToMany, "Post", Some("PostTag")));
+        schema.add_entity(tag);
+
+        let mut post_tag = Entity::new("PostTag".to_string());
+        post_tag.add_field(id());
+        post_tag.add_field(column("post_id", FieldType::UUID));
+        post_tag.add_field(column("tag_id", FieldType::UUID));
+        post_tag.add_relation(relation("post", RelationKind::BelongsTo, "Post", None));
+        post_tag.add_relation(relation("tag", RelationKind::BelongsTo, "Tag", None));
+        schema.add_entity(post_tag);
+
+        schema
+    }
+
     // ─── NAMING ───
 
     #[test]
@@ -218,6 +279,37 @@ mod tests {
         assert!(result.main_query.contains("orders.total > 100"));
     }
 
+    #[test]
+    fn test_filter_on_relation_keeps_primary_key() {
+        let schema = test_schema();
+        let query = Query::new("User")
+            .select(&["name"])
+            .filter(FilterExpr::condition(
+                "orders.total", ComparisonOp::Gt, FilterValue::Int(100),
+            ));
+
+        // Two users named alike stay two rows
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.starts_with("SELECT DISTINCT users.name, users.id\n"),
+            "{}", result.main_query);
+    }
+
+    #[test]
+    fn test_filter_on_relation_projects_order_fields() {
+        let schema = test_schema();
+        let query = Query::new("User")
+            .select(&["name"])
+            .filter(FilterExpr::condition(
+                "orders.total", ComparisonOp::Gt, FilterValue::Int(100),
+            ))
+            .order_by("age", SortDirection::Desc);
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.starts_with("SELECT DISTINCT users.name, users.id, users.age\n"),
+            "{}", result.main_query);
+        assert!(result.main_query.contains("ORDER BY users.age DESC"));
+    }
+
     #[test]
     fn test_include_single() {
         let schema = test_schema();
@@ -228,7 +320,7 @@ mod tests {
         assert_eq!(result.eager_queries.len(), 1);
         assert_eq!(result.eager_queries[0].0, "orders");
         assert!(result.eager_queries[0].1.contains("FROM orders"));
-        assert!(result.eager_queries[0].1.contains("WHERE user_id IN ($PARENT_IDS)"));
+        assert!(result.eager_queries[0].1.contains("WHERE user_id = ANY($1)"));
     }
 
     #[test]
@@ -245,14 +337,419 @@ mod tests {
         assert_eq!(result.eager_queries[0].0, "orders");
         assert!(result.eager_queries[0].1.contains("FROM orders"));
 
-        // Second: items
-        assert_eq!(result.eager_queries[1].0, "items");
+        // Second: items (named by full include path)
+        assert_eq!(result.eager_queries[1].0, "orders.items");
         assert!(result.eager_queries[1].1.contains("FROM order_items"));
-        assert!(result.eager_queries[1].1.contains("WHERE order_id IN ($PARENT_IDS)"));
+        assert!(result.eager_queries[1].1.contains("WHERE order_id = ANY($1)"));
+    }
+
+    #[test]
+    fn test_include_nested_only() {
+        let schema = test_schema();
+        let query = Query::new("User")
+            .include("orders.items");
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert_eq!(result.eager_queries.len(), 2);
+        assert_eq!(result.eager_queries[0].0, "orders");
+        assert_eq!(result.eager_queries[1].0, "orders.items");
+    }
+
+    #[test]
+    fn test_include_belongs_to() {
+        let schema = test_schema();
+        let query = Query::new("OrderItem")
+            .include("order.user");
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert_eq!(result.eager_queries.len(), 2);
+
+        // BelongsTo matches the target's primary key
+        assert_eq!(result.eager_queries[0].0, "order");
+        assert!(result.eager_queries[0].1.contains("FROM orders"));
+        assert!(result.eager_queries[0].1.contains("WHERE id = ANY($1)"));
+
+        assert_eq!(result.eager_queries[1].0, "order.user");
+        assert!(result.eager_queries[1].1.contains("FROM users"));
+        assert!(result.eager_queries[1].1.contains("WHERE id = ANY($1)"));
+    }
+
+    #[test]
+    fn test_resolve_belongs_to_foreign_key() {
+        let schema = test_schema();
+        let order = schema.get_entity("Order").unwrap();
+        let user_rel = order.relations.get("user").unwrap();
+
+        // Inferred from User.orders: [Order] via user_id
+        assert_eq!(
+            schema.resolve_foreign_key("Order", user_rel),
+            Some("user_id".to_string())
+        );
     }
 
     // ─── ORDER BY / LIMIT / OFFSET ───
 
+    // ─── MANY TO MANY ───
+
+    #[test]
+    fn test_resolve_through_keys() {
+        let schema = m2m_schema();
+        let post = schema.get_entity("Post").unwrap();
+        let tags = post.relations.get("tags").unwrap();
+
+        assert_eq!(
+            schema.resolve_through_keys("Post", tags),
+            Some(("post_id".to_string(), "tag_id".to_string()))
+        );
+    }
+
+    #[test]
+    fn test_include_many_to_many() {
+        let schema = m2m_schema();
+        let query = Query::new("Post")
+            .include("tags");
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert_eq!(result.eager_queries.len(), 1);
+
+        let sql = &result.eager_queries[0].1;
+        assert!(sql.contains("FROM tags"));
+        assert!(sql.contains("INNER JOIN post_tags ON post_tags.tag_id = tags.id"));
+        assert!(sql.contains("post_tags.post_id AS __parent_key"));
+        assert!(sql.contains("WHERE post_tags.post_id = ANY($1)"));
+    }
+
+    #[test]
+    fn test_filter_on_many_to_many() {
+        let schema = m2m_schema();
+        let query = Query::new("Post")
+            .filter(FilterExpr::condition(
+                "tags.name", ComparisonOp::Eq,
+                FilterValue::String("go".to_string()),
+            ));
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.contains("DISTINCT"));
+        assert!(result.main_query.contains("INNER JOIN post_tags ON post_tags.post_id = posts.id"));
+        assert!(result.main_query.contains("INNER JOIN tags ON tags.id = post_tags.tag_id"));
+        assert!(result.main_query.contains("tags.name = 'go'"));
+    }
+
+    #[test]
+    fn test_filter_on_belongs_to() {
+        let schema = test_schema();
+        let query = Query::new("Order")
+            .filter(FilterExpr::condition(
+                "user.email", ComparisonOp::Eq,
+                FilterValue::String("ana@mail.com".to_string()),
+            ));
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.contains("INNER JOIN users ON users.id = orders.user_id"));
+    }
+
+    // ─── BOOLEAN COMPOSITION ───
+
+    #[test]
+    fn test_or_filter_grouping() {
+        let schema = test_schema();
+        let query = Query::new("Order")
+            .filter(
+                FilterExpr::condition("status", ComparisonOp::Eq, FilterValue::String("paid".to_string()))
+                    .or(
+                        FilterExpr::condition("status", ComparisonOp::Eq, FilterValue::String("pending".to_string()))
+                            .and(FilterExpr::condition("total", ComparisonOp::Gt, FilterValue::Int(100))),
+                    ),
+            )
+            .filter(FilterExpr::condition("total", ComparisonOp::Lt, FilterValue::Int(1000)));
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.contains(
+            "WHERE (status = 'paid' OR (status = 'pending' AND total > 100)) AND total < 1000"
+        ));
+    }
+
+    #[test]
+    fn test_not_filter() {
+        let schema = test_schema();
+        let query = Query::new("Order")
+            .filter(FilterExpr::condition("status", ComparisonOp::Eq, FilterValue::String("cancelled".to_string())).not())
+            .filter(
+                FilterExpr::condition("total", ComparisonOp::Lt, FilterValue::Int(10))
+                    .or(FilterExpr::condition("total", ComparisonOp::Gt, FilterValue::Int(500)))
+                    .not(),
+            );
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.contains("NOT (status = 'cancelled')"));
+        assert!(result.main_query.contains("NOT (total < 10 OR total > 500)"));
+    }
+
+    #[test]
+    fn test_relation_filter_inside_or_uses_exists() {
+        let schema = test_schema();
+        let query = Query::new("User")
+            .filter(
+                FilterExpr::condition("name", ComparisonOp::Eq, FilterValue::String("Ana".to_string()))
+                    .or(FilterExpr::condition("orders.total", ComparisonOp::Gt, FilterValue::Int(100))),
+            );
+
+        let result = generate_sql(&query, &schema).unwrap();
+        // Users without orders must still match the other branch
+        assert!(!result.main_query.contains("INNER JOIN"));
+        assert!(!result.main_query.contains("DISTINCT"));
+        assert!(result.main_query.contains(
+            "(name = 'Ana' OR EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id AND orders.total > 100))"
+        ));
+    }
+
+    #[test]
+    fn test_negated_relation_filters_use_exists() {
+        let schema = test_schema();
+        let query = Query::new("Order")
+            .filter(FilterExpr::condition(
+                "user.email", ComparisonOp::Eq, FilterValue::String("ana@mail.com".to_string()),
+            ).not());
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.contains(
+            "NOT (EXISTS (SELECT 1 FROM users WHERE users.id = orders.user_id AND users.email = 'ana@mail.com'))"
+        ));
+
+        let schema = m2m_schema();
+        let query = Query::new("Post")
+            .filter(FilterExpr::condition(
+                "tags.name", ComparisonOp::Eq, FilterValue::String("go".to_string()),
+            ).not());
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.contains(
+            "NOT (EXISTS (SELECT 1 FROM post_tags INNER JOIN tags ON tags.id = post_tags.tag_id \
+             WHERE post_tags.post_id = posts.id AND tags.name = 'go'))"
+        ));
+    }
+
+    // ─── PROJECTION ───
+
+    #[test]
+    fn test_select_fields() {
+        let schema = test_schema();
+        let query = Query::new("User").select(&["email", "name"]);
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.starts_with("SELECT email, name\nFROM users"));
+    }
+
+    #[test]
+    fn test_select_unknown_field() {
+        let schema = test_schema();
+        let query = Query::new("User").select(&["phone"]);
+
+        let result = generate_sql(&query, &schema);
+        assert!(matches!(result.unwrap_err(), SqlGenError::UnknownField { .. }));
+    }
+
+    #[test]
+    fn test_select_keeps_eager_keys() {
+        let schema = test_schema();
+        let query = Query::new("User")
+            .select(&["email"])
+            .include_fields("orders", &["total"])
+            .include_fields("orders.items", &["quantity"]);
+
+        let result = generate_sql(&query, &schema).unwrap();
+        // Root keeps its PK for orders
+        assert!(result.main_query.starts_with("SELECT email, id\nFROM users"));
+        // Orders keep the FK to match users and their own PK for items
+        assert!(result.eager_queries[0].1.starts_with("SELECT total, id, user_id\nFROM orders"));
+        assert!(result.eager_queries[1].1.starts_with("SELECT quantity, order_id\nFROM order_items"));
+    }
+
+    #[test]
+    fn test_select_keeps_belongs_to_keys() {
+        let schema = test_schema();
+        let query = Query::new("Order")
+            .select(&["total"])
+            .include_fields("user", &["email"]);
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.starts_with("SELECT total, user_id\nFROM orders"));
+        assert!(result.eager_queries[0].1.starts_with("SELECT email, id\nFROM users"));
+    }
+
+    #[test]
+    fn test_select_many_to_many_include() {
+        let schema = m2m_schema();
+        let query = Query::new("Post").include_fields("tags", &["name"]);
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.eager_queries[0].1.starts_with(
+            "SELECT tags.name, post_tags.post_id AS __parent_key\nFROM tags"
+        ));
+    }
+
+    // ─── AGGREGATES ───
+
+    #[test]
+    fn test_count() {
+        let schema = test_schema();
+        let query = Query::new("User")
+            .filter(FilterExpr::condition("age", ComparisonOp::Gte, FilterValue::Int(18)))
+            .aggregate(AggregateFunc::Count, None);
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert_eq!(result.main_query, "SELECT COUNT(*) AS count\nFROM users\nWHERE users.age >= 18");
+        assert!(result.eager_queries.is_empty());
+    }
+
+    #[test]
+    fn test_group_by_with_aggregates() {
+        let schema = test_schema();
+        let query = Query::new("Order")
+            .group_by("status")
+            .aggregate(AggregateFunc::Count, None)
+            .aggregate(AggregateFunc::Sum, Some("total"))
+            .aggregate(AggregateFunc::Avg, Some("total"))
+            .having(FilterExpr::condition("count", ComparisonOp::Gt, FilterValue::Int(1)))
+            .order_by("sum_total", SortDirection::Desc);
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.starts_with(
+            "SELECT orders.status, COUNT(*) AS count, SUM(orders.total) AS sum_total, AVG(orders.total) AS avg_total"
+        ));
+        assert!(result.main_query.contains("GROUP BY orders.status"));
+        assert!(result.main_query.contains("HAVING COUNT(*) > 1"));
+        assert!(result.main_query.contains("ORDER BY sum_total DESC"));
+    }
+
+    #[test]
+    fn test_aggregate_over_relation() {
+        let schema = test_schema();
+        let query = Query::new("User")
+            .group_by("email")
+            .aggregate(AggregateFunc::Count, None)
+            .aggregate(AggregateFunc::Sum, Some("orders.total"))
+            .having(FilterExpr::condition("sum_orders_total", ComparisonOp::Gt, FilterValue::Int(100)));
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.contains(
+            "COUNT(*) AS count, SUM(orders.sum_orders_total) AS sum_orders_total"
+        ), "{}", result.main_query);
+        assert!(result.main_query.contains(
+            "LEFT JOIN (SELECT orders.user_id AS __parent_key, SUM(orders.total) AS sum_orders_total \
+             FROM orders GROUP BY orders.user_id) AS orders ON orders.__parent_key = users.id"
+        ), "{}", result.main_query);
+        assert!(result.main_query.contains("HAVING SUM(orders.sum_orders_total) > 100"));
+        assert!(!result.main_query.contains("DISTINCT users.email"));
+    }
+
+    #[test]
+    fn test_aggregate_root_and_relation_fields() {
+        let schema = test_schema();
+        let query = Query::new("Order")
+            .aggregate(AggregateFunc::Count, None)
+            .aggregate(AggregateFunc::Sum, Some("total"))
+            .aggregate(AggregateFunc::Sum, Some("items.price"))
+            .aggregate(AggregateFunc::Avg, Some("items.price"));
+
+        let result = generate_sql(&query, &schema).unwrap();
+        let sql = &result.main_query;
+
+        // Items are rolled up per order, so each order's total counts once
+        assert!(sql.starts_with(
+            "SELECT COUNT(*) AS count, SUM(orders.total) AS sum_total, \
+             SUM(order_items.sum_items_price) AS sum_items_price, \
+             SUM(order_items.avg_items_price_sum)::numeric / NULLIF(SUM(order_items.avg_items_price_count), 0) AS avg_items_price\nFROM orders\n"
+        ), "{}", sql);
+        assert!(sql.contains(
+            "LEFT JOIN (SELECT order_items.order_id AS __parent_key, SUM(order_items.price) AS sum_items_price, \
+             SUM(order_items.price) AS avg_items_price_sum, COUNT(order_items.price) AS avg_items_price_count \
+             FROM order_items GROUP BY order_items.order_id) AS order_items ON order_items.__parent_key = orders.id"
+        ), "{}", sql);
+        assert!(!sql.contains("LEFT JOIN order_items ON"), "{}", sql);
+    }
+
+    #[test]
+    fn test_aggregate_many_to_many_rolled_up() {
+        let schema = m2m_schema();
+        let query = Query::new("Post")
+            .aggregate(AggregateFunc::Count, Some("tags.id"))
+            .aggregate(AggregateFunc::Max, Some("tags.name"));
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.contains(
+            "COALESCE(SUM(tags.count_tags_id), 0) AS count_tags_id, MAX(tags.max_tags_name) AS max_tags_name"
+        ), "{}", result.main_query);
+        assert!(result.main_query.contains(
+            "LEFT JOIN (SELECT post_tags.post_id AS __parent_key, COUNT(tags.id) AS count_tags_id, MAX(tags.name) AS max_tags_name \
+             FROM post_tags INNER JOIN tags ON tags.id = post_tags.tag_id GROUP BY post_tags.post_id) AS tags \
+             ON tags.__parent_key = posts.id"
+        ), "{}", result.main_query);
+    }
+
+    #[test]
+    fn test_aggregate_grouped_by_to_many_relation() {
+        let schema = test_schema();
+
+        // Grouping by a to-many relation joins it row by row
+        let query = Query::new("User")
+            .group_by("orders.status")
+            .aggregate(AggregateFunc::Count, None)
+            .aggregate(AggregateFunc::Sum, Some("orders.total"))
+            .aggregate(AggregateFunc::Max, Some("age"));
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.contains(
+            "COUNT(DISTINCT users.id) AS count, SUM(orders.total) AS sum_orders_total, MAX(users.age) AS max_age"
+        ), "{}", result.main_query);
+        assert!(result.main_query.contains("LEFT JOIN orders ON orders.user_id = users.id"));
+
+        // ...which would repeat root values once per order
+        let query = Query::new("User")
+            .group_by("orders.status")
+            .aggregate(AggregateFunc::Sum, Some("age"));
+        match generate_sql(&query, &schema).unwrap_err() {
+            SqlGenError::InvalidAggregate(msg) => assert!(msg.contains("sum_age"), "{}", msg),
+            other => panic!("expected InvalidAggregate, got {:?}", other),
+        }
+    }
+
+    #[test]
+    fn test_aggregate_group_by_relation_field() {
+        let schema = test_schema();
+        let query = Query::new("Order")
+            .filter(FilterExpr::condition(
+                "user.email", ComparisonOp::Like, FilterValue::String("mail.com".to_string()),
+            ))
+            .group_by("user.email")
+            .aggregate(AggregateFunc::Max, Some("total"));
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.contains("SELECT users.email AS \"user.email\", MAX(orders.total) AS max_total"));
+        assert!(result.main_query.contains("LEFT JOIN users ON users.id = orders.user_id"));
+        // Relation filters must not multiply aggregated rows
+        assert!(result.main_query.contains("WHERE EXISTS (SELECT 1 FROM users WHERE"));
+        assert!(result.main_query.contains("GROUP BY users.email"));
+    }
+
+    #[test]
+    fn test_aggregate_rejects_includes() {
+        let schema = test_schema();
+        let query = Query::new("User")
+            .include("orders")
+            .aggregate(AggregateFunc::Count, None);
+
+        let result = generate_sql(&query, &schema);
+        assert!(matches!(result.unwrap_err(), SqlGenError::InvalidAggregate(_)));
+    }
+
+    #[test]
+    fn test_sum_requires_field() {
+        let schema = test_schema();
+        let query = Query::new("User").aggregate(AggregateFunc::Sum, None);
+
+        assert!(matches!(generate_sql(&query, &schema).unwrap_err(), SqlGenError::InvalidAggregate(_)));
+    }
+
     #[test]
     fn test_order_by() {
         let schema = test_schema();
@@ -276,6 +773,18 @@ mod tests {
         assert!(result.main_query.contains("OFFSET 20"));
     }
 
+    #[test]
+    fn test_limit_offset_params() {
+        let schema = test_schema();
+        let mut query = Query::new("User")
+            .filter(FilterExpr::condition("age", ComparisonOp::Gte, FilterValue::Param(1)));
+        query.limit = Some(RowCount::Param { param: 2 });
+        query.offset = Some(RowCount::Param { param: 3 });
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.ends_with("LIMIT $2\nOFFSET $3"), "{}", result.main_query);
+    }
+
     // ─── COMBINED ───
 
     #[test]
@@ -303,6 +812,28 @@ mod tests {
         assert_eq!(result.eager_queries.len(), 2);
     }
 
+    // ─── PARAMETERS ───
+
+    #[test]
+    fn test_filter_params() {
+        let schema = test_schema();
+        let query = Query::new("User")
+            .filter(FilterExpr::condition("email", ComparisonOp::Eq, FilterValue::Param(1)))
+            .filter(FilterExpr::condition("name", ComparisonOp::Like, FilterValue::Param(2)))
+            .filter(FilterExpr::condition("age", ComparisonOp::In, FilterValue::Param(3)));
+
+        let result = generate_sql(&query, &schema).unwrap();
+        assert!(result.main_query.contains("email = $1"));
+        assert!(result.main_query.contains("name LIKE $2"));
+        assert!(result.main_query.contains("age = ANY($3)"));
+    }
+
+    #[test]
+    fn test_param_deserialize() {
+        let value: FilterValue = serde_json::from_str(r#"{"Param":2}"#).unwrap();
+        assert_eq!(value, FilterValue::Param(2));
+    }
+
     // ─── ERRORS ───
 
     #[test]
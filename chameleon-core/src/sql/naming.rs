@@ -18,7 +18,7 @@ pub fn entity_to_table(entity_name: &str) -> String {
 /// "OrderItem" → "order_item"
 /// "User"      → "user"
 /// "UUID"      → "u_u_i_d" (edge case, handled separately if needed)
-fn pascal_to_snake(name: &str) -> String {
+pub fn pascal_to_snake(name: &str) -> String {
     let mut result = String::new();
 
     for (i, ch) in name.chars().enumerate() {
@@ -1,5 +1,6 @@
-use crate::ast::{Schema, FieldType, BackendAnnotation};
+use crate::ast::{Schema, Field, FieldType, FieldConstraint, BackendAnnotation};
 use super::errors::TypeCheckError;
+use regex::Regex;
 
 /// Validates primary key constraints
 pub fn check_primary_keys(schema: &Schema) -> Vec<TypeCheckError> {
@@ -68,4 +69,95 @@ pub fn check_annotations(schema: &Schema) -> Vec<TypeCheckError> {
     }
 
     errors
-}
\ No newline at end of file
+}
+/// Validates field constraints (min, max, length, pattern, check)
+pub fn check_field_constraints(schema: &Schema) -> Vec<TypeCheckError> {
+    let mut errors = Vec::new();
+
+    for entity in &schema.entities {
+        let mut fields: Vec<&Field> = entity.fields.values().collect();
+        fields.sort_by(|a, b| a.name.cmp(&b.name));
+
+        for field in fields {
+            let mut invalid = |constraint: &str, reason: String| {
+                errors.push(TypeCheckError::InvalidConstraint {
+                    entity: entity.name.clone(),
+                    field: field.name.clone(),
+                    constraint: constraint.to_string(),
+                    reason,
+                });
+            };
+
+            let numeric = matches!(field.field_type, FieldType::Int | FieldType::Decimal | FieldType::Float);
+            let string = field.field_type == FieldType::String;
+            let mut seen: Vec<&str> = Vec::new();
+            let (mut min, mut max) = (None, None);
+
+            for constraint in &field.constraints {
+                let name = constraint_name(constraint);
+                if name != "check" && seen.contains(&name) {
+                    invalid(name, "declared more than once".to_string());
+                    continue;
+                }
+                seen.push(name);
+
+                match constraint {
+                    FieldConstraint::Min(bound) | FieldConstraint::Max(bound) => {
+                        if !numeric {
+                            invalid(name, format!("only applies to int, decimal and float fields, not {:?}", field.field_type));
+                            continue;
+                        }
+                        if field.field_type == FieldType::Int && bound.parse::<i64>().is_err() {
+                            invalid(name, format!("'{}' is not an integer", bound));
+                            continue;
+                        }
+                        let value = bound.parse::<f64>().ok();
+                        if matches!(constraint, FieldConstraint::Min(_)) { min = value } else { max = value }
+                    }
+                    FieldConstraint::Length { min, max } => {
+                        if !string {
+                            invalid(name, format!("only applies to string fields, not {:?}", field.field_type));
+                        } else if min.is_none() && max.is_none() {
+                            invalid(name, "needs a lower or upper bound".to_string());
+                        } else if let (Some(lo), Some(hi)) = (min, max) {
+                            if lo > hi {
+                                invalid(name, format!("lower bound {} is greater than upper bound {}", lo, hi));
+                            }
+                        }
+                    }
+                    FieldConstraint::Pattern(pattern) => {
+                        if !string {
+                            invalid(name, format!("only applies to string fields, not {:?}", field.field_type));
+                        } else if let Err(e) = Regex::new(pattern) {
+                            invalid(name, format!("invalid regular expression: {}", e));
+                        }
+                    }
+                    FieldConstraint::Check(expr) => {
+                        if expr.trim().is_empty() {
+                            invalid(name, "expression is empty".to_string());
+                        }
+                    }
+                }
+            }
+
+            if let (Some(lo), Some(hi)) = (min, max) {
+                if lo > hi {
+                    invalid("min", format!("min is greater than max ({} > {})", lo, hi));
+                }
+            }
+        }
+    }
+
+    errors
+}
+
+/// Keyword of a constraint as written in the schema
+pub fn constraint_name(constraint: &FieldConstraint) -> &'static str {
+    match constraint {
+        FieldConstraint::Min(_) => "min",
+        FieldConstraint::Max(_) => "max",
+        FieldConstraint::Length { .. } => "length",
+        FieldConstraint::Pattern(_) => "pattern",
+        FieldConstraint::Check(_) => "check",
+    }
+}
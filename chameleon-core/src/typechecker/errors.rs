@@ -24,6 +24,13 @@ pub enum TypeCheckError {
         relation: String,
     },
 
+    #[error("ManyToMany relation '{relation}' in '{entity}' requires a 'through' entity '{through}' with keys to both sides")]
+    InvalidThroughEntity {
+        entity: String,
+        relation: String,
+        through: String,
+    },
+
     // Primary keys
     #[error("Entity '{entity}' has no primary key")]
     MissingPrimaryKey {
@@ -52,6 +59,15 @@ pub enum TypeCheckError {
         annotation: String,
     },
 
+    // Field constraints
+    #[error("Constraint '{constraint}' on field '{field}' in '{entity}' is invalid: {reason}")]
+    InvalidConstraint {
+        entity: String,
+        field: String,
+        constraint: String,  // "min", "max", "length", "pattern", "check"
+        reason: String,
+    },
+
     // Circular dependencies
     #[error("Circular dependency detected: {cycle:?}")]
     CircularDependency {
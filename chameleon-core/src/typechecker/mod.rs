@@ -90,6 +90,7 @@ pub fn type_check(schema: &Schema) -> TypeCheckResult {
     // Constraints
     errors.extend(constraints::check_primary_keys(schema));
     errors.extend(constraints::check_annotations(schema));
+    errors.extend(constraints::check_field_constraints(schema));
 
     TypeCheckResult { errors }
 }
@@ -116,6 +117,7 @@ mod tests {
                     primary_key: primary,
                     default: None,
                     backend: annotation,
+                    constraints: Vec::new(),
                 });
             }
 
@@ -231,6 +233,47 @@ mod tests {
         assert!(result.errors.iter().any(|e| matches!(e, TypeCheckError::MissingForeignKey { .. })));
     }
 
+    #[test]
+    fn test_many_to_many_requires_join_keys() {
+        let mut schema = build_schema(vec![
+            ("Post",
+                vec![("id", FieldType::UUID, true, false, None)],
+                vec![]),
+            ("Tag",
+                vec![("id", FieldType::UUID, true, false, None)],
+                vec![]),
+            ("PostTag",
+                vec![("id", FieldType::UUID, true, false, None),
+                     ("post_id", FieldType::UUID, false, false, None)],
+                vec![]),
+        ]);
+        schema.get_entity_mut("Post").unwrap().add_relation(Relation {
+            name: "tags".to_string(),
+            kind: RelationKind::ManyToMany,
+            target_entity: "Tag".to_string(),
+            foreign_key: None,
+            through: Some("PostTag".to_string()),
+        });
+
+        // PostTag has no tag_id
+        let result = type_check(&schema);
+        assert!(result.errors.iter().any(|e| matches!(e, TypeCheckError::InvalidThroughEntity { .. })));
+
+        schema.get_entity_mut("PostTag").unwrap().add_field(Field {
+            name: "tag_id".to_string(),
+            field_type: FieldType::UUID,
+            nullable: false,
+            unique: false,
+            primary_key: false,
+            default: None,
+            backend: None,
+            constraints: Vec::new(),
+        });
+
+        let result = type_check(&schema);
+        assert!(result.is_valid(), "{}", result.error_report());
+    }
+
     // ─── PRIMARY KEY ERRORS ───
 
     #[test]
@@ -304,6 +347,76 @@ mod tests {
         assert!(result.errors.iter().any(|e| matches!(e, TypeCheckError::AnnotationOnConstrainedField { .. })));
     }
 
+    // ─── FIELD CONSTRAINTS ───
+
+    fn with_constraints(field: &str, field_type: FieldType, constraints: Vec<FieldConstraint>) -> Schema {
+        let mut schema = build_schema(vec![
+            ("User", vec![("id", FieldType::UUID, true, false, None)], vec![]),
+        ]);
+        schema.get_entity_mut("User").unwrap().add_field(Field {
+            name: field.to_string(),
+            field_type,
+            nullable: false,
+            unique: false,
+            primary_key: false,
+            default: None,
+            backend: None,
+            constraints,
+        });
+        schema
+    }
+
+    fn constraint_errors(schema: &Schema) -> Vec<String> {
+        type_check(schema).errors.iter()
+            .filter_map(|e| match e {
+                TypeCheckError::InvalidConstraint { constraint, reason, .. } => Some(format!("{}: {}", constraint, reason)),
+                _ => None,
+            })
+            .collect()
+    }
+
+    #[test]
+    fn test_valid_constraints() {
+        let schema = with_constraints("age", FieldType::Int, vec![
+            FieldConstraint::Min("0".into()),
+            FieldConstraint::Max("150".into()),
+            FieldConstraint::Check("age % 2 = 0".into()),
+        ]);
+        assert!(type_check(&schema).is_valid(), "{}", type_check(&schema).error_report());
+
+        let schema = with_constraints("code", FieldType::String, vec![
+            FieldConstraint::Length { min: Some(3), max: Some(3) },
+            FieldConstraint::Pattern("^[A-Z]+$".into()),
+        ]);
+        assert!(type_check(&schema).is_valid(), "{}", type_check(&schema).error_report());
+    }
+
+    #[test]
+    fn test_invalid_constraints() {
+        let cases = vec![
+            (FieldType::String, FieldConstraint::Min("1".into()), "min: only applies to int, decimal and float fields"),
+            (FieldType::Int, FieldConstraint::Max("1.5".into()), "max: '1.5' is not an integer"),
+            (FieldType::Int, FieldConstraint::Length { min: Some(1), max: None }, "length: only applies to string fields"),
+            (FieldType::String, FieldConstraint::Length { min: Some(5), max: Some(2) }, "length: lower bound 5 is greater"),
+            (FieldType::String, FieldConstraint::Length { min: None, max: None }, "length: needs a lower or upper bound"),
+            (FieldType::String, FieldConstraint::Pattern("[a-".into()), "pattern: invalid regular expression"),
+            (FieldType::Decimal, FieldConstraint::Check("  ".into()), "check: expression is empty"),
+        ];
+
+        for (field_type, constraint, expected) in cases {
+            let errors = constraint_errors(&with_constraints("f", field_type, vec![constraint]));
+            assert!(errors.iter().any(|e| e.starts_with(expected)), "expected '{}', got {:?}", expected, errors);
+        }
+
+        let errors = constraint_errors(&with_constraints("total", FieldType::Decimal, vec![
+            FieldConstraint::Min("10".into()),
+            FieldConstraint::Max("1.5".into()),
+            FieldConstraint::Max("2".into()),
+        ]));
+        assert!(errors.iter().any(|e| e == "max: declared more than once"), "{:?}", errors);
+        assert!(errors.iter().any(|e| e.starts_with("min: min is greater than max")), "{:?}", errors);
+    }
+
     // ─── CIRCULAR DEPENDENCY ───
 
     #[test]
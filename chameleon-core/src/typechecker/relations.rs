@@ -38,6 +38,18 @@ pub fn check_relations(schema: &Schema) -> Vec<TypeCheckError> {
                     relation: relation.name.clone(),
                 });
             }
+
+            // 4. ManyToMany relations MUST go through a join entity
+            //    that points at both sides
+            if relation.kind == RelationKind::ManyToMany
+                && schema.resolve_through_keys(&entity.name, relation).is_none()
+            {
+                errors.push(TypeCheckError::InvalidThroughEntity {
+                    entity: entity.name.clone(),
+                    relation: relation.name.clone(),
+                    through: relation.through.clone().unwrap_or_default(),
+                });
+            }
         }
     }
 
@@ -73,7 +85,8 @@ fn dfs(
     if let Some(entity) = schema.get_entity(current) {
         for (_, relation) in &entity.relations {
             // BelongsTo is just the inverse side of a relation, skip it
-            if relation.kind == RelationKind::BelongsTo {
+            // ManyToMany has no FK on either side (the join entity holds them)
+            if relation.kind == RelationKind::BelongsTo || relation.kind == RelationKind::ManyToMany {
                 continue;
             }
             
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/chameleon-db/chameleondb/chameleon/internal/admin"
+	"github.com/chameleon-db/chameleondb/chameleon/internal/codegen"
+	"github.com/chameleon-db/chameleondb/chameleon/internal/schema"
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+	"github.com/spf13/cobra"
+)
+
+var (
+	generateOut     string
+	generatePackage string
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate code from the schema",
+}
+
+var generateGoCmd = &cobra.Command{
+	Use:   "go [file]",
+	Short: "Generate typed Go models and query builders",
+	Long: `Generate Go structs, field/relation constants and typed query and
+mutation wrappers for every entity of the schema.
+
+Without a file, schemas are loaded from the paths in .chameleon.yml,
+falling back to 'schema.cham' in the current directory.
+The output is deterministic: regenerating an unchanged schema leaves
+the file untouched.
+
+Examples:
+  chameleon generate go
+  chameleon generate go schema.cham --out internal/db/models_gen.go --package db`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source, err := loadSchemaSource(args)
+		if err != nil {
+			return err
+		}
+
+		eng := engine.NewEngineWithoutSchema()
+		loaded, err := eng.LoadSchemaFromString(source)
+		if err != nil {
+			return fmt.Errorf("failed to load schema: %w", err)
+		}
+
+		code, err := codegen.GenerateGo(loaded, codegen.GoOptions{Package: generatePackage})
+		if err != nil {
+			return err
+		}
+
+		if existing, err := os.ReadFile(generateOut); err == nil && bytes.Equal(existing, code) {
+			printInfo("%s is up to date", generateOut)
+			return nil
+		}
+
+		if err := os.MkdirAll(filepath.Dir(generateOut), 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		if err := os.WriteFile(generateOut, code, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", generateOut, err)
+		}
+
+		printSuccess("Generated %s (%d entities)", generateOut, len(loaded.Entities))
+		return nil
+	},
+}
+
+// loadSchemaSource reads the schema from the given file, the paths
+// configured in .chameleon.yml, or schema.cham
+func loadSchemaSource(args []string) (string, error) {
+	if len(args) > 0 {
+		content, err := os.ReadFile(args[0])
+		if err != nil {
+			return "", fmt.Errorf("failed to read file: %w", err)
+		}
+		return string(content), nil
+	}
+
+	if workDir, err := os.Getwd(); err == nil {
+		cfg, err := admin.NewManagerFactory(workDir).CreateConfigLoader().Load()
+		if err == nil && len(cfg.Schema.Paths) > 0 {
+			filenames, contents, err := schema.NewFileLoader(cfg.Schema.Paths).LoadAll()
+			if err != nil {
+				return "", fmt.Errorf("failed to load schemas: %w", err)
+			}
+			merged, err := schema.NewSimpleMerger().Merge(filenames, contents)
+			if err != nil {
+				return "", fmt.Errorf("failed to merge schemas: %w", err)
+			}
+			return merged.Content, nil
+		}
+	}
+
+	content, err := os.ReadFile("schema.cham")
+	if err != nil {
+		return "", fmt.Errorf("no schema found: pass a file or run 'chameleon init'")
+	}
+	return string(content), nil
+}
+
+func init() {
+	generateGoCmd.Flags().StringVarP(&generateOut, "out", "o", "models/models_gen.go", "output file")
+	generateGoCmd.Flags().StringVar(&generatePackage, "package", "models", "package name of the generated code")
+
+	generateCmd.AddCommand(generateGoCmd)
+	rootCmd.AddCommand(generateCmd)
+}
@@ -153,8 +153,8 @@ func exampleSchema() string {
 
 entity User {
     id: uuid primary,
-    email: string unique,
-    name: string,
+    email: string unique pattern("^[^@\s]+@[^@\s]+\.[^@\s]+$"),
+    name: string length(1..100),
     created_at: timestamp default now(),
 }
 
@@ -238,8 +238,8 @@ Define your database schema in ` + "`schemas/*.cham`" + `:
 ` + "```" + `
 entity User {
     id: uuid primary,
-    email: string unique,
-    name: string,
+    email: string unique pattern("^[^@\s]+@[^@\s]+\.[^@\s]+$"),
+    name: string length(1..100),
     created_at: timestamp default now(),
 }
 
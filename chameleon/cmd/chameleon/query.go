@@ -12,6 +12,7 @@ var (
 	queryDebug   bool
 	queryTrace   bool
 	queryExplain bool
+	queryAnalyze bool
 )
 
 var queryCmd = &cobra.Command{
@@ -22,7 +23,8 @@ var queryCmd = &cobra.Command{
 Examples:
   chameleon query User --debug
   chameleon query Post --trace
-  chameleon query Order --explain`,
+  chameleon query Order --explain
+  chameleon query Order --explain --analyze`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		entity := args[0]
@@ -32,8 +34,9 @@ Examples:
 		eng.LoadSchemaFromFile("schema.cham")
 
 		// Set debug level
-		if queryExplain {
+		if queryExplain || queryAnalyze {
 			eng.Debug.Level = engine.DebugExplain
+			eng.Debug.ExplainAnalyze = queryAnalyze
 		} else if queryTrace {
 			eng.Debug.Level = engine.DebugTrace
 		} else if queryDebug {
@@ -63,6 +66,7 @@ func init() {
 	queryCmd.Flags().BoolVar(&queryDebug, "debug", false, "show generated SQL")
 	queryCmd.Flags().BoolVar(&queryTrace, "trace", false, "show full query trace")
 	queryCmd.Flags().BoolVar(&queryExplain, "explain", false, "show query plan")
+	queryCmd.Flags().BoolVar(&queryAnalyze, "analyze", false, "run EXPLAIN ANALYZE (executes the query again)")
 
 	rootCmd.AddCommand(queryCmd)
 }
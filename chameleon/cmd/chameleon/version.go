@@ -1,43 +1,77 @@
 package main
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/chameleon-db/chameleondb/chameleon/internal/ffi"
 	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
 	"github.com/spf13/cobra"
 )
 
+// version is the CLI's own version, set at build time with
+// -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Show ChameleonDB version",
-	Long:  "Display the current version of ChameleonDB CLI and core library",
-	Run: func(cmd *cobra.Command, args []string) {
-		ctx := context.Background()
-
-		config, err := LoadConnectorConfig()
-		if err != nil {
-			return
-		}
-
-		eng := engine.NewEngine()
-		connector := engine.NewConnector(config)
-		if err := connector.Connect(ctx); err != nil {
-			return
-		}
-		defer connector.Close()
-		version := eng.Version()
+	Long: `Display the current version of ChameleonDB CLI and core library.
 
-		fmt.Printf("ChameleonDB v%s\n", version)
+Also checks that the core library (libchameleon_core) matches this binary,
+and fails with instructions when it does not.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Printf("ChameleonDB %s\n", version)
 
 		if verbose {
 			fmt.Println("\nComponents:")
-			fmt.Printf("  CLI:  v%s\n", version)
-			fmt.Printf("  Core: v%s (Rust)\n", version)
+			fmt.Printf("  CLI:  %s (core ABI %d)\n", version, ffi.ABIVersion)
+			fmt.Printf("  Core: v%s (Rust, ABI %d)\n", engine.NewEngineWithoutSchema().Version(), ffi.LibraryABIVersion())
+			fmt.Printf("  Capabilities: %s\n", strings.Join(ffi.Capabilities(), ", "))
 		}
+
+		if err := engine.CheckCore(); err != nil {
+			fmt.Println()
+			printError("Core library mismatch")
+			return coreMismatchError(err)
+		}
+		return nil
 	},
 }
 
+// coreMismatchError explains a failed ABI handshake
+func coreMismatchError(err error) error {
+	var abiErr *ffi.ABIError
+	if !errors.As(err, &abiErr) {
+		return err
+	}
+
+	var b strings.Builder
+	switch {
+	case abiErr.Found == 0:
+		// Nothing else to report: such a library has none of the newer features
+		fmt.Fprintf(&b, "this binary speaks core ABI %d, the loaded libchameleon_core v%s predates ABI versioning\n",
+			abiErr.Expected, abiErr.Library)
+	case abiErr.Found != abiErr.Expected:
+		fmt.Fprintf(&b, "this binary speaks core ABI %d, the loaded libchameleon_core v%s speaks ABI %d\n",
+			abiErr.Expected, abiErr.Library, abiErr.Found)
+	}
+	if abiErr.Found != 0 && len(abiErr.Missing) > 0 {
+		fmt.Fprintf(&b, "the loaded libchameleon_core v%s lacks: %s\n",
+			abiErr.Library, strings.Join(abiErr.Missing, ", "))
+	}
+	if abiErr.Found != 0 && len(abiErr.Symbols) > 0 {
+		fmt.Fprintf(&b, "the loaded libchameleon_core v%s does not export: %s\n",
+			abiErr.Library, strings.Join(abiErr.Symbols, ", "))
+	}
+	b.WriteString("\nTo fix it, do one of the following:\n" +
+		"  - reinstall the CLI and library together (dist/install.sh)\n" +
+		"  - check LD_LIBRARY_PATH / DYLD_LIBRARY_PATH for an older libchameleon_core\n" +
+		"  - when building from source, rebuild the core: cd chameleon-core && cargo build --release")
+	return errors.New(b.String())
+}
+
 func init() {
 	rootCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,403 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+)
+
+// GoOptions configures the Go code generator
+type GoOptions struct {
+	// Package name of the generated file
+	Package string
+}
+
+// goEntity is the template view of an entity
+type goEntity struct {
+	Name      string
+	Fields    []goField
+	Relations []goRelation
+}
+
+// goField is the template view of a field
+type goField struct {
+	GoName    string // Email, UserID
+	Column    string // email, user_id
+	Type      string // Go type of the struct field
+	ValueType string // Go type accepted by filters and setters
+	Nullable  bool
+	Primary   bool
+}
+
+// goRelation is the template view of a relation
+type goRelation struct {
+	GoName     string // Orders
+	Name       string // orders
+	Target     string // Order
+	Many       bool
+	ManyToMany bool
+}
+
+// GenerateGo renders typed models, constants and query/mutation wrappers
+// for every entity of the schema.
+//
+// The output only depends on the schema: entities, fields and relations are
+// sorted by name (primary keys first), so regenerating is idempotent.
+func GenerateGo(schema *engine.Schema, opts GoOptions) ([]byte, error) {
+	if schema == nil {
+		return nil, fmt.Errorf("no schema loaded")
+	}
+	if opts.Package == "" {
+		opts.Package = "models"
+	}
+
+	entities := make([]goEntity, 0, len(schema.Entities))
+	usesTime := false
+
+	for _, ent := range schema.Entities {
+		view := goEntity{Name: ent.Name}
+
+		for _, f := range ent.Fields {
+			valueType := goType(f.Type)
+			if valueType == "time.Time" {
+				usesTime = true
+			}
+			fieldType := valueType
+			if f.Nullable && !strings.HasPrefix(valueType, "[]") && valueType != "any" {
+				fieldType = "*" + valueType
+			}
+			view.Fields = append(view.Fields, goField{
+				GoName:    goIdent(f.Name),
+				Column:    f.Name,
+				Type:      fieldType,
+				ValueType: valueType,
+				Nullable:  f.Nullable,
+				Primary:   f.PrimaryKey,
+			})
+		}
+		sort.Slice(view.Fields, func(i, j int) bool {
+			a, b := view.Fields[i], view.Fields[j]
+			if a.Primary != b.Primary {
+				return a.Primary
+			}
+			return a.Column < b.Column
+		})
+
+		for _, rel := range ent.Relations {
+			view.Relations = append(view.Relations, goRelation{
+				GoName:     goIdent(rel.Name),
+				Name:       rel.Name,
+				Target:     rel.TargetEntity,
+				Many:       rel.Kind == engine.RelationHasMany || rel.Kind == engine.RelationManyToMany,
+				ManyToMany: rel.Kind == engine.RelationManyToMany,
+			})
+		}
+		sort.Slice(view.Relations, func(i, j int) bool {
+			return view.Relations[i].Name < view.Relations[j].Name
+		})
+
+		entities = append(entities, view)
+	}
+	sort.Slice(entities, func(i, j int) bool { return entities[i].Name < entities[j].Name })
+
+	var buf bytes.Buffer
+	err := goTemplate.Execute(&buf, map[string]interface{}{
+		"Package":  opts.Package,
+		"UsesTime": usesTime,
+		"Entities": entities,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render Go code: %w", err)
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated Go code is invalid: %w", err)
+	}
+	return out, nil
+}
+
+// goType maps a field type to the Go type its values are scanned into
+func goType(ft engine.FieldType) string {
+	switch ft.Kind {
+	case "UUID", "String":
+		return "string"
+	case "Int":
+		return "int64"
+	case "Decimal":
+		// Exact, like the "12.50" strings mutations accept for decimals
+		return "string"
+	case "Float":
+		return "float64"
+	case "Bool":
+		return "bool"
+	case "Timestamp":
+		return "time.Time"
+	default:
+		// Vector, Array: no lossless mapping yet
+		return "any"
+	}
+}
+
+// commonInitialisms are kept upper-case in Go identifiers (user_id → UserID)
+var commonInitialisms = map[string]bool{
+	"api": true, "html": true, "http": true, "id": true, "ip": true,
+	"json": true, "sql": true, "uri": true, "url": true, "uuid": true,
+}
+
+// goIdent converts a snake_case name to an exported Go identifier
+func goIdent(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+		if commonInitialisms[strings.ToLower(part)] {
+			b.WriteString(strings.ToUpper(part))
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+var goTemplate = template.Must(template.New("go").Parse(`// Code generated by chameleon generate go. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+{{- if .UsesTime}}
+	"time"
+{{- end}}
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+)
+
+// Op is a filter operator
+type Op string
+
+const (
+	Eq   Op = "eq"
+	Neq  Op = "neq"
+	Gt   Op = "gt"
+	Gte  Op = "gte"
+	Lt   Op = "lt"
+	Lte  Op = "lte"
+	Like Op = "like"
+)
+
+// DB gives typed access to every entity
+type DB struct {
+{{- range .Entities}}
+	{{.Name}} {{.Name}}Model
+{{- end}}
+}
+
+// New wraps an engine with typed entity accessors
+func New(eng *engine.Engine) *DB {
+	return &DB{
+{{- range .Entities}}
+		{{.Name}}: {{.Name}}Model{eng: eng},
+{{- end}}
+	}
+}
+{{range $e := .Entities}}
+// ============================================================
+// {{$e.Name}}
+// ============================================================
+
+// Entity, field and relation names of {{$e.Name}}
+const (
+	{{$e.Name}}Entity = "{{$e.Name}}"
+{{- range $e.Fields}}
+	{{$e.Name}}Field{{.GoName}} = "{{.Column}}"
+{{- end}}
+{{- range $e.Relations}}
+	{{$e.Name}}Relation{{.GoName}} = "{{.Name}}"
+{{- end}}
+)
+
+// {{$e.Name}} is a row of the {{$e.Name}} entity
+type {{$e.Name}} struct {
+{{- range $e.Fields}}
+	{{.GoName}} {{.Type}} ` + "`cham:\"{{.Column}}\"`" + `
+{{- end}}
+{{- range $e.Relations}}
+	{{.GoName}} {{if .Many}}[]{{else}}*{{end}}{{.Target}} ` + "`cham:\"{{.Name}}\"`" + `
+{{- end}}
+}
+
+// {{$e.Name}}Model creates queries and mutations for {{$e.Name}}
+type {{$e.Name}}Model struct {
+	eng *engine.Engine
+}
+
+// Query starts a typed query
+func (m {{$e.Name}}Model) Query() *{{$e.Name}}Query {
+	return &{{$e.Name}}Query{q: engine.QueryAs[{{$e.Name}}](m.eng, {{$e.Name}}Entity)}
+}
+
+// Insert starts a typed insert
+func (m {{$e.Name}}Model) Insert() *{{$e.Name}}Insert {
+	return &{{$e.Name}}Insert{m: m.eng.Insert({{$e.Name}}Entity)}
+}
+
+// Update starts a typed update
+func (m {{$e.Name}}Model) Update() *{{$e.Name}}Update {
+	return &{{$e.Name}}Update{m: m.eng.Update({{$e.Name}}Entity)}
+}
+
+// Delete starts a typed delete
+func (m {{$e.Name}}Model) Delete() *{{$e.Name}}Delete {
+	return &{{$e.Name}}Delete{m: m.eng.Delete({{$e.Name}}Entity)}
+}
+{{- range $e.Relations}}{{if .ManyToMany}}
+
+// Link{{.GoName}} connects and disconnects {{.Name}} of the {{$e.Name}} with the given id
+func (m {{$e.Name}}Model) Link{{.GoName}}(id any) engine.LinkMutation {
+	return m.eng.Link({{$e.Name}}Entity, {{$e.Name}}Relation{{.GoName}}, id)
+}
+{{- end}}{{end}}
+
+// {{$e.Name}}Query is a typed QueryBuilder for {{$e.Name}}
+type {{$e.Name}}Query struct {
+	q *engine.TypedQuery[{{$e.Name}}]
+}
+{{range $e.Fields}}
+// Where{{.GoName}} filters on {{.Column}}
+func (q *{{$e.Name}}Query) Where{{.GoName}}(op Op, value {{.ValueType}}) *{{$e.Name}}Query {
+	q.q.Filter({{$e.Name}}Field{{.GoName}}, string(op), value)
+	return q
+}
+{{end}}
+{{- range $e.Relations}}
+// Include{{.GoName}} eager-loads {{.Name}}, optionally only some fields
+func (q *{{$e.Name}}Query) Include{{.GoName}}(fields ...string) *{{$e.Name}}Query {
+	q.q.Include({{$e.Name}}Relation{{.GoName}}, fields...)
+	return q
+}
+{{end}}
+// Where adds a composed filter (engine.Or, engine.Not, ...)
+func (q *{{$e.Name}}Query) Where(expr engine.FilterExpr) *{{$e.Name}}Query {
+	q.q.Where(expr)
+	return q
+}
+
+// Select loads only the given fields ({{$e.Name}}Field* constants)
+func (q *{{$e.Name}}Query) Select(fields ...string) *{{$e.Name}}Query {
+	q.q.Select(fields...)
+	return q
+}
+
+// OrderBy sorts by a field; direction is "asc" or "desc"
+func (q *{{$e.Name}}Query) OrderBy(field string, direction string) *{{$e.Name}}Query {
+	q.q.OrderBy(field, direction)
+	return q
+}
+
+// Limit sets the maximum number of results
+func (q *{{$e.Name}}Query) Limit(n uint64) *{{$e.Name}}Query {
+	q.q.Limit(n)
+	return q
+}
+
+// Offset sets the number of results to skip
+func (q *{{$e.Name}}Query) Offset(n uint64) *{{$e.Name}}Query {
+	q.q.Offset(n)
+	return q
+}
+
+// Builder returns the underlying QueryBuilder
+func (q *{{$e.Name}}Query) Builder() *engine.QueryBuilder {
+	return q.q.QueryBuilder
+}
+
+// All executes the query
+func (q *{{$e.Name}}Query) All(ctx context.Context) ([]{{$e.Name}}, error) {
+	return q.q.All(ctx)
+}
+
+// Each streams the query row by row
+func (q *{{$e.Name}}Query) Each(ctx context.Context, fn func({{$e.Name}}) error) error {
+	return q.q.Each(ctx, fn)
+}
+
+// {{$e.Name}}Insert is a typed insert for {{$e.Name}}
+type {{$e.Name}}Insert struct {
+	m engine.InsertMutation
+}
+{{range $e.Fields}}
+// Set{{.GoName}} sets {{.Column}}{{if .Nullable}} (nil = NULL){{end}}
+func (i *{{$e.Name}}Insert) Set{{.GoName}}(value {{if .Nullable}}*{{end}}{{.ValueType}}) *{{$e.Name}}Insert {
+{{- if .Nullable}}
+	if value == nil {
+		i.m.Set({{$e.Name}}Field{{.GoName}}, nil)
+		return i
+	}
+	i.m.Set({{$e.Name}}Field{{.GoName}}, *value)
+{{- else}}
+	i.m.Set({{$e.Name}}Field{{.GoName}}, value)
+{{- end}}
+	return i
+}
+{{end}}
+// Execute validates and runs the insert
+func (i *{{$e.Name}}Insert) Execute(ctx context.Context) (*engine.InsertResult, error) {
+	return i.m.Execute(ctx)
+}
+
+// {{$e.Name}}Update is a typed update for {{$e.Name}}
+type {{$e.Name}}Update struct {
+	m engine.UpdateMutation
+}
+{{range $e.Fields}}
+// Set{{.GoName}} sets {{.Column}}{{if .Nullable}} (nil = NULL){{end}}
+func (u *{{$e.Name}}Update) Set{{.GoName}}(value {{if .Nullable}}*{{end}}{{.ValueType}}) *{{$e.Name}}Update {
+{{- if .Nullable}}
+	if value == nil {
+		u.m.Set({{$e.Name}}Field{{.GoName}}, nil)
+		return u
+	}
+	u.m.Set({{$e.Name}}Field{{.GoName}}, *value)
+{{- else}}
+	u.m.Set({{$e.Name}}Field{{.GoName}}, value)
+{{- end}}
+	return u
+}
+
+// Where{{.GoName}} filters the rows to update on {{.Column}}
+func (u *{{$e.Name}}Update) Where{{.GoName}}(op Op, value {{.ValueType}}) *{{$e.Name}}Update {
+	u.m.Filter({{$e.Name}}Field{{.GoName}}, string(op), value)
+	return u
+}
+{{end}}
+// Execute validates and runs the update
+func (u *{{$e.Name}}Update) Execute(ctx context.Context) (*engine.UpdateResult, error) {
+	return u.m.Execute(ctx)
+}
+
+// {{$e.Name}}Delete is a typed delete for {{$e.Name}}
+type {{$e.Name}}Delete struct {
+	m engine.DeleteMutation
+}
+{{range $e.Fields}}
+// Where{{.GoName}} filters the rows to delete on {{.Column}}
+func (d *{{$e.Name}}Delete) Where{{.GoName}}(op Op, value {{.ValueType}}) *{{$e.Name}}Delete {
+	d.m.Filter({{$e.Name}}Field{{.GoName}}, string(op), value)
+	return d
+}
+{{end}}
+// Execute validates and runs the delete
+func (d *{{$e.Name}}Delete) Execute(ctx context.Context) (*engine.DeleteResult, error) {
+	return d.m.Execute(ctx)
+}
+{{end}}`))
@@ -0,0 +1,136 @@
+package codegen
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+)
+
+func testSchema() *engine.Schema {
+	userFK := "user_id"
+	through := "post_tags"
+
+	return &engine.Schema{Entities: []*engine.Entity{
+		{
+			Name: "User",
+			Fields: map[string]*engine.Field{
+				"id":         {Name: "id", Type: engine.FieldTypeUUID, PrimaryKey: true},
+				"email":      {Name: "email", Type: engine.FieldTypeString, Unique: true},
+				"age":        {Name: "age", Type: engine.FieldTypeInt, Nullable: true},
+				"created_at": {Name: "created_at", Type: engine.FieldTypeTimestamp},
+			},
+			Relations: map[string]*engine.Relation{
+				"orders": {Name: "orders", Kind: engine.RelationHasMany, TargetEntity: "Order", ForeignKey: &userFK},
+			},
+		},
+		{
+			Name: "Order",
+			Fields: map[string]*engine.Field{
+				"id":      {Name: "id", Type: engine.FieldTypeUUID, PrimaryKey: true},
+				"total":   {Name: "total", Type: engine.FieldTypeDecimal},
+				"user_id": {Name: "user_id", Type: engine.FieldTypeUUID},
+			},
+			Relations: map[string]*engine.Relation{
+				"user": {Name: "user", Kind: engine.RelationBelongsTo, TargetEntity: "User", ForeignKey: &userFK},
+			},
+		},
+		{
+			Name: "Post",
+			Fields: map[string]*engine.Field{
+				"id":    {Name: "id", Type: engine.FieldTypeUUID, PrimaryKey: true},
+				"title": {Name: "title", Type: engine.FieldTypeString},
+			},
+			Relations: map[string]*engine.Relation{
+				"tags": {Name: "tags", Kind: engine.RelationManyToMany, TargetEntity: "Tag", Through: &through},
+			},
+		},
+		{
+			Name: "Tag",
+			Fields: map[string]*engine.Field{
+				"id":   {Name: "id", Type: engine.FieldTypeUUID, PrimaryKey: true},
+				"name": {Name: "name", Type: engine.FieldTypeString},
+			},
+		},
+	}}
+}
+
+func TestGenerateGo_Output(t *testing.T) {
+	code, err := GenerateGo(testSchema(), GoOptions{Package: "db"})
+	if err != nil {
+		t.Fatalf("GenerateGo failed: %v", err)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "models_gen.go", code, parser.AllErrors); err != nil {
+		t.Fatalf("Generated code does not parse: %v\n%s", err, code)
+	}
+
+	// Collapse gofmt alignment so expectations don't depend on padding
+	src := strings.Join(strings.Fields(string(code)), " ")
+	for _, want := range []string{
+		"// Code generated by chameleon generate go. DO NOT EDIT.",
+		"package db",
+		`UserFieldEmail = "email"`,
+		`UserRelationOrders = "orders"`,
+		"Age *int64 `cham:\"age\"`",
+		"CreatedAt time.Time",
+		"Orders []Order `cham:\"orders\"`",
+		"User *User `cham:\"user\"`",
+		"func (q *UserQuery) WhereEmail(op Op, value string) *UserQuery",
+		"func (q *UserQuery) IncludeOrders(fields ...string) *UserQuery",
+		"func (i *UserInsert) SetAge(value *int64) *UserInsert",
+		"func (q *OrderQuery) WhereUserID(op Op, value string) *OrderQuery",
+		"Total string `cham:\"total\"`",
+		"func (q *OrderQuery) WhereTotal(op Op, value string) *OrderQuery",
+		"func (m PostModel) LinkTags(id any) engine.LinkMutation",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("Expected generated code to contain %q", want)
+		}
+	}
+}
+
+func TestGenerateGo_Deterministic(t *testing.T) {
+	first, err := GenerateGo(testSchema(), GoOptions{})
+	if err != nil {
+		t.Fatalf("GenerateGo failed: %v", err)
+	}
+
+	// Entities in a different order, maps rebuilt (random iteration)
+	reordered := testSchema()
+	ents := reordered.Entities
+	ents[0], ents[3] = ents[3], ents[0]
+
+	for i := 0; i < 5; i++ {
+		again, err := GenerateGo(reordered, GoOptions{})
+		if err != nil {
+			t.Fatalf("GenerateGo failed: %v", err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatal("Expected identical output for the same schema")
+		}
+	}
+
+	if !strings.Contains(string(first), "package models") {
+		t.Error("Expected default package 'models'")
+	}
+}
+
+func TestGoIdent(t *testing.T) {
+	cases := map[string]string{
+		"email":      "Email",
+		"id":         "ID",
+		"user_id":    "UserID",
+		"created_at": "CreatedAt",
+		"api_url":    "APIURL",
+		"orders":     "Orders",
+	}
+	for in, want := range cases {
+		if got := goIdent(in); got != want {
+			t.Errorf("goIdent(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
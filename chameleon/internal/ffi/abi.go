@@ -0,0 +1,144 @@
+package ffi
+
+/*
+#cgo linux LDFLAGS: -ldl
+#include "symbols.h"
+*/
+import "C"
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ============================================================
+// ABI HANDSHAKE
+// ============================================================
+
+// ABIVersion is the version of the core's C ABI these bindings are
+// written against (CHAMELEON_ABI_VERSION in chameleon.h)
+const ABIVersion = 1
+
+// headerABIVersion is the ABI version of the chameleon.h compiled in
+const headerABIVersion = C.CHAMELEON_ABI_VERSION
+
+// requiredCapabilities are the core features the engine relies on
+var requiredCapabilities = []string{
+	"parse_schema",
+	"validate_schema",
+	"generate_sql",
+	"generate_migration",
+	"schema_handle",
+	"query_params",
+	"encoded_calls",
+}
+
+// ABIError reports a core library that does not match these bindings
+type ABIError struct {
+	Library  string   // version of the loaded library
+	Expected int      // ABIVersion
+	Found    int      // ABI version of the loaded library (0 = unversioned)
+	Missing  []string // required capabilities the library lacks
+	Symbols  []string // core functions the library does not export
+}
+
+func (e *ABIError) Error() string {
+	var problem string
+	switch {
+	case e.Found == 0:
+		problem = fmt.Sprintf("predates ABI versioning, this build needs ABI %d", e.Expected)
+	case e.Found != e.Expected:
+		problem = fmt.Sprintf("has ABI version %d, this build needs %d", e.Found, e.Expected)
+	case len(e.Symbols) > 0:
+		problem = "does not export " + strings.Join(e.Symbols, ", ")
+	default:
+		problem = "lacks " + strings.Join(e.Missing, ", ")
+	}
+	return fmt.Sprintf(
+		"core library libchameleon_core v%s %s: install the library from the same "+
+			"ChameleonDB release as this binary, or rebuild it (cargo build --release in chameleon-core)",
+		e.Library, problem)
+}
+
+// missingSymbols lists the functions of symbols.h the loaded library does
+// not export, resolved when the package loads
+var missingSymbols = resolveSymbols()
+
+// abiErr is the result of the handshake, run when the package loads
+var abiErr = checkABI(LibraryABIVersion(), Capabilities(), missingSymbols)
+
+func resolveSymbols() []string {
+	if C.ffi_resolve_symbols() == 0 {
+		return nil
+	}
+
+	var missing []string
+	for i := range int(C.ffi_symbol_count()) {
+		if C.ffi_symbol_found(C.int(i)) == 0 {
+			missing = append(missing, C.GoString(C.ffi_symbol_name(C.int(i))))
+		}
+	}
+	return missing
+}
+
+// exported reports whether the loaded library exports a function of
+// symbols.h
+func exported(symbol string) bool {
+	return !slices.Contains(missingSymbols, symbol)
+}
+
+// checkABI compares a library's ABI version, capabilities and exported
+// functions with these bindings
+func checkABI(found int, capabilities []string, symbols []string) error {
+	var missing []string
+	for _, capability := range requiredCapabilities {
+		if !slices.Contains(capabilities, capability) {
+			missing = append(missing, capability)
+		}
+	}
+
+	if found == ABIVersion && len(missing) == 0 && len(symbols) == 0 {
+		return nil
+	}
+	return &ABIError{
+		Library:  Version(),
+		Expected: ABIVersion,
+		Found:    found,
+		Missing:  missing,
+		Symbols:  symbols,
+	}
+}
+
+// CheckABI returns an *ABIError when the loaded core library does not
+// match these bindings (ABI version, required capabilities or exported
+// functions). The other bindings return the same error rather than call
+// into such a library.
+func CheckABI() error {
+	return abiErr
+}
+
+// LibraryABIVersion returns the ABI version of the loaded core library, 0
+// for libraries older than the handshake
+func LibraryABIVersion() int {
+	if !exported("chameleon_abi_version") {
+		return 0
+	}
+	return int(C.ffi_abi_version())
+}
+
+// Capabilities returns the features of the loaded core library
+func Capabilities() []string {
+	if !exported("chameleon_capabilities") {
+		return nil
+	}
+	list := C.GoString(C.ffi_capabilities())
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+// HasCapability reports whether the loaded core library has a feature
+func HasCapability(name string) bool {
+	return slices.Contains(Capabilities(), name)
+}
@@ -2,40 +2,39 @@ package ffi
 
 /*
 #include <stdlib.h>
-
-// Forward declarations
-typedef enum {
-    CHAMELEON_OK = 0,
-    CHAMELEON_PARSE_ERROR = 1,
-    CHAMELEON_VALIDATION_ERROR = 2,
-    CHAMELEON_INTERNAL_ERROR = 3,
-} ChameleonResult;
-
-char* chameleon_parse_schema(const char* input, char** error_out);
-ChameleonResult chameleon_validate_schema(const char* schema_json, char** error_out);
-void chameleon_free_string(char* s);
-const char* chameleon_version(void);
-extern int chameleon_generate_sql(const char* query_json, const char* schema_json, char** error_out);
-extern int chameleon_generate_migration(const char* schema_json, char** error_out);
+#include "symbols.h"
 */
 import "C"
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"runtime"
+	"sync"
 	"unsafe"
 )
 
+// The C declarations come from chameleon.h (generated by the core's build),
+// found through CGO_CFLAGS=-I.../chameleon-core/include. Functions newer
+// than the first release are called through symbols.h. Every binding but
+// Version fails with the handshake error (see CheckABI) when the loaded
+// library does not match.
+
 // Result codes
 const (
-	ResultOk              = C.CHAMELEON_OK
-	ResultParseError      = C.CHAMELEON_PARSE_ERROR
-	ResultValidationError = C.CHAMELEON_VALIDATION_ERROR
-	ResultInternalError   = C.CHAMELEON_INTERNAL_ERROR
+	ResultOk              = C.Ok
+	ResultParseError      = C.ParseError
+	ResultValidationError = C.ValidationError
+	ResultInternalError   = C.InternalError
 )
 
 // ParseSchema calls Rust FFI to parse a schema string into JSON
 func ParseSchema(input string) (string, error) {
+	if abiErr != nil {
+		return "", abiErr
+	}
+
 	cInput := C.CString(input)
 	defer C.free(unsafe.Pointer(cInput))
 
@@ -61,6 +60,10 @@ func ParseSchema(input string) (string, error) {
 
 // ValidateSchema calls Rust FFI to validate a schema JSON
 func ValidateSchema(schemaJSON string) error {
+	if abiErr != nil {
+		return abiErr
+	}
+
 	cJSON := C.CString(schemaJSON)
 	defer C.free(unsafe.Pointer(cJSON))
 
@@ -85,6 +88,10 @@ func ValidateSchema(schemaJSON string) error {
 
 // ValidateSchemaRaw validates schema and returns structured JSON errors
 func ValidateSchemaRaw(schemaInput string) (string, error) {
+	if abiErr != nil {
+		return "", abiErr
+	}
+
 	cInput := C.CString(schemaInput)
 	defer C.free(unsafe.Pointer(cInput))
 	var cError *C.char
@@ -122,6 +129,10 @@ func Version() string {
 // GenerateSQL calls the Rust SQL generator
 // Takes query JSON and schema JSON, returns GeneratedSQL JSON
 func GenerateSQL(queryJSON string, schemaJSON string) (string, error) {
+	if abiErr != nil {
+		return "", abiErr
+	}
+
 	cQuery := C.CString(queryJSON)
 	defer C.free(unsafe.Pointer(cQuery))
 
@@ -153,6 +164,10 @@ func GenerateSQL(queryJSON string, schemaJSON string) (string, error) {
 
 // GenerateMigration calls the Rust migration generator
 func GenerateMigration(schemaJSON string) (string, error) {
+	if abiErr != nil {
+		return "", abiErr
+	}
+
 	cSchema := C.CString(schemaJSON)
 	defer C.free(unsafe.Pointer(cSchema))
 
@@ -181,21 +196,278 @@ func GenerateMigration(schemaJSON string) (string, error) {
 // GenerateMutationSQL calls Rust FFI to generate mutation SQL
 // If schemaJSON is "", uses cached schema from previous SetSchemaCache call
 func GenerateMutationSQL(mutationJSON string, schemaJSON string) string {
-	// C code would call: generate_mutation_sql(mutation_json, schema_json)
-	// Returns JSON string
-	// Implementation depends on your C bindings
-	// For now, placeholder:
-	return `{"valid": false, "error": "FFI binding not yet implemented"}`
+	if abiErr != nil {
+		return invalidJSON(abiErr)
+	}
+
+	cMutation := C.CString(mutationJSON)
+	defer C.free(unsafe.Pointer(cMutation))
+
+	var cSchema *C.char
+	if schemaJSON != "" {
+		cSchema = C.CString(schemaJSON)
+		defer C.free(unsafe.Pointer(cSchema))
+	}
+
+	return takeJSON(C.generate_mutation_sql(cMutation, cSchema))
 }
 
 // SetSchemaCache calls Rust FFI to cache schema
+//
+// Deprecated: the cache is shared by the whole process; use LoadSchema.
 func SetSchemaCache(schemaJSON string) string {
-	// C code would call: set_schema_cache(schema_json)
-	return `{"valid": false, "error": "FFI binding not yet implemented"}`
+	if abiErr != nil {
+		return invalidJSON(abiErr)
+	}
+
+	cSchema := C.CString(schemaJSON)
+	defer C.free(unsafe.Pointer(cSchema))
+
+	return takeJSON(C.set_schema_cache(cSchema))
 }
 
 // ClearSchemaCache calls Rust FFI to clear cache
+//
+// Deprecated: use Schema.Close.
 func ClearSchemaCache() string {
-	// C code would call: clear_schema_cache()
-	return `{"valid": true}`
+	if abiErr != nil {
+		return invalidJSON(abiErr)
+	}
+	return takeJSON(C.clear_schema_cache())
+}
+
+// ============================================================
+// SCHEMA HANDLES
+// ============================================================
+
+// Schema is a schema loaded into the Rust core once, so that generating
+// SQL does not send the schema JSON with every call. A Schema is safe for
+// concurrent use. Close frees it; otherwise a finalizer does once the
+// Schema is unreachable.
+type Schema struct {
+	mu     sync.RWMutex // held for reading by calls, for writing by Close
+	handle *C.ChameleonSchema
+}
+
+// LoadSchema loads a schema JSON (as returned by ParseSchema) into the core
+func LoadSchema(schemaJSON string) (*Schema, error) {
+	if abiErr != nil {
+		return nil, abiErr
+	}
+
+	cSchema := C.CString(schemaJSON)
+	defer C.free(unsafe.Pointer(cSchema))
+
+	var cError *C.char
+	handle := C.ffi_schema_load(cSchema, &cError)
+	if handle == nil {
+		return nil, takeError(cError, "schema load failed")
+	}
+
+	s := &Schema{handle: handle}
+	runtime.SetFinalizer(s, (*Schema).Close)
+	return s, nil
+}
+
+// Close frees the schema in the core, waiting for running calls.
+// Calls after Close fail; closing twice is a no-op.
+func (s *Schema) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.handle != nil {
+		C.ffi_schema_free(s.handle)
+		s.handle = nil
+	}
+	runtime.SetFinalizer(s, nil)
+}
+
+// errSchemaClosed is returned by calls on a closed Schema
+var errSchemaClosed = errors.New("schema handle is closed")
+
+// GenerateSQL calls the Rust SQL generator for a query JSON,
+// returns GeneratedSQL JSON
+func (s *Schema) GenerateSQL(queryJSON string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.handle == nil {
+		return "", errSchemaClosed
+	}
+
+	cQuery := C.CString(queryJSON)
+	defer C.free(unsafe.Pointer(cQuery))
+
+	var out *C.char
+	result := C.ffi_schema_generate_sql(s.handle, cQuery, &out)
+	if result != 0 {
+		return "", takeError(out, fmt.Sprintf("SQL generation failed with code %d", result))
+	}
+	return takeOutput(out, "SQL generation returned null")
+}
+
+// GenerateMigration calls the Rust migration generator
+func (s *Schema) GenerateMigration() (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.handle == nil {
+		return "", errSchemaClosed
+	}
+
+	var out *C.char
+	result := C.ffi_schema_generate_migration(s.handle, &out)
+	if result != 0 {
+		return "", takeError(out, fmt.Sprintf("migration generation failed with code %d", result))
+	}
+	return takeOutput(out, "migration generation returned null")
+}
+
+// GenerateMutationSQL calls the Rust mutation generator; the result is
+// JSON: {"valid":true,"sql":"...","params":[...]} or {"valid":false,"error":"..."}
+func (s *Schema) GenerateMutationSQL(mutationJSON string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.handle == nil {
+		return invalidJSON(errSchemaClosed)
+	}
+
+	cMutation := C.CString(mutationJSON)
+	defer C.free(unsafe.Pointer(cMutation))
+
+	return takeJSON(C.ffi_schema_generate_mutation_sql(s.handle, cMutation))
+}
+
+// ============================================================
+// ENCODED CALLS
+// ============================================================
+
+// Encoding is the payload format of an encoded call. The core answers in
+// the encoding of the request.
+type Encoding uint32
+
+const (
+	EncodingJSON    Encoding = C.CHAMELEON_ENCODING_JSON
+	EncodingMsgPack Encoding = C.CHAMELEON_ENCODING_MSGPACK
+)
+
+// buffer receives the answers of encoded calls. Its memory belongs to the
+// core, which reuses it from call to call; buffers are pooled, and freed
+// by a finalizer once the pool drops them.
+type buffer struct {
+	c C.ChameleonBuffer
+}
+
+// maxPooledBuffer bounds the memory kept by a pooled buffer
+const maxPooledBuffer = 1 << 20
+
+var buffers = sync.Pool{
+	New: func() any {
+		b := &buffer{}
+		runtime.SetFinalizer(b, (*buffer).free)
+		return b
+	},
+}
+
+func (b *buffer) free() {
+	C.ffi_buffer_free(&b.c)
+}
+
+// bytes returns the last answer, valid until the buffer is used again
+func (b *buffer) bytes() []byte {
+	if b.c.data == nil {
+		return nil
+	}
+	return unsafe.Slice((*byte)(unsafe.Pointer(b.c.data)), int(b.c.len))
+}
+
+func (b *buffer) release() {
+	if b.c.cap > maxPooledBuffer {
+		b.free()
+	}
+	buffers.Put(b)
+}
+
+type encodedOp int
+
+const (
+	opGenerateSQL encodedOp = iota
+	opGenerateMutationSQL
+)
+
+// GenerateSQLEncoded calls the Rust SQL generator with a query in enc.
+// read gets the GeneratedSQL in the same encoding; the bytes belong to
+// the core and are only valid during read, which must copy what it keeps.
+func (s *Schema) GenerateSQLEncoded(enc Encoding, query []byte, read func(result []byte) error) error {
+	return s.encodedCall(opGenerateSQL, enc, query, read)
+}
+
+// GenerateMutationSQLEncoded calls the Rust mutation generator with a
+// mutation in enc. read gets {"valid":true,"sql":"...","params":[...]} or
+// {"valid":false,"error":"..."} in the same encoding, as for
+// GenerateSQLEncoded.
+func (s *Schema) GenerateMutationSQLEncoded(enc Encoding, mutation []byte, read func(result []byte) error) error {
+	return s.encodedCall(opGenerateMutationSQL, enc, mutation, read)
+}
+
+func (s *Schema) encodedCall(op encodedOp, enc Encoding, input []byte, read func([]byte) error) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.handle == nil {
+		return errSchemaClosed
+	}
+
+	buf := buffers.Get().(*buffer)
+	defer buf.release()
+
+	// The core reads the input in place: no copy into C memory
+	var cInput *C.uint8_t
+	if len(input) > 0 {
+		cInput = (*C.uint8_t)(unsafe.Pointer(&input[0]))
+	}
+	cLen := C.uintptr_t(len(input))
+
+	var result C.enum_ChameleonResult
+	switch op {
+	case opGenerateSQL:
+		result = C.ffi_schema_generate_sql_encoded(s.handle, C.uint32_t(enc), cInput, cLen, &buf.c)
+	case opGenerateMutationSQL:
+		result = C.ffi_schema_generate_mutation_sql_encoded(s.handle, C.uint32_t(enc), cInput, cLen, &buf.c)
+	}
+
+	if result != ResultOk {
+		return errors.New(string(buf.bytes()))
+	}
+	return read(buf.bytes())
+}
+
+// takeError frees an error string from the core and returns it as an error
+func takeError(cError *C.char, fallback string) error {
+	if cError == nil {
+		return errors.New(fallback)
+	}
+	defer C.chameleon_free_string(cError)
+	return errors.New(C.GoString(cError))
+}
+
+// takeOutput frees a result string from the core and returns its content
+func takeOutput(out *C.char, errNull string) (string, error) {
+	if out == nil {
+		return "", errors.New(errNull)
+	}
+	defer C.chameleon_free_string(out)
+	return C.GoString(out), nil
+}
+
+// takeJSON frees a JSON result from the mutation functions and returns it
+func takeJSON(out *C.char) string {
+	if out == nil {
+		return invalidJSON(errors.New("core returned null"))
+	}
+	defer C.chameleon_free_string(out)
+	return C.GoString(out)
+}
+
+// invalidJSON is the mutation functions' result for an error
+func invalidJSON(err error) string {
+	out, _ := json.Marshal(map[string]interface{}{"valid": false, "error": err.Error()})
+	return string(out)
 }
@@ -0,0 +1,390 @@
+package ffi
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chameleon-db/chameleondb/chameleon/internal/msgpack"
+)
+
+const testSchema = `
+	entity User {
+		id: uuid primary,
+		email: string unique,
+	}
+`
+
+const testQuery = `{"entity":"User","filters":[],"includes":[],"order_by":[],"limit":null,"offset":null}`
+
+const testMutation = `{"type":"delete","entity":"User","filters":{"id":"u1"}}`
+
+func parseTestSchema(t *testing.T) string {
+	t.Helper()
+	schemaJSON, err := ParseSchema(testSchema)
+	if err != nil {
+		t.Fatalf("ParseSchema failed: %v", err)
+	}
+	return schemaJSON
+}
+
+// mutationResult decodes the JSON returned by the mutation bindings
+func mutationResult(t *testing.T, out string) (bool, string) {
+	t.Helper()
+	var result struct {
+		Valid bool   `json:"valid"`
+		SQL   string `json:"sql"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if !result.Valid {
+		return false, result.Error
+	}
+	return true, result.SQL
+}
+
+// ─────────────────────────────────────────────────────────────
+// Handshake
+// ─────────────────────────────────────────────────────────────
+
+func TestABIHandshake(t *testing.T) {
+	if err := CheckABI(); err != nil {
+		t.Fatalf("CheckABI failed: %v", err)
+	}
+	if LibraryABIVersion() != ABIVersion {
+		t.Errorf("library ABI %d, bindings ABI %d", LibraryABIVersion(), ABIVersion)
+	}
+	if headerABIVersion != ABIVersion {
+		t.Errorf("chameleon.h declares ABI %d, bindings are written for %d", headerABIVersion, ABIVersion)
+	}
+	for _, capability := range requiredCapabilities {
+		if !HasCapability(capability) {
+			t.Errorf("missing capability %q in %v", capability, Capabilities())
+		}
+	}
+	if HasCapability("time_travel") {
+		t.Error("unexpected capability")
+	}
+	if len(missingSymbols) != 0 {
+		t.Errorf("symbols not found in the library: %v", missingSymbols)
+	}
+}
+
+func TestCheckABI_Mismatch(t *testing.T) {
+	err := checkABI(ABIVersion+1, requiredCapabilities, nil)
+	var abiErr *ABIError
+	if !errors.As(err, &abiErr) {
+		t.Fatalf("expected *ABIError, got %v", err)
+	}
+	if abiErr.Found != ABIVersion+1 || abiErr.Expected != ABIVersion || abiErr.Library != Version() {
+		t.Errorf("unexpected error fields: %+v", abiErr)
+	}
+	if !strings.Contains(err.Error(), "has ABI version 2, this build needs 1") {
+		t.Errorf("unexpected message: %v", err)
+	}
+
+	err = checkABI(ABIVersion, []string{"parse_schema"}, nil)
+	if !errors.As(err, &abiErr) || len(abiErr.Missing) != len(requiredCapabilities)-1 {
+		t.Fatalf("expected missing capabilities, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "lacks validate_schema, generate_sql") {
+		t.Errorf("unexpected message: %v", err)
+	}
+
+	// Libraries older than the handshake export none of symbols.h
+	err = checkABI(0, nil, []string{"chameleon_abi_version", "chameleon_capabilities"})
+	if !errors.As(err, &abiErr) || len(abiErr.Symbols) != 2 {
+		t.Fatalf("expected missing symbols, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "predates ABI versioning, this build needs ABI 1") {
+		t.Errorf("unexpected message: %v", err)
+	}
+
+	err = checkABI(ABIVersion, requiredCapabilities, []string{"chameleon_buffer_free"})
+	if !strings.Contains(err.Error(), "does not export chameleon_buffer_free") {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	if Version() == "" {
+		t.Error("expected a version")
+	}
+}
+
+// ─────────────────────────────────────────────────────────────
+// Schema parsing and validation
+// ─────────────────────────────────────────────────────────────
+
+func TestParseSchema(t *testing.T) {
+	schemaJSON := parseTestSchema(t)
+	if !strings.Contains(schemaJSON, `"User"`) || !strings.Contains(schemaJSON, `"email"`) {
+		t.Errorf("unexpected schema JSON: %s", schemaJSON)
+	}
+
+	if _, err := ParseSchema("invalid syntax!!!"); err == nil {
+		t.Error("expected a parse error")
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	if err := ValidateSchema(testSchema); err != nil {
+		t.Errorf("expected a valid schema: %v", err)
+	}
+	if err := ValidateSchema("invalid syntax!!!"); err == nil {
+		t.Error("expected a validation error")
+	}
+}
+
+func TestValidateSchemaRaw(t *testing.T) {
+	out, err := ValidateSchemaRaw(testSchema)
+	if err != nil || !strings.Contains(out, `"valid":true`) {
+		t.Errorf("expected a valid result, got %q (%v)", out, err)
+	}
+
+	out, err = ValidateSchemaRaw("invalid syntax!!!")
+	if err == nil || !strings.Contains(out, `"valid":false`) {
+		t.Errorf("expected an invalid result, got %q (%v)", out, err)
+	}
+}
+
+// ─────────────────────────────────────────────────────────────
+// Stateless generation
+// ─────────────────────────────────────────────────────────────
+
+func TestGenerateSQL(t *testing.T) {
+	schemaJSON := parseTestSchema(t)
+
+	out, err := GenerateSQL(testQuery, schemaJSON)
+	if err != nil {
+		t.Fatalf("GenerateSQL failed: %v", err)
+	}
+	if !strings.Contains(out, "FROM users") {
+		t.Errorf("unexpected SQL: %s", out)
+	}
+
+	if _, err := GenerateSQL(`{"entity":"Ghost","filters":[],"includes":[],"order_by":[],"limit":null,"offset":null}`, schemaJSON); err == nil {
+		t.Error("expected an error for an unknown entity")
+	}
+	if _, err := GenerateSQL(testQuery, "not json"); err == nil {
+		t.Error("expected an error for an invalid schema")
+	}
+}
+
+func TestGenerateMigration(t *testing.T) {
+	out, err := GenerateMigration(parseTestSchema(t))
+	if err != nil {
+		t.Fatalf("GenerateMigration failed: %v", err)
+	}
+	if !strings.Contains(out, "CREATE TABLE users") {
+		t.Errorf("unexpected migration: %s", out)
+	}
+
+	if _, err := GenerateMigration("not json"); err == nil {
+		t.Error("expected an error for an invalid schema")
+	}
+}
+
+func TestGenerateMutationSQL_SchemaCache(t *testing.T) {
+	schemaJSON := parseTestSchema(t)
+
+	valid, sql := mutationResult(t, GenerateMutationSQL(testMutation, schemaJSON))
+	if !valid || !strings.Contains(sql, "DELETE FROM") {
+		t.Errorf("unexpected result: %v %s", valid, sql)
+	}
+
+	if valid, msg := mutationResult(t, SetSchemaCache(schemaJSON)); !valid {
+		t.Fatalf("SetSchemaCache failed: %s", msg)
+	}
+	if valid, msg := mutationResult(t, GenerateMutationSQL(testMutation, "")); !valid {
+		t.Errorf("expected the cached schema to be used: %s", msg)
+	}
+
+	if valid, msg := mutationResult(t, ClearSchemaCache()); !valid {
+		t.Fatalf("ClearSchemaCache failed: %s", msg)
+	}
+	if valid, msg := mutationResult(t, GenerateMutationSQL(testMutation, "")); valid || !strings.Contains(msg, "cache is empty") {
+		t.Errorf("expected an empty cache error, got %q", msg)
+	}
+
+	if valid, _ := mutationResult(t, SetSchemaCache("not json")); valid {
+		t.Error("expected an invalid schema error")
+	}
+}
+
+// ─────────────────────────────────────────────────────────────
+// Schema handles
+// ─────────────────────────────────────────────────────────────
+
+func TestSchemaHandle(t *testing.T) {
+	schema, err := LoadSchema(parseTestSchema(t))
+	if err != nil {
+		t.Fatalf("LoadSchema failed: %v", err)
+	}
+	defer schema.Close()
+
+	out, err := schema.GenerateSQL(testQuery)
+	if err != nil || !strings.Contains(out, "FROM users") {
+		t.Errorf("GenerateSQL: %q (%v)", out, err)
+	}
+	if _, err := schema.GenerateSQL("not json"); err == nil {
+		t.Error("expected an error for an invalid query")
+	}
+
+	out, err = schema.GenerateMigration()
+	if err != nil || !strings.Contains(out, "CREATE TABLE users") {
+		t.Errorf("GenerateMigration: %q (%v)", out, err)
+	}
+
+	if valid, sql := mutationResult(t, schema.GenerateMutationSQL(testMutation)); !valid || !strings.Contains(sql, "DELETE FROM") {
+		t.Errorf("GenerateMutationSQL: %v %s", valid, sql)
+	}
+	if valid, _ := mutationResult(t, schema.GenerateMutationSQL("not json")); valid {
+		t.Error("expected an error for an invalid mutation")
+	}
+}
+
+func TestSchemaHandle_Close(t *testing.T) {
+	schema, err := LoadSchema(parseTestSchema(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema.Close()
+	schema.Close() // no-op
+
+	if _, err := schema.GenerateSQL(testQuery); !errors.Is(err, errSchemaClosed) {
+		t.Errorf("GenerateSQL after Close: %v", err)
+	}
+	if _, err := schema.GenerateMigration(); !errors.Is(err, errSchemaClosed) {
+		t.Errorf("GenerateMigration after Close: %v", err)
+	}
+	if valid, msg := mutationResult(t, schema.GenerateMutationSQL(testMutation)); valid || msg != errSchemaClosed.Error() {
+		t.Errorf("GenerateMutationSQL after Close: %q", msg)
+	}
+}
+
+func TestLoadSchema_Invalid(t *testing.T) {
+	if _, err := LoadSchema(`{"entities": 42}`); err == nil || !strings.Contains(err.Error(), "deserialization") {
+		t.Errorf("expected a deserialization error, got %v", err)
+	}
+}
+
+// ─────────────────────────────────────────────────────────────
+// Encoded calls
+// ─────────────────────────────────────────────────────────────
+
+func TestGenerateSQLEncoded(t *testing.T) {
+	schema, err := LoadSchema(parseTestSchema(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer schema.Close()
+
+	var query map[string]interface{}
+	if err := json.Unmarshal([]byte(testQuery), &query); err != nil {
+		t.Fatal(err)
+	}
+
+	var fromJSON map[string]interface{}
+	err = schema.GenerateSQLEncoded(EncodingJSON, []byte(testQuery), func(result []byte) error {
+		return json.Unmarshal(result, &fromJSON)
+	})
+	if err != nil {
+		t.Fatalf("JSON call failed: %v", err)
+	}
+	if !strings.Contains(fromJSON["main_query"].(string), "FROM users") {
+		t.Errorf("unexpected answer: %v", fromJSON)
+	}
+
+	input, err := msgpack.AppendValue(nil, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fromMsgPack interface{}
+	err = schema.GenerateSQLEncoded(EncodingMsgPack, input, func(result []byte) error {
+		fromMsgPack, err = msgpack.Decode(result)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("MessagePack call failed: %v", err)
+	}
+	if fromMsgPack.(map[string]interface{})["main_query"] != fromJSON["main_query"] {
+		t.Errorf("encodings disagree: %v / %v", fromMsgPack, fromJSON)
+	}
+
+	// Errors come from the core's buffer
+	err = schema.GenerateSQLEncoded(Encoding(7), input, func([]byte) error { return nil })
+	if err == nil || err.Error() != "Unknown encoding 7" {
+		t.Errorf("expected an unknown encoding error, got %v", err)
+	}
+	err = schema.GenerateSQLEncoded(EncodingMsgPack, []byte(testQuery), func([]byte) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "Query deserialization error") {
+		t.Errorf("expected a deserialization error, got %v", err)
+	}
+	if err := schema.GenerateSQLEncoded(EncodingJSON, nil, func([]byte) error { return nil }); err == nil {
+		t.Error("expected an error for an empty query")
+	}
+
+	schema.Close()
+	if err := schema.GenerateSQLEncoded(EncodingJSON, []byte(testQuery), nil); !errors.Is(err, errSchemaClosed) {
+		t.Errorf("expected errSchemaClosed, got %v", err)
+	}
+}
+
+func TestGenerateMutationSQLEncoded(t *testing.T) {
+	schema, err := LoadSchema(parseTestSchema(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer schema.Close()
+
+	input, _ := msgpack.AppendValue(nil, map[string]interface{}{
+		"type":    "delete",
+		"entity":  "User",
+		"filters": map[string]interface{}{"id": "u1"},
+	})
+	var answer map[string]interface{}
+	err = schema.GenerateMutationSQLEncoded(EncodingMsgPack, input, func(result []byte) error {
+		v, err := msgpack.Decode(result)
+		answer, _ = v.(map[string]interface{})
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer["valid"] != true || !strings.Contains(answer["sql"].(string), "DELETE FROM") {
+		t.Errorf("unexpected answer: %v", answer)
+	}
+
+	err = schema.GenerateMutationSQLEncoded(EncodingJSON, []byte("not json"), func(result []byte) error {
+		valid, msg := mutationResult(t, string(result))
+		if valid || !strings.Contains(msg, "Invalid mutation") {
+			t.Errorf("expected an invalid mutation, got %q", msg)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuffer_Reuse(t *testing.T) {
+	schema, err := LoadSchema(parseTestSchema(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer schema.Close()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		schema.GenerateSQLEncoded(EncodingJSON, []byte(testQuery), func([]byte) error { return nil })
+	})
+	// The input is read in place and the answer written to a pooled
+	// buffer: only the []byte conversion allocates on the Go side
+	if allocs > 2 {
+		t.Errorf("expected at most 2 allocations per call, got %.0f", allocs)
+	}
+}
@@ -0,0 +1,131 @@
+// Run-time resolution of the core functions declared in symbols.h
+
+#define _GNU_SOURCE // RTLD_DEFAULT
+
+#include <stddef.h>
+#include "symbols.h"
+
+#ifdef _WIN32
+#include <windows.h>
+
+static void *lookup(const char *name) {
+    HMODULE core = GetModuleHandleA("chameleon_core.dll");
+    return core ? (void *)GetProcAddress(core, name) : NULL;
+}
+#else
+#include <dlfcn.h>
+
+static void *lookup(const char *name) {
+    return dlsym(RTLD_DEFAULT, name);
+}
+#endif
+
+static uint32_t (*p_abi_version)(void);
+static const char *(*p_capabilities)(void);
+static struct ChameleonSchema *(*p_schema_load)(const char *, char **);
+static void (*p_schema_free)(struct ChameleonSchema *);
+static enum ChameleonResult (*p_schema_generate_sql)(const struct ChameleonSchema *, const char *, char **);
+static enum ChameleonResult (*p_schema_generate_migration)(const struct ChameleonSchema *, char **);
+static char *(*p_schema_generate_mutation_sql)(const struct ChameleonSchema *, const char *);
+static enum ChameleonResult (*p_schema_generate_sql_encoded)(
+    const struct ChameleonSchema *, uint32_t, const uint8_t *, uintptr_t, struct ChameleonBuffer *);
+static enum ChameleonResult (*p_schema_generate_mutation_sql_encoded)(
+    const struct ChameleonSchema *, uint32_t, const uint8_t *, uintptr_t, struct ChameleonBuffer *);
+static void (*p_buffer_free)(struct ChameleonBuffer *);
+
+static struct {
+    const char *name;
+    void **ptr;
+} symbols[] = {
+    {"chameleon_abi_version", (void **)&p_abi_version},
+    {"chameleon_capabilities", (void **)&p_capabilities},
+    {"chameleon_schema_load", (void **)&p_schema_load},
+    {"chameleon_schema_free", (void **)&p_schema_free},
+    {"chameleon_schema_generate_sql", (void **)&p_schema_generate_sql},
+    {"chameleon_schema_generate_migration", (void **)&p_schema_generate_migration},
+    {"chameleon_schema_generate_mutation_sql", (void **)&p_schema_generate_mutation_sql},
+    {"chameleon_schema_generate_sql_encoded", (void **)&p_schema_generate_sql_encoded},
+    {"chameleon_schema_generate_mutation_sql_encoded", (void **)&p_schema_generate_mutation_sql_encoded},
+    {"chameleon_buffer_free", (void **)&p_buffer_free},
+};
+
+#define SYMBOL_COUNT ((int)(sizeof(symbols) / sizeof(symbols[0])))
+
+int ffi_resolve_symbols(void) {
+    int missing = 0;
+    for (int i = 0; i < SYMBOL_COUNT; i++) {
+        *symbols[i].ptr = lookup(symbols[i].name);
+        if (*symbols[i].ptr == NULL) {
+            missing++;
+        }
+    }
+    return missing;
+}
+
+int ffi_symbol_count(void) {
+    return SYMBOL_COUNT;
+}
+
+const char *ffi_symbol_name(int i) {
+    return symbols[i].name;
+}
+
+int ffi_symbol_found(int i) {
+    return *symbols[i].ptr != NULL;
+}
+
+// ─────────────────────────────────────────────────────────────
+// Trampolines
+// ─────────────────────────────────────────────────────────────
+
+uint32_t ffi_abi_version(void) {
+    return p_abi_version();
+}
+
+const char *ffi_capabilities(void) {
+    return p_capabilities();
+}
+
+struct ChameleonSchema *ffi_schema_load(const char *schema_json, char **error_out) {
+    return p_schema_load(schema_json, error_out);
+}
+
+void ffi_schema_free(struct ChameleonSchema *handle) {
+    p_schema_free(handle);
+}
+
+enum ChameleonResult ffi_schema_generate_sql(const struct ChameleonSchema *handle,
+                                             const char *query_json,
+                                             char **out) {
+    return p_schema_generate_sql(handle, query_json, out);
+}
+
+enum ChameleonResult ffi_schema_generate_migration(const struct ChameleonSchema *handle,
+                                                   char **out) {
+    return p_schema_generate_migration(handle, out);
+}
+
+char *ffi_schema_generate_mutation_sql(const struct ChameleonSchema *handle,
+                                       const char *mutation_json) {
+    return p_schema_generate_mutation_sql(handle, mutation_json);
+}
+
+enum ChameleonResult ffi_schema_generate_sql_encoded(const struct ChameleonSchema *handle,
+                                                     uint32_t encoding,
+                                                     const uint8_t *input,
+                                                     uintptr_t input_len,
+                                                     struct ChameleonBuffer *out) {
+    return p_schema_generate_sql_encoded(handle, encoding, input, input_len, out);
+}
+
+enum ChameleonResult ffi_schema_generate_mutation_sql_encoded(const struct ChameleonSchema *handle,
+                                                              uint32_t encoding,
+                                                              const uint8_t *input,
+                                                              uintptr_t input_len,
+                                                              struct ChameleonBuffer *out) {
+    return p_schema_generate_mutation_sql_encoded(handle, encoding, input, input_len, out);
+}
+
+void ffi_buffer_free(struct ChameleonBuffer *buf) {
+    p_buffer_free(buf);
+}
@@ -0,0 +1,46 @@
+#ifndef CHAMELEON_FFI_SYMBOLS_H
+#define CHAMELEON_FFI_SYMBOLS_H
+
+#include "chameleon.h"
+
+/*
+ * Core functions added after the first release (ABI handshake, schema
+ * handles, encoded calls) are resolved when the package loads instead of
+ * linked, so a binary paired with an older libchameleon_core starts and
+ * reports the mismatch (see abi.go) rather than failing in the dynamic
+ * loader. Each ffi_ function calls the resolved symbol of the same name
+ * with the chameleon_ prefix; call it only when that symbol was found.
+ */
+
+/* Resolve the symbols; returns how many are missing */
+int ffi_resolve_symbols(void);
+int ffi_symbol_count(void);
+const char *ffi_symbol_name(int i);
+int ffi_symbol_found(int i);
+
+uint32_t ffi_abi_version(void);
+const char *ffi_capabilities(void);
+
+struct ChameleonSchema *ffi_schema_load(const char *schema_json, char **error_out);
+void ffi_schema_free(struct ChameleonSchema *handle);
+enum ChameleonResult ffi_schema_generate_sql(const struct ChameleonSchema *handle,
+                                             const char *query_json,
+                                             char **out);
+enum ChameleonResult ffi_schema_generate_migration(const struct ChameleonSchema *handle,
+                                                   char **out);
+char *ffi_schema_generate_mutation_sql(const struct ChameleonSchema *handle,
+                                       const char *mutation_json);
+
+enum ChameleonResult ffi_schema_generate_sql_encoded(const struct ChameleonSchema *handle,
+                                                     uint32_t encoding,
+                                                     const uint8_t *input,
+                                                     uintptr_t input_len,
+                                                     struct ChameleonBuffer *out);
+enum ChameleonResult ffi_schema_generate_mutation_sql_encoded(const struct ChameleonSchema *handle,
+                                                              uint32_t encoding,
+                                                              const uint8_t *input,
+                                                              uintptr_t input_len,
+                                                              struct ChameleonBuffer *out);
+void ffi_buffer_free(struct ChameleonBuffer *buf);
+
+#endif
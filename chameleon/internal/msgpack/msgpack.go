@@ -0,0 +1,438 @@
+// Package msgpack is a minimal MessagePack codec for the payloads
+// exchanged with the Rust core. It covers the JSON data model only: nil,
+// bools, integers, floats, strings, arrays and maps with string keys.
+//
+// Encoding appends to a caller-owned slice (Append*), so buffers can be
+// reused; decoding reads values one at a time (Reader) or whole (Decode).
+package msgpack
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ============================================================
+// ENCODING
+// ============================================================
+
+// AppendNil appends nil
+func AppendNil(b []byte) []byte {
+	return append(b, 0xc0)
+}
+
+// AppendBool appends a bool
+func AppendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0xc3)
+	}
+	return append(b, 0xc2)
+}
+
+// AppendInt appends an integer in its smallest form
+func AppendInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return AppendUint(b, uint64(v))
+	case v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(v))
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(v))
+}
+
+// AppendUint appends an unsigned integer in its smallest form
+func AppendUint(b []byte, v uint64) []byte {
+	switch {
+	case v < 0x80:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(v))
+	case v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(b, 0xcf), v)
+}
+
+// AppendFloat appends a float64
+func AppendFloat(b []byte, v float64) []byte {
+	return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(v))
+}
+
+// AppendString appends a string
+func AppendString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = binary.BigEndian.AppendUint16(append(b, 0xda), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+// AppendStrings appends a string array; nil appends nil
+func AppendStrings(b []byte, values []string) []byte {
+	if values == nil {
+		return AppendNil(b)
+	}
+	b = AppendArrayHeader(b, len(values))
+	for _, v := range values {
+		b = AppendString(b, v)
+	}
+	return b
+}
+
+// AppendArrayHeader starts an array of n values
+func AppendArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xdc), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xdd), uint32(n))
+}
+
+// AppendMapHeader starts a map of n key/value pairs
+func AppendMapHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xde), uint16(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xdf), uint32(n))
+}
+
+// AppendValue appends any value of the JSON data model. Map keys are
+// sorted, so equal values always encode to the same bytes.
+func AppendValue(b []byte, v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return AppendNil(b), nil
+	case bool:
+		return AppendBool(b, v), nil
+	case int:
+		return AppendInt(b, int64(v)), nil
+	case int8:
+		return AppendInt(b, int64(v)), nil
+	case int16:
+		return AppendInt(b, int64(v)), nil
+	case int32:
+		return AppendInt(b, int64(v)), nil
+	case int64:
+		return AppendInt(b, v), nil
+	case uint:
+		return AppendUint(b, uint64(v)), nil
+	case uint8:
+		return AppendUint(b, uint64(v)), nil
+	case uint16:
+		return AppendUint(b, uint64(v)), nil
+	case uint32:
+		return AppendUint(b, uint64(v)), nil
+	case uint64:
+		return AppendUint(b, v), nil
+	case float32:
+		return AppendFloat(b, float64(v)), nil
+	case float64:
+		return AppendFloat(b, v), nil
+	case string:
+		return AppendString(b, v), nil
+	case []string:
+		return AppendStrings(b, v), nil
+	case []interface{}:
+		if v == nil {
+			return AppendNil(b), nil
+		}
+		b = AppendArrayHeader(b, len(v))
+		for _, item := range v {
+			var err error
+			if b, err = AppendValue(b, item); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	case map[string]interface{}:
+		if v == nil {
+			return AppendNil(b), nil
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b = AppendMapHeader(b, len(v))
+		for _, key := range keys {
+			b = AppendString(b, key)
+			var err error
+			if b, err = AppendValue(b, v[key]); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	}
+	return b, fmt.Errorf("msgpack: unsupported type %T", v)
+}
+
+// ============================================================
+// DECODING
+// ============================================================
+
+// maxDepth bounds the nesting of arrays and maps Decode accepts
+const maxDepth = 128
+
+// ErrShort is returned when the input ends in the middle of a value
+var ErrShort = errors.New("msgpack: unexpected end of input")
+
+// Reader decodes values from MessagePack bytes, one at a time
+type Reader struct {
+	data []byte
+	pos  int
+}
+
+// NewReader returns a Reader over data
+func NewReader(data []byte) *Reader {
+	return &Reader{data: data}
+}
+
+// Done reports whether all input was read
+func (r *Reader) Done() bool {
+	return r.pos == len(r.data)
+}
+
+func (r *Reader) take(n int) ([]byte, error) {
+	if n < 0 || len(r.data)-r.pos < n {
+		return nil, ErrShort
+	}
+	out := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return out, nil
+}
+
+func (r *Reader) byte() (byte, error) {
+	b, err := r.take(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (r *Reader) uint(size int) (uint64, error) {
+	b, err := r.take(size)
+	if err != nil {
+		return 0, err
+	}
+	switch size {
+	case 1:
+		return uint64(b[0]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b)), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b)), nil
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// TryNil consumes a nil and reports whether there was one
+func (r *Reader) TryNil() bool {
+	if r.pos < len(r.data) && r.data[r.pos] == 0xc0 {
+		r.pos++
+		return true
+	}
+	return false
+}
+
+// ReadArrayHeader reads the length of an array
+func (r *Reader) ReadArrayHeader() (int, error) {
+	marker, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case marker&0xf0 == 0x90:
+		return int(marker & 0x0f), nil
+	case marker == 0xdc:
+		n, err := r.uint(2)
+		return int(n), err
+	case marker == 0xdd:
+		n, err := r.uint(4)
+		return int(n), err
+	}
+	return 0, fmt.Errorf("msgpack: expected an array, got 0x%02x", marker)
+}
+
+// ReadMapHeader reads the number of key/value pairs of a map
+func (r *Reader) ReadMapHeader() (int, error) {
+	marker, err := r.byte()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case marker&0xf0 == 0x80:
+		return int(marker & 0x0f), nil
+	case marker == 0xde:
+		n, err := r.uint(2)
+		return int(n), err
+	case marker == 0xdf:
+		n, err := r.uint(4)
+		return int(n), err
+	}
+	return 0, fmt.Errorf("msgpack: expected a map, got 0x%02x", marker)
+}
+
+// ReadString reads a string (copied out of the input)
+func (r *Reader) ReadString() (string, error) {
+	marker, err := r.byte()
+	if err != nil {
+		return "", err
+	}
+	return r.readString(marker)
+}
+
+func (r *Reader) readString(marker byte) (string, error) {
+	var n uint64
+	var err error
+	switch {
+	case marker&0xe0 == 0xa0:
+		n = uint64(marker & 0x1f)
+	case marker == 0xd9:
+		n, err = r.uint(1)
+	case marker == 0xda:
+		n, err = r.uint(2)
+	case marker == 0xdb:
+		n, err = r.uint(4)
+	default:
+		return "", fmt.Errorf("msgpack: expected a string, got 0x%02x", marker)
+	}
+	if err != nil {
+		return "", err
+	}
+	b, err := r.take(int(n))
+	return string(b), err
+}
+
+// ReadValue reads any value: nil, bool, int64 (uint64 above MaxInt64),
+// float64, string, []interface{} or map[string]interface{}
+func (r *Reader) ReadValue() (interface{}, error) {
+	return r.readValue(0)
+}
+
+// Skip reads past the next value
+func (r *Reader) Skip() error {
+	_, err := r.readValue(0)
+	return err
+}
+
+func (r *Reader) readValue(depth int) (interface{}, error) {
+	if depth > maxDepth {
+		return nil, errors.New("msgpack: nesting too deep")
+	}
+
+	marker, err := r.byte()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case marker <= 0x7f:
+		return int64(marker), nil
+	case marker >= 0xe0:
+		return int64(int8(marker)), nil
+	case marker&0xe0 == 0xa0, marker >= 0xd9 && marker <= 0xdb:
+		return r.readString(marker)
+	case marker&0xf0 == 0x90, marker == 0xdc, marker == 0xdd:
+		r.pos--
+		n, err := r.ReadArrayHeader()
+		if err != nil {
+			return nil, err
+		}
+		items := make([]interface{}, 0, min(n, len(r.data)-r.pos))
+		for range n {
+			item, err := r.readValue(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case marker&0xf0 == 0x80, marker == 0xde, marker == 0xdf:
+		r.pos--
+		n, err := r.ReadMapHeader()
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{}, min(n, len(r.data)-r.pos))
+		for range n {
+			key, err := r.ReadString()
+			if err != nil {
+				return nil, fmt.Errorf("msgpack: map key: %w", err)
+			}
+			if m[key], err = r.readValue(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+
+	switch marker {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := r.uint(1 << (marker - 0xcc))
+		if err != nil || u > math.MaxInt64 {
+			return u, err
+		}
+		return int64(u), nil
+	case 0xd0:
+		u, err := r.uint(1)
+		return int64(int8(u)), err
+	case 0xd1:
+		u, err := r.uint(2)
+		return int64(int16(u)), err
+	case 0xd2:
+		u, err := r.uint(4)
+		return int64(int32(u)), err
+	case 0xd3:
+		u, err := r.uint(8)
+		return int64(u), err
+	case 0xca:
+		u, err := r.uint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := r.uint(8)
+		return math.Float64frombits(u), err
+	}
+	return nil, fmt.Errorf("msgpack: unsupported type 0x%02x", marker)
+}
+
+// Decode decodes a single value spanning all of data (see ReadValue)
+func Decode(data []byte) (interface{}, error) {
+	r := NewReader(data)
+	v, err := r.ReadValue()
+	if err != nil {
+		return nil, err
+	}
+	if !r.Done() {
+		return nil, fmt.Errorf("msgpack: %d trailing bytes", len(data)-r.pos)
+	}
+	return v, nil
+}
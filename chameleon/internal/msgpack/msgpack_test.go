@@ -0,0 +1,117 @@
+package msgpack
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRoundtrip(t *testing.T) {
+	values := []interface{}{
+		nil,
+		true,
+		false,
+		int64(0), int64(127), int64(128), int64(255), int64(256), int64(65536), int64(1 << 32), int64(math.MaxInt64),
+		int64(-1), int64(-32), int64(-33), int64(-128), int64(-129), int64(-32769), int64(math.MinInt64),
+		uint64(math.MaxUint64),
+		1.5, -0.25, 1e300,
+		"", "héllo", strings.Repeat("x", 31), strings.Repeat("x", 32), strings.Repeat("x", 300), strings.Repeat("x", 70000),
+		[]interface{}{},
+		[]interface{}{int64(1), "a", nil, []interface{}{true}},
+		make([]interface{}, 20),
+		map[string]interface{}{},
+		map[string]interface{}{"entity": "User", "limit": nil, "filters": []interface{}{map[string]interface{}{"Param": int64(1)}}},
+	}
+
+	for _, want := range values {
+		data, err := AppendValue(nil, want)
+		if err != nil {
+			t.Fatalf("AppendValue(%v): %v", want, err)
+		}
+		got, err := Decode(data)
+		if err != nil {
+			t.Fatalf("Decode(%v): %v", want, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("roundtrip: got %#v, want %#v", got, want)
+		}
+	}
+}
+
+func TestAppendValue_Compact(t *testing.T) {
+	data, err := AppendValue(nil, map[string]interface{}{"b": []string{"x"}, "a": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x91, 0xa1, 'x'}
+	if !bytes.Equal(data, want) {
+		t.Errorf("got % x, want % x", data, want)
+	}
+
+	// Go types normalize to the JSON data model
+	for _, v := range []interface{}{int8(-5), int16(300), int32(-70000), uint(7), uint8(200), uint16(60000), uint32(1 << 31), float32(0.5)} {
+		data, err := AppendValue(nil, v)
+		if err != nil {
+			t.Fatalf("AppendValue(%T): %v", v, err)
+		}
+		if _, err := Decode(data); err != nil {
+			t.Errorf("Decode(%T): %v", v, err)
+		}
+	}
+
+	if _, err := AppendValue(nil, struct{}{}); err == nil {
+		t.Error("expected an error for an unsupported type")
+	}
+}
+
+func TestReader(t *testing.T) {
+	b := AppendMapHeader(nil, 2)
+	b = AppendString(b, "main_query")
+	b = AppendString(b, "SELECT 1")
+	b = AppendString(b, "eager_queries")
+	b = AppendNil(b)
+
+	r := NewReader(b)
+	n, err := r.ReadMapHeader()
+	if err != nil || n != 2 {
+		t.Fatalf("ReadMapHeader: %d, %v", n, err)
+	}
+	if key, _ := r.ReadString(); key != "main_query" {
+		t.Errorf("unexpected key %q", key)
+	}
+	if value, _ := r.ReadString(); value != "SELECT 1" {
+		t.Errorf("unexpected value %q", value)
+	}
+	if err := r.Skip(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.TryNil() || !r.Done() {
+		t.Error("expected nil at the end")
+	}
+
+	if _, err := NewReader([]byte{0x01}).ReadArrayHeader(); err == nil {
+		t.Error("expected an error reading an int as an array")
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := map[string][]byte{
+		"end of input": {0xa5, 'a'},
+		"trailing":     {0x01, 0x02},
+		"map key":      {0x81, 0x01, 0x01},
+		"unsupported":  {0xc4, 0x00},
+		"too deep":     bytes.Repeat([]byte{0x91}, 200),
+	}
+	for want, data := range tests {
+		if _, err := Decode(data); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Decode(% x): expected %q error, got %v", data, want, err)
+		}
+	}
+
+	// Lengths beyond the input fail without allocating them
+	if _, err := Decode([]byte{0xdd, 0xff, 0xff, 0xff, 0xff}); err != ErrShort {
+		t.Errorf("expected ErrShort, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package chamerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// ============================================================
+// ERROR COLLECTION
+// ============================================================
+
+// ValidationErrors collects every field-level problem of a mutation
+// Items are sorted by field, so the result doesn't depend on map order.
+// errors.As and errors.Is reach the individual items:
+//
+//	var mismatch *chamerr.TypeMismatchError
+//	if errors.As(err, &mismatch) { ... }
+type ValidationErrors struct {
+	Errors []MutationError
+}
+
+func (e *ValidationErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ValidationErrors: %d problem(s)", len(e.Errors))
+	for _, issue := range e.Issues() {
+		fmt.Fprintf(&b, "\n  - %s: %s", issue.Field, issue.Message)
+		if issue.Suggestion != "" {
+			fmt.Fprintf(&b, " (%s)", issue.Suggestion)
+		}
+	}
+	return b.String()
+}
+
+func (e *ValidationErrors) Code() string     { return CodeValidationErrors }
+func (e *ValidationErrors) IsMutationError() {}
+
+// Unwrap exposes the items to errors.Is and errors.As
+func (e *ValidationErrors) Unwrap() []error {
+	out := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		out[i] = err
+	}
+	return out
+}
+
+// Issues returns the items as code/field/message/suggestion
+func (e *ValidationErrors) Issues() []Issue {
+	issues := make([]Issue, len(e.Errors))
+	for i, err := range e.Errors {
+		issues[i] = Describe(err)
+	}
+	return issues
+}
+
+// MarshalJSON renders {"code": "VALIDATION_ERRORS", "errors": [...]}
+func (e *ValidationErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Code   string  `json:"code"`
+		Errors []Issue `json:"errors"`
+	}{e.Code(), e.Issues()})
+}
+
+// LogValue renders the code and one group per item
+func (e *ValidationErrors) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("code", e.Code())}
+	for i, issue := range e.Issues() {
+		attrs = append(attrs, slog.Any(fmt.Sprint(i), issue))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Add appends err, flattening nested collections
+func (e *ValidationErrors) Add(err error) {
+	var nested *ValidationErrors
+	switch {
+	case err == nil:
+	case errors.As(err, &nested) && nested != e:
+		e.Errors = append(e.Errors, nested.Errors...)
+	default:
+		if me, ok := err.(MutationError); ok {
+			e.Errors = append(e.Errors, me)
+		} else {
+			e.Errors = append(e.Errors, &ValidationError{Type: "invalid", Message: err.Error()})
+		}
+	}
+}
+
+// Err returns the sorted collection, or nil if nothing was collected
+func (e *ValidationErrors) Err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	sort.SliceStable(e.Errors, func(i, j int) bool {
+		a, b := Describe(e.Errors[i]), Describe(e.Errors[j])
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Code < b.Code
+	})
+	return e
+}
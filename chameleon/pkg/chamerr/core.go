@@ -0,0 +1,193 @@
+package chamerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// ============================================================
+// CORE ERRORS (reported by the Rust core through the FFI)
+// ============================================================
+
+// ParseError: The schema source has a syntax error
+type ParseError struct {
+	Message    string
+	Line       int
+	Column     int
+	Snippet    string // source excerpt pointing at the error, if any
+	Suggestion string
+	Token      string // offending token, if any
+}
+
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ParseError: %s\n  --> schema.cham:%d:%d", e.Message, e.Line, e.Column)
+	if e.Snippet != "" {
+		fmt.Fprintf(&b, "\n%s", e.Snippet)
+	}
+	if e.Suggestion != "" {
+		fmt.Fprintf(&b, "\n  Help: %s", strings.ReplaceAll(e.Suggestion, "\n", "\n  "))
+	}
+	return b.String()
+}
+
+func (e *ParseError) Code() string                 { return CodeParse }
+func (e *ParseError) Is(target error) bool         { return isAny(target, ErrParse, ErrSchema) }
+func (e *ParseError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *ParseError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// SchemaError: The schema parsed but failed type checking (unknown
+// entity in a relation, invalid constraint, missing primary key...)
+type SchemaError struct {
+	Message    string
+	Suggestion string
+}
+
+func (e *SchemaError) Error() string {
+	if e.Suggestion == "" {
+		return "SchemaError: " + e.Message
+	}
+	return fmt.Sprintf("SchemaError: %s\n  Suggestion: %s", e.Message, e.Suggestion)
+}
+
+func (e *SchemaError) Code() string                 { return CodeSchema }
+func (e *SchemaError) Is(target error) bool         { return target == ErrSchema }
+func (e *SchemaError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *SchemaError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// SchemaErrors collects the problems of a schema that failed validation
+type SchemaErrors struct {
+	Errors []Error // *ParseError or *SchemaError
+}
+
+func (e *SchemaErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "SchemaErrors: %d problem(s)", len(e.Errors))
+	for _, err := range e.Errors {
+		fmt.Fprintf(&b, "\n  - %s", Describe(err).Message)
+	}
+	return b.String()
+}
+
+func (e *SchemaErrors) Code() string         { return CodeSchemaErrors }
+func (e *SchemaErrors) Is(target error) bool { return target == ErrSchema }
+
+// Unwrap exposes the items to errors.Is and errors.As
+func (e *SchemaErrors) Unwrap() []error {
+	out := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		out[i] = err
+	}
+	return out
+}
+
+// MarshalJSON renders {"code": "SCHEMA_ERRORS", "errors": [...]}
+func (e *SchemaErrors) MarshalJSON() ([]byte, error) {
+	issues := make([]Issue, len(e.Errors))
+	for i, err := range e.Errors {
+		issues[i] = Describe(err)
+	}
+	return json.Marshal(struct {
+		Code   string  `json:"code"`
+		Errors []Issue `json:"errors"`
+	}{e.Code(), issues})
+}
+
+// LogValue renders the code and one group per item
+func (e *SchemaErrors) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("code", e.Code())}
+	for i, err := range e.Errors {
+		attrs = append(attrs, slog.Any(fmt.Sprint(i), Describe(err)))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// InternalError: The core failed for a reason unrelated to the input
+type InternalError struct {
+	Message string
+}
+
+func (e *InternalError) Error() string                { return "InternalError: " + e.Message }
+func (e *InternalError) Code() string                 { return CodeInternal }
+func (e *InternalError) Is(target error) bool         { return target == ErrInternal }
+func (e *InternalError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *InternalError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// ─────────────────────────────────────────────────────────────
+// Decoding core errors
+// ─────────────────────────────────────────────────────────────
+
+// coreIssue is one error as serialized by the core, either an item of a
+// validation result or the data of a ParseError
+type coreIssue struct {
+	Kind       string  `json:"kind"`
+	Message    string  `json:"message"`
+	Line       int     `json:"line"`
+	Column     int     `json:"column"`
+	Snippet    *string `json:"snippet"`
+	Suggestion *string `json:"suggestion"`
+	Token      *string `json:"token"`
+}
+
+func (c coreIssue) toError() Error {
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	if c.Kind == "ParseError" && c.Line > 0 {
+		return &ParseError{
+			Message:    c.Message,
+			Line:       c.Line,
+			Column:     c.Column,
+			Snippet:    deref(c.Snippet),
+			Suggestion: deref(c.Suggestion),
+			Token:      deref(c.Token),
+		}
+	}
+	return &SchemaError{Message: c.Message, Suggestion: deref(c.Suggestion)}
+}
+
+// FromCore maps an error message from the Rust core onto this package.
+// It understands the validation result ({"valid": false, "errors": [...]})
+// and the tagged ChameleonError ({"kind": "ParseError", "data": {...}});
+// any other message becomes a SchemaError. A single problem is returned
+// as is, several as *SchemaErrors.
+func FromCore(raw string) error {
+	var result struct {
+		Valid  *bool       `json:"valid"`
+		Errors []coreIssue `json:"errors"`
+	}
+	if json.Unmarshal([]byte(raw), &result) == nil && result.Valid != nil {
+		switch len(result.Errors) {
+		case 0:
+			return &SchemaError{Message: "schema validation failed"}
+		case 1:
+			return result.Errors[0].toError()
+		}
+		errs := &SchemaErrors{}
+		for _, issue := range result.Errors {
+			errs.Errors = append(errs.Errors, issue.toError())
+		}
+		return errs
+	}
+
+	var tagged struct {
+		Kind string          `json:"kind"`
+		Data json.RawMessage `json:"data"`
+	}
+	if json.Unmarshal([]byte(raw), &tagged) == nil && tagged.Kind != "" {
+		var issue coreIssue
+		json.Unmarshal(tagged.Data, &issue)
+		issue.Kind = tagged.Kind
+		if tagged.Kind == "InternalError" {
+			return &InternalError{Message: issue.Message}
+		}
+		return issue.toError()
+	}
+
+	return &SchemaError{Message: raw}
+}
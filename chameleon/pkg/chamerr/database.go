@@ -0,0 +1,97 @@
+package chamerr
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ============================================================
+// DATABASE ERRORS (Postgres errors mapped back to the schema)
+// ============================================================
+
+// UniqueViolationError: Postgres rejected a duplicate value (UNIQUE or
+// primary key constraint)
+type UniqueViolationError struct {
+	Entity     string
+	Field      string      // "email", or "a, b" for multi-column keys
+	Value      interface{} // duplicated value, as reported by Postgres
+	Constraint string      // "users_email_key"
+	Suggestion string
+	Err        *pgconn.PgError
+}
+
+func (e *UniqueViolationError) Error() string {
+	return fmt.Sprintf(
+		"UniqueViolationError: %s.%s must be unique\n"+
+			"  Value: %v\n"+
+			"  Constraint: %s\n"+
+			"  Suggestion: %s",
+		e.Entity, e.Field, e.Value, e.Constraint, e.Suggestion,
+	)
+}
+
+func (e *UniqueViolationError) Code() string                 { return CodeUniqueViolation }
+func (e *UniqueViolationError) IsMutationError()             {}
+func (e *UniqueViolationError) Is(target error) bool         { return isAny(target, ErrConstraint, ErrUnique) }
+func (e *UniqueViolationError) Unwrap() error                { return e.Err }
+func (e *UniqueViolationError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *UniqueViolationError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// SerializationError: Transaction aborted by a serialization failure or a
+// deadlock. Retrying the whole transaction is expected to succeed.
+type SerializationError struct {
+	SQLState string // "40001" (serialization failure) or "40P01" (deadlock)
+	Message  string
+	Err      *pgconn.PgError
+}
+
+func (e *SerializationError) Error() string {
+	return fmt.Sprintf(
+		"SerializationError: Transaction aborted by a concurrent transaction\n"+
+			"  Detail: %s (SQLSTATE %s)\n"+
+			"  Suggestion: Retry the transaction",
+		e.Message, e.SQLState,
+	)
+}
+
+func (e *SerializationError) Code() string                 { return CodeSerialization }
+func (e *SerializationError) IsMutationError()             {}
+func (e *SerializationError) Is(target error) bool         { return target == ErrSerialization }
+func (e *SerializationError) Unwrap() error                { return e.Err }
+func (e *SerializationError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *SerializationError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// pgError returns err as an error, nil if it is nil: errors raised by the
+// validator have no Postgres error to unwrap
+func pgError(err *pgconn.PgError) error {
+	if err == nil {
+		return nil
+	}
+	return err
+}
+
+// ============================================================
+// RESULT ERRORS
+// ============================================================
+
+// ScanError reports a value that can't be stored in a struct field
+type ScanError struct {
+	Column string
+	Field  string // Go field path, e.g. "User.Orders[0].Total"
+	From   string // Go type of the value read
+	To     string // Go type of the field
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf(
+		"ScanError: cannot scan column '%s' (%s) into %s (%s)",
+		e.Column, e.From, e.Field, e.To,
+	)
+}
+
+func (e *ScanError) Code() string                 { return CodeScan }
+func (e *ScanError) Is(target error) bool         { return target == ErrScan }
+func (e *ScanError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *ScanError) LogValue() slog.Value         { return Describe(e).LogValue() }
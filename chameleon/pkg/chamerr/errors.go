@@ -0,0 +1,291 @@
+// Package chamerr defines the errors returned by ChameleonDB.
+//
+// Every error has a stable Code() for programmatic handling, matches one or
+// more sentinels with errors.Is, renders as JSON with json.Marshal and as a
+// structured group with log/slog. The engine and mutation packages re-export
+// these types, so errors.As works the same against either package.
+//
+//	if errors.Is(err, chamerr.ErrNotFound) { ... }
+//
+//	var dup *chamerr.UniqueViolationError
+//	if errors.As(err, &dup) { ... }
+//
+// # Code catalog
+//
+//	Code                              Type                        Sentinels
+//	VALIDATION_ERROR                  *ValidationError            ErrValidation
+//	VALIDATION_ERRORS                 *ValidationErrors           (its items)
+//	TYPE_MISMATCH                     *TypeMismatchError          ErrValidation
+//	LENGTH_EXCEEDED                   *LengthExceededError        ErrValidation
+//	FORMAT_ERROR                      *FieldFormatError           ErrValidation
+//	<TYPE>_CONSTRAINT                 *ConstraintError            ErrConstraint (+ ErrUnique, ErrNotNull, ErrForeignKey)
+//	UNIQUE_CONSTRAINT_VIOLATION       *UniqueConstraintError      ErrConstraint, ErrUnique
+//	UNIQUE_VIOLATION                  *UniqueViolationError       ErrConstraint, ErrUnique
+//	NOT_NULL_VIOLATION                *NotNullError               ErrConstraint, ErrNotNull
+//	FOREIGN_KEY_VIOLATION             *ForeignKeyError            ErrConstraint, ErrForeignKey
+//	FOREIGN_KEY_CONSTRAINT_VIOLATION  *ForeignKeyConstraintError  ErrConstraint, ErrForeignKey
+//	UNKNOWN_FIELD                     *UnknownFieldError          ErrUnknownField
+//	UNKNOWN_RELATION                  *UnknownRelationError       ErrUnknownRelation
+//	UNKNOWN_ENTITY                    *UnknownEntityError         ErrUnknownEntity
+//	NOT_FOUND                         *NotFoundError              ErrNotFound
+//	CONFLICT                          *ConflictError              ErrConflict
+//	SERIALIZATION_FAILURE             *SerializationError         ErrSerialization
+//	SAFETY_VIOLATION                  *SafetyError                ErrSafety
+//	AUTHORIZATION_DENIED              *AuthorizationError         ErrUnauthorized
+//	SCAN_ERROR                        *ScanError                  ErrScan
+//	PARSE_ERROR                       *ParseError                 ErrParse, ErrSchema
+//	SCHEMA_INVALID                    *SchemaError                ErrSchema
+//	SCHEMA_ERRORS                     *SchemaErrors               ErrSchema (+ its items)
+//	INTERNAL_ERROR                    *InternalError              ErrInternal
+//
+// <TYPE> is the upper-cased ConstraintError.Type: FOREIGN_KEY_CONSTRAINT,
+// CHECK_CONSTRAINT, PRIMARY_KEY_CONSTRAINT, ...
+package chamerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// ============================================================
+// BASE ERROR INTERFACES
+// ============================================================
+
+// Error is implemented by every error of this package
+type Error interface {
+	error
+	Code() string // Error code for programmatic handling
+}
+
+// MutationError is the base interface for all mutation errors
+type MutationError interface {
+	Error
+	IsMutationError() // Marker method
+}
+
+// ============================================================
+// CODES
+// ============================================================
+
+const (
+	CodeValidation           = "VALIDATION_ERROR"
+	CodeValidationErrors     = "VALIDATION_ERRORS"
+	CodeTypeMismatch         = "TYPE_MISMATCH"
+	CodeLengthExceeded       = "LENGTH_EXCEEDED"
+	CodeFormat               = "FORMAT_ERROR"
+	CodeUniqueConstraint     = "UNIQUE_CONSTRAINT_VIOLATION"
+	CodeUniqueViolation      = "UNIQUE_VIOLATION"
+	CodeNotNull              = "NOT_NULL_VIOLATION"
+	CodeForeignKey           = "FOREIGN_KEY_VIOLATION"
+	CodeForeignKeyDependents = "FOREIGN_KEY_CONSTRAINT_VIOLATION"
+	CodeUnknownField         = "UNKNOWN_FIELD"
+	CodeUnknownRelation      = "UNKNOWN_RELATION"
+	CodeUnknownEntity        = "UNKNOWN_ENTITY"
+	CodeNotFound             = "NOT_FOUND"
+	CodeConflict             = "CONFLICT"
+	CodeSerialization        = "SERIALIZATION_FAILURE"
+	CodeSafety               = "SAFETY_VIOLATION"
+	CodeAuthorization        = "AUTHORIZATION_DENIED"
+	CodeScan                 = "SCAN_ERROR"
+	CodeParse                = "PARSE_ERROR"
+	CodeSchema               = "SCHEMA_INVALID"
+	CodeSchemaErrors         = "SCHEMA_ERRORS"
+	CodeInternal             = "INTERNAL_ERROR"
+	CodeUnknown              = "UNKNOWN_ERROR" // not an error of this package
+)
+
+// ============================================================
+// SENTINELS (for errors.Is)
+// ============================================================
+
+var (
+	ErrValidation      = errors.New("chameleon: validation failed")
+	ErrConstraint      = errors.New("chameleon: constraint violation")
+	ErrUnique          = errors.New("chameleon: unique violation")
+	ErrNotNull         = errors.New("chameleon: not null violation")
+	ErrForeignKey      = errors.New("chameleon: foreign key violation")
+	ErrUnknownEntity   = errors.New("chameleon: unknown entity")
+	ErrUnknownField    = errors.New("chameleon: unknown field")
+	ErrUnknownRelation = errors.New("chameleon: unknown relation")
+	ErrNotFound        = errors.New("chameleon: not found")
+	ErrConflict        = errors.New("chameleon: conflict")
+	ErrSerialization   = errors.New("chameleon: serialization failure")
+	ErrSafety          = errors.New("chameleon: blocked by safety guard")
+	ErrUnauthorized    = errors.New("chameleon: not authorized")
+	ErrScan            = errors.New("chameleon: scan failed")
+	ErrParse           = errors.New("chameleon: schema parse error")
+	ErrSchema          = errors.New("chameleon: invalid schema")
+	ErrInternal        = errors.New("chameleon: internal error")
+)
+
+// isAny reports whether target is one of sentinels
+func isAny(target error, sentinels ...error) bool {
+	for _, s := range sentinels {
+		if target == s {
+			return true
+		}
+	}
+	return false
+}
+
+// ============================================================
+// STRUCTURED FORM (JSON, slog)
+// ============================================================
+
+// Issue is the structured form of an error, used for JSON and slog
+type Issue struct {
+	Code       string `json:"code"`
+	Entity     string `json:"entity,omitempty"`
+	Field      string `json:"field,omitempty"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+}
+
+// LogValue renders the issue as a slog group, skipping empty attributes
+func (i Issue) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("code", i.Code)}
+	for _, a := range []slog.Attr{
+		slog.String("entity", i.Entity),
+		slog.String("field", i.Field),
+		slog.String("message", i.Message),
+		slog.String("suggestion", i.Suggestion),
+	} {
+		if a.Value.String() != "" {
+			attrs = append(attrs, a)
+		}
+	}
+	if i.Line > 0 {
+		attrs = append(attrs, slog.Int("line", i.Line), slog.Int("column", i.Column))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func marshalError(err error) ([]byte, error) {
+	return json.Marshal(Describe(err))
+}
+
+// Describe extracts code, entity, field, message and suggestion from err,
+// looking through wrapping errors. Errors outside this package get
+// CodeUnknown and their first line.
+func Describe(err error) Issue {
+	var ce Error
+	if errors.As(err, &ce) {
+		err = ce
+	}
+	issue := Issue{Code: ErrorCode(err)}
+
+	switch e := err.(type) {
+	case *ValidationError:
+		issue.Field, issue.Message = e.Field, e.Message
+		if issue.Message == "" {
+			issue.Message = e.Type
+		}
+	case *TypeMismatchError:
+		issue.Field, issue.Suggestion = e.Field, e.Suggestion
+		issue.Message = fmt.Sprintf("expected %s, got %s", e.ExpectedType, e.ReceivedType)
+	case *LengthExceededError:
+		issue.Field = e.Field
+		issue.Message = fmt.Sprintf("length %d exceeds the maximum of %d", e.Actual, e.MaxLen)
+		issue.Suggestion = fmt.Sprintf("Use at most %d characters", e.MaxLen)
+	case *FieldFormatError:
+		issue.Field, issue.Suggestion = e.Field, e.Suggestion
+		issue.Message = fmt.Sprintf("invalid %s format", e.Format)
+	case *NotNullError:
+		issue.Entity, issue.Field, issue.Suggestion = e.Entity, e.Field, e.Suggestion
+		issue.Message = "value is required"
+	case *ConstraintError:
+		issue.Entity, issue.Field, issue.Suggestion = e.Entity, e.Field, e.Suggestion
+		issue.Message = fmt.Sprintf("%s constraint violated", e.Type)
+	case *UniqueConstraintError:
+		issue.Entity, issue.Field, issue.Suggestion = e.Table, e.Field, e.Suggestion
+		issue.Message = "value already exists"
+	case *UniqueViolationError:
+		issue.Entity, issue.Field, issue.Suggestion = e.Entity, e.Field, e.Suggestion
+		issue.Message = "value already exists"
+	case *ForeignKeyError:
+		issue.Entity, issue.Field, issue.Suggestion = e.ReferencedEntity, e.Field, e.Suggestion
+		issue.Message = fmt.Sprintf("referenced %s does not exist", e.ReferencedEntity)
+	case *ForeignKeyConstraintError:
+		issue.Entity, issue.Suggestion = e.Entity, e.Suggestion
+		issue.Message = fmt.Sprintf("%d %s row(s) still reference this record", e.DependentCount, e.DependentTable)
+	case *UnknownFieldError:
+		available := append([]string(nil), e.Available...)
+		sort.Strings(available)
+		issue.Entity, issue.Field = e.Entity, e.Field
+		issue.Message = fmt.Sprintf("entity '%s' has no field '%s'", e.Entity, e.Field)
+		issue.Suggestion = "Available fields: " + strings.Join(available, ", ")
+	case *UnknownRelationError:
+		issue.Entity, issue.Field = e.Entity, e.Relation
+		issue.Message = fmt.Sprintf("entity '%s' has no relation '%s'", e.Entity, e.Relation)
+	case *UnknownEntityError:
+		issue.Entity = e.Entity
+		issue.Message = fmt.Sprintf("entity '%s' not found in schema", e.Entity)
+	case *NotFoundError:
+		issue.Entity = e.Entity
+		issue.Message = fmt.Sprintf("%s with id %v not found", e.Entity, e.ID)
+	case *ConflictError:
+		issue.Entity, issue.Suggestion = e.Entity, e.Suggestion
+		issue.Message = "concurrent modification detected"
+	case *SerializationError:
+		issue.Message, issue.Suggestion = e.Message, "Retry the transaction"
+	case *SafetyError:
+		issue.Message, issue.Suggestion = e.Message, e.Suggestion
+	case *AuthorizationError:
+		issue.Entity, issue.Message = e.Entity, e.Message
+	case *ScanError:
+		issue.Field = e.Field
+		issue.Message = fmt.Sprintf("cannot scan column '%s' (%s) into %s", e.Column, e.From, e.To)
+	case *ParseError:
+		issue.Message, issue.Suggestion = e.Message, e.Suggestion
+		issue.Line, issue.Column = e.Line, e.Column
+	case *SchemaError:
+		issue.Message, issue.Suggestion = e.Message, e.Suggestion
+	case *InternalError:
+		issue.Message = e.Message
+	default:
+		issue.Message, _, _ = strings.Cut(err.Error(), "\n")
+	}
+
+	return issue
+}
+
+// ============================================================
+// HELPER FUNCTIONS
+// ============================================================
+
+// IsMutationError checks if error is (or wraps) a mutation error
+func IsMutationError(err error) bool {
+	var me MutationError
+	return errors.As(err, &me)
+}
+
+// ErrorCode extracts the error code, looking through wrapping errors
+func ErrorCode(err error) string {
+	var ce Error
+	if errors.As(err, &ce) {
+		return ce.Code()
+	}
+	return CodeUnknown
+}
+
+// IsSafetyError checks if error is a safety violation
+func IsSafetyError(err error) bool {
+	return errors.Is(err, ErrSafety)
+}
+
+// IsConstraintError checks if error is (or collects) a constraint violation
+func IsConstraintError(err error) bool {
+	return errors.Is(err, ErrConstraint)
+}
+
+// IsSerializationError checks if error is a serialization failure or
+// deadlock, after which the transaction can be retried
+func IsSerializationError(err error) bool {
+	return errors.Is(err, ErrSerialization)
+}
@@ -0,0 +1,169 @@
+package chamerr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestSentinels(t *testing.T) {
+	cases := []struct {
+		err       error
+		sentinels []error
+	}{
+		{&TypeMismatchError{Field: "age"}, []error{ErrValidation}},
+		{&NotNullError{Field: "email"}, []error{ErrConstraint, ErrNotNull}},
+		{&UniqueViolationError{Field: "email"}, []error{ErrConstraint, ErrUnique}},
+		{&ConstraintError{Type: "foreign_key"}, []error{ErrConstraint, ErrForeignKey}},
+		{&ConstraintError{Type: "check"}, []error{ErrConstraint}},
+		{&UnknownEntityError{Entity: "Ghost"}, []error{ErrUnknownEntity}},
+		{&NotFoundError{Entity: "User"}, []error{ErrNotFound}},
+		{&SafetyError{Operation: "delete_all"}, []error{ErrSafety}},
+		{&SerializationError{SQLState: "40001"}, []error{ErrSerialization}},
+		{&ParseError{Line: 1}, []error{ErrParse, ErrSchema}},
+		{&SchemaError{}, []error{ErrSchema}},
+	}
+
+	for _, c := range cases {
+		wrapped := fmt.Errorf("insert into User failed: %w", c.err)
+		for _, s := range c.sentinels {
+			if !errors.Is(wrapped, s) {
+				t.Errorf("%T: expected errors.Is(%v)", c.err, s)
+			}
+		}
+		if errors.Is(wrapped, ErrInternal) {
+			t.Errorf("%T: unexpected match on ErrInternal", c.err)
+		}
+	}
+
+	// Collections match their items
+	var errs ValidationErrors
+	errs.Add(&TypeMismatchError{Field: "age"})
+	errs.Add(&NotNullError{Field: "email"})
+	if err := errs.Err(); !errors.Is(err, ErrNotNull) || !errors.Is(err, ErrValidation) || errors.Is(err, ErrSafety) {
+		t.Errorf("Unexpected sentinel matches for %v", err)
+	}
+
+	// Errors raised before reaching Postgres have nothing to unwrap
+	var pgErr *pgconn.PgError
+	for _, err := range []error{&NotNullError{Field: "email"}, &ConstraintError{Type: "check"}} {
+		if errors.Unwrap(err) != nil || errors.As(err, &pgErr) {
+			t.Errorf("%T: unexpected wrapped error", err)
+		}
+	}
+	wrapped := &NotNullError{Field: "email", Err: &pgconn.PgError{Code: "23502"}}
+	if !errors.As(wrapped, &pgErr) || pgErr.Code != "23502" {
+		t.Errorf("Expected the PgError to be reachable, got %v", pgErr)
+	}
+}
+
+func TestCodes(t *testing.T) {
+	cases := map[string]error{
+		"TYPE_MISMATCH":          &TypeMismatchError{},
+		"FOREIGN_KEY_CONSTRAINT": &ConstraintError{Type: "foreign_key"},
+		"PRIMARY_KEY_CONSTRAINT": &ConstraintError{Type: "primary_key"},
+		"UNIQUE_VIOLATION":       &UniqueViolationError{},
+		"PARSE_ERROR":            &ParseError{},
+		"SCAN_ERROR":             &ScanError{},
+		"UNKNOWN_ERROR":          errors.New("boom"),
+	}
+	for code, err := range cases {
+		if got := ErrorCode(fmt.Errorf("wrapped: %w", err)); got != code {
+			t.Errorf("%T: code = %s, want %s", err, got, code)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	err := &UnknownFieldError{Entity: "User", Field: "phone", Available: []string{"name", "email"}}
+
+	data, jerr := json.Marshal(err)
+	if jerr != nil {
+		t.Fatalf("Marshal failed: %v", jerr)
+	}
+	want := `{"code":"UNKNOWN_FIELD","entity":"User","field":"phone",` +
+		`"message":"entity 'User' has no field 'phone'","suggestion":"Available fields: email, name"}`
+	if string(data) != want {
+		t.Errorf("JSON =\n%s\nwant\n%s", data, want)
+	}
+
+	// Wrapped errors describe the typed error inside
+	issue := Describe(fmt.Errorf("query failed: %w", &NotFoundError{Entity: "User", ID: 7}))
+	if issue.Code != CodeNotFound || issue.Entity != "User" {
+		t.Errorf("Unexpected issue: %+v", issue)
+	}
+
+	issue = Describe(&ConstraintError{Type: "check", Entity: "User", Field: "age"})
+	if issue.Code != "CHECK_CONSTRAINT" || issue.Entity != "User" || issue.Field != "age" {
+		t.Errorf("Unexpected issue: %+v", issue)
+	}
+}
+
+func TestSlog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	logger.Error("insert failed", "err", &UniqueViolationError{Entity: "User", Field: "email", Suggestion: "use another"})
+
+	out := buf.String()
+	for _, want := range []string{"err.code=UNIQUE_VIOLATION", "err.entity=User", "err.field=email", `err.suggestion="use another"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in %q", want, out)
+		}
+	}
+}
+
+func TestFromCore(t *testing.T) {
+	// Tagged parse error (chameleon_parse_schema)
+	err := FromCore(`{"kind":"ParseError","data":{"message":"Unexpected token","line":3,"column":7,` +
+		`"snippet":null,"suggestion":"Check the field type","token":"\"strng\""}}`)
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("Expected ParseError, got %v", err)
+	}
+	if perr.Line != 3 || perr.Column != 7 || perr.Token != `"strng"` || perr.Suggestion != "Check the field type" {
+		t.Errorf("Unexpected ParseError: %+v", perr)
+	}
+	if !strings.Contains(perr.Error(), "--> schema.cham:3:7") {
+		t.Errorf("Expected location in message, got %q", perr.Error())
+	}
+
+	// Validation result with several type-check errors (chameleon_validate_schema)
+	err = FromCore(`{"valid":false,"errors":[` +
+		`{"kind":"ValidationError","message":"Unknown entity 'Ghost'"},` +
+		`{"kind":"ValidationError","message":"Entity 'User' has no primary key"}]}`)
+	var serrs *SchemaErrors
+	if !errors.As(err, &serrs) || len(serrs.Errors) != 2 || !errors.Is(err, ErrSchema) {
+		t.Fatalf("Expected 2 SchemaErrors, got %v", err)
+	}
+	var serr *SchemaError
+	if !errors.As(err, &serr) || serr.Message != "Unknown entity 'Ghost'" {
+		t.Errorf("Expected errors.As to reach the first SchemaError, got %v", serr)
+	}
+
+	// A single problem comes back on its own
+	err = FromCore(`{"valid":false,"errors":[{"kind":"ParseError","message":"Invalid token","line":1,"column":4}]}`)
+	if !errors.As(err, &perr) || perr.Column != 4 {
+		t.Errorf("Expected a single ParseError, got %v", err)
+	}
+
+	if err := FromCore(`{"kind":"InternalError","data":{"message":"boom"}}`); !errors.Is(err, ErrInternal) {
+		t.Errorf("Expected InternalError, got %v", err)
+	}
+	if err := FromCore("Invalid UTF-8"); !errors.As(err, &serr) || serr.Message != "Invalid UTF-8" {
+		t.Errorf("Expected plain message as SchemaError, got %v", err)
+	}
+}
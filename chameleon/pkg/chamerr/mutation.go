@@ -0,0 +1,456 @@
+package chamerr
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ============================================================
+// VALIDATION ERRORS (Before SQL generation)
+// ============================================================
+
+// ValidationError: Schema/type/constraint validation failure
+type ValidationError struct {
+	Field    string      // "email", "age"
+	Type     string      // "type_mismatch", "length_exceeded", "invalid_format"
+	Value    interface{} // actual value provided
+	Expected string      // "string(255)", "uuid", "int"
+	Message  string      // User-friendly message
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf(
+		"ValidationError: Field '%s' - %s\n"+
+			"  Expected: %s\n"+
+			"  Got: %v\n"+
+			"  Details: %s",
+		e.Field, e.Type, e.Expected, e.Value, e.Message,
+	)
+}
+
+func (e *ValidationError) Code() string                 { return CodeValidation }
+func (e *ValidationError) IsMutationError()             {}
+func (e *ValidationError) Is(target error) bool         { return isAny(target, ErrValidation) }
+func (e *ValidationError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *ValidationError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// ============================================================
+// TYPE ERRORS
+// ============================================================
+
+// TypeMismatchError: Value doesn't match field type
+type TypeMismatchError struct {
+	Field        string
+	ExpectedType string // "uuid", "int", "string"
+	ReceivedType string // "string", "bool"
+	Value        interface{}
+	Suggestion   string // "Use uuid.Parse() to convert"
+}
+
+func (e *TypeMismatchError) Error() string {
+	return fmt.Sprintf(
+		"TypeMismatchError: Field '%s'\n"+
+			"  Expected type: %s\n"+
+			"  Received type: %s (value: %v)\n"+
+			"  Suggestion: %s",
+		e.Field, e.ExpectedType, e.ReceivedType, e.Value, e.Suggestion,
+	)
+}
+
+func (e *TypeMismatchError) Code() string                 { return CodeTypeMismatch }
+func (e *TypeMismatchError) IsMutationError()             {}
+func (e *TypeMismatchError) Is(target error) bool         { return isAny(target, ErrValidation) }
+func (e *TypeMismatchError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *TypeMismatchError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// ============================================================
+// LENGTH/FORMAT ERRORS
+// ============================================================
+
+// LengthExceededError: String exceeds max length
+type LengthExceededError struct {
+	Field  string
+	MaxLen int
+	Actual int
+	Value  string
+}
+
+func (e *LengthExceededError) Error() string {
+	return fmt.Sprintf(
+		"LengthExceededError: Field '%s'\n"+
+			"  Max length: %d characters\n"+
+			"  Provided length: %d characters\n"+
+			"  Value: %q",
+		e.Field, e.MaxLen, e.Actual, e.Value,
+	)
+}
+
+func (e *LengthExceededError) Code() string                 { return CodeLengthExceeded }
+func (e *LengthExceededError) IsMutationError()             {}
+func (e *LengthExceededError) Is(target error) bool         { return isAny(target, ErrValidation) }
+func (e *LengthExceededError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *LengthExceededError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// FieldFormatError: Invalid format (e.g., uuid, pattern)
+type FieldFormatError struct {
+	Field      string
+	Format     string // "uuid", "email", "iso8601"
+	Value      string
+	Suggestion string
+}
+
+func (e *FieldFormatError) Error() string {
+	return fmt.Sprintf(
+		"FormatError: Field '%s'\n"+
+			"  Expected format: %s\n"+
+			"  Provided value: %q\n"+
+			"  Suggestion: %s",
+		e.Field, e.Format, e.Value, e.Suggestion,
+	)
+}
+
+func (e *FieldFormatError) Code() string                 { return CodeFormat }
+func (e *FieldFormatError) IsMutationError()             {}
+func (e *FieldFormatError) Is(target error) bool         { return isAny(target, ErrValidation) }
+func (e *FieldFormatError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *FieldFormatError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// ============================================================
+// CONSTRAINT ERRORS (Data integrity)
+// ============================================================
+
+// ConstraintError: Generic constraint violation
+type ConstraintError struct {
+	Type       string // "unique", "not_null", "check", "foreign_key"
+	Entity     string
+	Field      string
+	Relation   string // relation the foreign key belongs to ("user"), if any
+	Value      interface{}
+	Constraint string // database constraint name, when reported by Postgres
+	Suggestion string
+	Err        *pgconn.PgError // original error, when reported by Postgres
+}
+
+func (e *ConstraintError) Error() string {
+	field := e.Field
+	switch {
+	case e.Entity != "" && e.Field != "":
+		field = e.Entity + "." + e.Field
+	case e.Entity != "":
+		field = e.Entity
+	}
+	if e.Relation != "" {
+		field = fmt.Sprintf("%s (relation '%s')", field, e.Relation)
+	}
+	return fmt.Sprintf(
+		"ConstraintError: %s constraint violation\n"+
+			"  Field: %s\n"+
+			"  Value: %v\n"+
+			"  Suggestion: %s",
+		e.Type, field, e.Value, e.Suggestion,
+	)
+}
+
+func (e *ConstraintError) Code() string                 { return strings.ToUpper(e.Type) + "_CONSTRAINT" }
+func (e *ConstraintError) IsMutationError()             {}
+func (e *ConstraintError) Unwrap() error                { return pgError(e.Err) }
+func (e *ConstraintError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *ConstraintError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// Is matches ErrConstraint, and ErrUnique / ErrNotNull / ErrForeignKey by Type
+func (e *ConstraintError) Is(target error) bool {
+	switch e.Type {
+	case "unique":
+		return isAny(target, ErrConstraint, ErrUnique)
+	case "not_null":
+		return isAny(target, ErrConstraint, ErrNotNull)
+	case "foreign_key":
+		return isAny(target, ErrConstraint, ErrForeignKey)
+	}
+	return target == ErrConstraint
+}
+
+// UniqueConstraintError: Value already exists (UNIQUE constraint)
+type UniqueConstraintError struct {
+	Field          string
+	Value          interface{}
+	ConflictingRow map[string]interface{} // The existing row
+	Table          string
+	Suggestion     string
+}
+
+func (e *UniqueConstraintError) Error() string {
+	return fmt.Sprintf(
+		"UniqueConstraintError: Field '%s' must be unique\n"+
+			"  Value: %v\n"+
+			"  Conflict: %s(id=%v) already has this value\n"+
+			"  Suggestion: %s",
+		e.Field, e.Value,
+		e.Table, e.ConflictingRow["id"],
+		e.Suggestion,
+	)
+}
+
+func (e *UniqueConstraintError) Code() string                 { return CodeUniqueConstraint }
+func (e *UniqueConstraintError) IsMutationError()             {}
+func (e *UniqueConstraintError) Is(target error) bool         { return isAny(target, ErrConstraint, ErrUnique) }
+func (e *UniqueConstraintError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *UniqueConstraintError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// NotNullError: Required field is null
+type NotNullError struct {
+	Entity     string // set when reported by Postgres
+	Field      string
+	Suggestion string
+	Err        *pgconn.PgError // original error, when reported by Postgres
+}
+
+func (e *NotNullError) Error() string {
+	return fmt.Sprintf(
+		"NotNullError: Field '%s' cannot be null\n"+
+			"  This field is required\n"+
+			"  Suggestion: %s",
+		e.Field, e.Suggestion,
+	)
+}
+
+func (e *NotNullError) Code() string                 { return CodeNotNull }
+func (e *NotNullError) IsMutationError()             {}
+func (e *NotNullError) Is(target error) bool         { return isAny(target, ErrConstraint, ErrNotNull) }
+func (e *NotNullError) Unwrap() error                { return pgError(e.Err) }
+func (e *NotNullError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *NotNullError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// ============================================================
+// FOREIGN KEY ERRORS
+// ============================================================
+
+// ForeignKeyError: Referenced record doesn't exist
+type ForeignKeyError struct {
+	Field            string      // "authorId"
+	Value            interface{} // "uuid-999"
+	ReferencedTable  string      // "User"
+	ReferencedField  string      // "id"
+	ReferencedEntity string      // "User" (entity name)
+	Suggestion       string
+}
+
+func (e *ForeignKeyError) Error() string {
+	return fmt.Sprintf(
+		"ForeignKeyError: Invalid reference\n"+
+			"  Field: %s\n"+
+			"  Referenced: %s(%s=%v)\n"+
+			"  The referenced %s does not exist\n"+
+			"  Suggestion: %s",
+		e.Field,
+		e.ReferencedTable, e.ReferencedField, e.Value,
+		e.ReferencedEntity,
+		e.Suggestion,
+	)
+}
+
+func (e *ForeignKeyError) Code() string                 { return CodeForeignKey }
+func (e *ForeignKeyError) IsMutationError()             {}
+func (e *ForeignKeyError) Is(target error) bool         { return isAny(target, ErrConstraint, ErrForeignKey) }
+func (e *ForeignKeyError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *ForeignKeyError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// ForeignKeyConstraintError: Attempt to delete/update row with dependents
+type ForeignKeyConstraintError struct {
+	Entity         string      // "User"
+	ID             interface{} // "uuid-123"
+	DependentTable string      // "Post"
+	DependentCount int
+	Suggestion     string
+}
+
+func (e *ForeignKeyConstraintError) Error() string {
+	return fmt.Sprintf(
+		"ForeignKeyConstraintError: Cannot delete/update - dependents exist\n"+
+			"  Entity: %s(id=%v)\n"+
+			"  Dependent records: %d %s(s) reference this\n"+
+			"  Suggestion: %s",
+		e.Entity, e.ID,
+		e.DependentCount, e.DependentTable,
+		e.Suggestion,
+	)
+}
+
+func (e *ForeignKeyConstraintError) Code() string     { return CodeForeignKeyDependents }
+func (e *ForeignKeyConstraintError) IsMutationError() {}
+func (e *ForeignKeyConstraintError) Is(target error) bool {
+	return isAny(target, ErrConstraint, ErrForeignKey)
+}
+func (e *ForeignKeyConstraintError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *ForeignKeyConstraintError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// ============================================================
+// SCHEMA ERRORS
+// ============================================================
+
+// UnknownFieldError: Field doesn't exist in schema
+type UnknownFieldError struct {
+	Entity    string
+	Field     string
+	Available []string // Valid field names
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf(
+		"UnknownFieldError: Entity '%s' has no field '%s'\n"+
+			"  Available fields: %v",
+		e.Entity, e.Field, e.Available,
+	)
+}
+
+func (e *UnknownFieldError) Code() string                 { return CodeUnknownField }
+func (e *UnknownFieldError) IsMutationError()             {}
+func (e *UnknownFieldError) Is(target error) bool         { return isAny(target, ErrUnknownField) }
+func (e *UnknownFieldError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *UnknownFieldError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// UnknownRelationError: Relation doesn't exist in schema
+type UnknownRelationError struct {
+	Entity    string
+	Relation  string
+	Available []string // Valid relation names
+}
+
+func (e *UnknownRelationError) Error() string {
+	return fmt.Sprintf(
+		"UnknownRelationError: Entity '%s' has no relation '%s'\n"+
+			"  Available relations: %v",
+		e.Entity, e.Relation, e.Available,
+	)
+}
+
+func (e *UnknownRelationError) Code() string                 { return CodeUnknownRelation }
+func (e *UnknownRelationError) IsMutationError()             {}
+func (e *UnknownRelationError) Is(target error) bool         { return isAny(target, ErrUnknownRelation) }
+func (e *UnknownRelationError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *UnknownRelationError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// UnknownEntityError: Entity doesn't exist in schema
+type UnknownEntityError struct {
+	Entity    string
+	Available []string
+}
+
+func (e *UnknownEntityError) Error() string {
+	return fmt.Sprintf(
+		"UnknownEntityError: Entity '%s' not found in schema\n"+
+			"  Available entities: %v",
+		e.Entity, e.Available,
+	)
+}
+
+func (e *UnknownEntityError) Code() string                 { return CodeUnknownEntity }
+func (e *UnknownEntityError) IsMutationError()             {}
+func (e *UnknownEntityError) Is(target error) bool         { return isAny(target, ErrUnknownEntity) }
+func (e *UnknownEntityError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *UnknownEntityError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// ============================================================
+// EXECUTION ERRORS (After SQL generation)
+// ============================================================
+
+// NotFoundError: Record to update/delete doesn't exist
+type NotFoundError struct {
+	Entity string
+	ID     interface{}
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf(
+		"NotFoundError: %s with id %v not found\n"+
+			"  The record you're trying to update/delete doesn't exist",
+		e.Entity, e.ID,
+	)
+}
+
+func (e *NotFoundError) Code() string                 { return CodeNotFound }
+func (e *NotFoundError) IsMutationError()             {}
+func (e *NotFoundError) Is(target error) bool         { return isAny(target, ErrNotFound) }
+func (e *NotFoundError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *NotFoundError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// ConflictError: Concurrent modification (optimistic locking - v0.2)
+type ConflictError struct {
+	Entity          string
+	ID              interface{}
+	ExpectedVersion int
+	ActualVersion   int
+	Suggestion      string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf(
+		"ConflictError: Concurrent modification detected\n"+
+			"  Entity: %s(id=%v)\n"+
+			"  Expected version: %d\n"+
+			"  Actual version: %d\n"+
+			"  Suggestion: %s",
+		e.Entity, e.ID, e.ExpectedVersion, e.ActualVersion, e.Suggestion,
+	)
+}
+
+func (e *ConflictError) Code() string                 { return CodeConflict }
+func (e *ConflictError) IsMutationError()             {}
+func (e *ConflictError) Is(target error) bool         { return isAny(target, ErrConflict) }
+func (e *ConflictError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *ConflictError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// ============================================================
+// SAFETY/PERMISSION ERRORS
+// ============================================================
+
+// SafetyError: Safety guard prevented operation
+type SafetyError struct {
+	Operation  string // "delete_without_filter", "delete_all", "large_update"
+	Rows       int
+	Threshold  int
+	Message    string
+	Suggestion string
+}
+
+func (e *SafetyError) Error() string {
+	return fmt.Sprintf(
+		"SafetyError: Operation blocked by safety guard\n"+
+			"  Operation: %s\n"+
+			"  Would affect: %d rows (threshold: %d)\n"+
+			"  Message: %s\n"+
+			"  Suggestion: %s",
+		e.Operation, e.Rows, e.Threshold, e.Message, e.Suggestion,
+	)
+}
+
+func (e *SafetyError) Code() string                 { return CodeSafety }
+func (e *SafetyError) IsMutationError()             {}
+func (e *SafetyError) Is(target error) bool         { return isAny(target, ErrSafety) }
+func (e *SafetyError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *SafetyError) LogValue() slog.Value         { return Describe(e).LogValue() }
+
+// AuthorizationError: User not authorized (v0.2)
+type AuthorizationError struct {
+	Operation string
+	Entity    string
+	Message   string
+}
+
+func (e *AuthorizationError) Error() string {
+	return fmt.Sprintf(
+		"AuthorizationError: Not authorized\n"+
+			"  Operation: %s on %s\n"+
+			"  Message: %s",
+		e.Operation, e.Entity, e.Message,
+	)
+}
+
+func (e *AuthorizationError) Code() string                 { return CodeAuthorization }
+func (e *AuthorizationError) IsMutationError()             {}
+func (e *AuthorizationError) Is(target error) bool         { return isAny(target, ErrUnauthorized) }
+func (e *AuthorizationError) MarshalJSON() ([]byte, error) { return marshalError(e) }
+func (e *AuthorizationError) LogValue() slog.Value         { return Describe(e).LogValue() }
@@ -0,0 +1,293 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Aggregate mirrors Rust's Aggregate: func(field)
+// Field is nil only for Count of the root entity
+type Aggregate struct {
+	Func  string     `json:"func"` // "Count", "Sum", "Avg", "Min", "Max"
+	Field *FieldPath `json:"field"`
+}
+
+// --- Builder ---
+
+// Count counts the matching entities
+func (qb *QueryBuilder) Count() *QueryBuilder {
+	return qb.aggregate("Count", "")
+}
+
+// Sum adds SUM(field); field may be a relation path ("orders.total")
+func (qb *QueryBuilder) Sum(field string) *QueryBuilder {
+	return qb.aggregate("Sum", field)
+}
+
+// Avg adds AVG(field)
+func (qb *QueryBuilder) Avg(field string) *QueryBuilder {
+	return qb.aggregate("Avg", field)
+}
+
+// Min adds MIN(field)
+func (qb *QueryBuilder) Min(field string) *QueryBuilder {
+	return qb.aggregate("Min", field)
+}
+
+// Max adds MAX(field)
+func (qb *QueryBuilder) Max(field string) *QueryBuilder {
+	return qb.aggregate("Max", field)
+}
+
+// GroupBy groups aggregates by one or more fields ("status", "user.email")
+func (qb *QueryBuilder) GroupBy(fields ...string) *QueryBuilder {
+	for _, field := range fields {
+		qb.query.GroupBy = append(qb.query.GroupBy, parseFieldPath(field))
+	}
+	return qb
+}
+
+// Having filters groups by their aggregates
+// Conditions name an aggregate by its alias ("count", "sum_total",
+// "avg_orders_total") or a group field:
+//
+//	qb.GroupBy("status").Count().Having(engine.Cond("count", "gt", 10))
+func (qb *QueryBuilder) Having(expr FilterExpr) *QueryBuilder {
+	if expr.IsEmpty() {
+		return qb
+	}
+	qb.query.Having = append(qb.query.Having, expr)
+	return qb
+}
+
+func (qb *QueryBuilder) aggregate(fn string, field string) *QueryBuilder {
+	agg := Aggregate{Func: fn}
+	if field != "" {
+		path := parseFieldPath(field)
+		agg.Field = &path
+	}
+	qb.query.Aggregates = append(qb.query.Aggregates, agg)
+	return qb
+}
+
+// isAggregate mirrors Rust's Query::is_aggregate
+func (qb *QueryBuilder) isAggregate() bool {
+	return len(qb.query.Aggregates) > 0 || len(qb.query.GroupBy) > 0
+}
+
+// aggregateAlias names the result column of an aggregate
+// (mirrors Aggregate::alias in chameleon-core/src/query/ast.rs)
+// Count → "count", Sum("orders.total") → "sum_orders_total"
+func aggregateAlias(fn string, field string) string {
+	alias := strings.ToLower(fn)
+	if field != "" {
+		alias += "_" + strings.ReplaceAll(field, ".", "_")
+	}
+	return alias
+}
+
+// --- Execution ---
+
+// Aggregate runs the aggregates and returns one group per result row
+// (a single group when there is no GroupBy)
+func (qb *QueryBuilder) Aggregate(ctx context.Context) (*AggregateResult, error) {
+	if qb.engine.executor == nil {
+		return nil, fmt.Errorf("executor not initialized - call engine.Connect() first")
+	}
+	if !qb.isAggregate() {
+		return nil, fmt.Errorf("no aggregates - add Count, Sum, Avg, Min, Max or GroupBy")
+	}
+
+	generated, err := qb.compile(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return qb.engine.executor.Aggregate(ctx, qb, generated)
+}
+
+// Aggregate runs an aggregate query and splits each row into group keys
+// and aggregate values
+func (ex *Executor) Aggregate(ctx context.Context, qb *QueryBuilder, generated *GeneratedSQL) (*AggregateResult, error) {
+	if !ex.connector.IsConnected() {
+		return nil, fmt.Errorf("not connected to database")
+	}
+
+	rows, _, err := ex.runQuery(ctx, qb, ex.querier(qb), &QueryEvent{
+		Operation: OpAggregate,
+		Entity:    qb.query.Entity,
+		SQL:       generated.MainQuery,
+	}, generated.Args...)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate query failed: %w", err)
+	}
+
+	keys := make([]string, len(qb.query.GroupBy))
+	for i, field := range qb.query.GroupBy {
+		keys[i] = strings.Join(field.Segments, ".")
+	}
+
+	groups := make([]AggregateGroup, 0, len(rows))
+	for _, row := range rows {
+		group := AggregateGroup{Key: make(Row, len(keys)), values: row}
+		for _, key := range keys {
+			group.Key[key] = row[key]
+		}
+		groups = append(groups, group)
+	}
+
+	return &AggregateResult{Entity: qb.query.Entity, Groups: groups}, nil
+}
+
+// --- Results ---
+
+// AggregateResult holds the groups returned by an aggregate query
+type AggregateResult struct {
+	// Entity name the aggregates were computed over
+	Entity string
+	// One group per GroupBy combination (exactly one without GroupBy)
+	Groups []AggregateGroup
+}
+
+// First returns the first group, or an empty group if there is none
+// Useful for ungrouped aggregates: result.First().Count()
+func (ar *AggregateResult) First() AggregateGroup {
+	if len(ar.Groups) == 0 {
+		return AggregateGroup{}
+	}
+	return ar.Groups[0]
+}
+
+// AggregateGroup holds the group-by values and aggregates of one group
+type AggregateGroup struct {
+	// Group-by values keyed by field path ("status", "user.email")
+	Key Row
+	// Aggregate values keyed by alias
+	values Row
+}
+
+// Count returns the number of entities in the group
+func (g AggregateGroup) Count() int64 {
+	n, _ := toInt64(g.values[aggregateAlias("Count", "")])
+	return n
+}
+
+// Sum returns SUM(field), or 0 if there were no values
+func (g AggregateGroup) Sum(field string) float64 {
+	f, _ := toFloat64(g.values[aggregateAlias("Sum", field)])
+	return f
+}
+
+// Avg returns AVG(field), or 0 if there were no values
+func (g AggregateGroup) Avg(field string) float64 {
+	f, _ := toFloat64(g.values[aggregateAlias("Avg", field)])
+	return f
+}
+
+// SumDecimal returns SUM(field) exactly, as a decimal string like
+// "1234.50" ("0" if there were no values). Sum rounds to float64, which
+// loses cents on totals past 2^53.
+func (g AggregateGroup) SumDecimal(field string) (string, error) {
+	return g.decimal(aggregateAlias("Sum", field))
+}
+
+// AvgDecimal returns AVG(field) exactly, as a decimal string ("0" if
+// there were no values)
+func (g AggregateGroup) AvgDecimal(field string) (string, error) {
+	return g.decimal(aggregateAlias("Avg", field))
+}
+
+// decimal renders an aggregate without rounding, "0" for NULL
+func (g AggregateGroup) decimal(alias string) (string, error) {
+	value, ok := g.values[alias]
+	if !ok {
+		return "", fmt.Errorf("aggregate %s is not part of the query", alias)
+	}
+
+	switch n := value.(type) {
+	case nil:
+		return "0", nil
+	case pgtype.Numeric:
+		if !n.Valid {
+			return "0", nil
+		}
+		text, err := n.Value()
+		if err != nil {
+			return "", fmt.Errorf("aggregate %s: %w", alias, err)
+		}
+		return text.(string), nil
+	case float32, float64:
+		f, _ := toFloat64(n)
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	}
+	if i, ok := toInt64(value); ok {
+		return strconv.FormatInt(i, 10), nil
+	}
+	return "", fmt.Errorf("aggregate %s is %T, not a number", alias, value)
+}
+
+// Min returns MIN(field) as the field's Go type (numbers as int64 or
+// float64), or nil if there were no values
+func (g AggregateGroup) Min(field string) interface{} {
+	return normalizeAggregate(g.values[aggregateAlias("Min", field)])
+}
+
+// Max returns MAX(field) as the field's Go type (numbers as int64 or
+// float64), or nil if there were no values
+func (g AggregateGroup) Max(field string) interface{} {
+	return normalizeAggregate(g.values[aggregateAlias("Max", field)])
+}
+
+// normalizeAggregate converts numeric values to int64/float64
+func normalizeAggregate(v interface{}) interface{} {
+	switch n := v.(type) {
+	case pgtype.Numeric:
+		if !n.Valid {
+			return nil
+		}
+		f, _ := toFloat64(n)
+		return f
+	case float32, float64:
+		f, _ := toFloat64(v)
+		return f
+	case int16, int32, int64:
+		i, _ := toInt64(v)
+		return i
+	}
+	return v
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int32:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case pgtype.Numeric:
+		i, err := n.Int64Value()
+		return i.Int64, err == nil && i.Valid
+	}
+	return 0, false
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case pgtype.Numeric:
+		f, err := n.Float64Value()
+		return f.Float64, err == nil && f.Valid
+	}
+	if i, ok := toInt64(v); ok {
+		return float64(i), true
+	}
+	return 0, false
+}
@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestQueryBuilder_Count(t *testing.T) {
+	e := setupTestEngine(t)
+
+	result, err := e.Query("User").
+		Filter("age", "gte", 18).
+		Count().
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL failed: %v", err)
+	}
+
+	assertContains(t, result.MainQuery, "SELECT COUNT(*) AS count")
+	assertContains(t, result.MainQuery, "WHERE users.age >= 18")
+	if len(result.EagerQueries) != 0 {
+		t.Errorf("Expected no eager queries, got %d", len(result.EagerQueries))
+	}
+}
+
+func TestQueryBuilder_GroupByHaving(t *testing.T) {
+	e := setupTestEngine(t)
+
+	result, err := e.Query("User").
+		GroupBy("name").
+		Count().
+		Sum("orders.total").
+		Having(Cond(aggregateAlias("Sum", "orders.total"), "gt", 100)).
+		OrderBy("sum_orders_total", "desc").
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL failed: %v", err)
+	}
+
+	assertContains(t, result.MainQuery, "SELECT users.name, COUNT(*) AS count, SUM(orders.sum_orders_total) AS sum_orders_total")
+	assertContains(t, result.MainQuery, "LEFT JOIN (SELECT orders.user_id AS __parent_key, SUM(orders.total) AS sum_orders_total FROM orders GROUP BY orders.user_id) AS orders ON orders.__parent_key = users.id")
+	assertContains(t, result.MainQuery, "GROUP BY users.name")
+	assertContains(t, result.MainQuery, "HAVING SUM(orders.sum_orders_total) > 100")
+	assertContains(t, result.MainQuery, "ORDER BY sum_orders_total DESC")
+}
+
+func TestQueryBuilder_AggregateRootAndRelation(t *testing.T) {
+	e := setupTestEngine(t)
+
+	// Orders are rolled up per user, so each user's age counts once
+	result, err := e.Query("User").
+		Sum("age").
+		Sum("orders.total").
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL failed: %v", err)
+	}
+	assertContains(t, result.MainQuery, "SUM(users.age) AS sum_age, SUM(orders.sum_orders_total) AS sum_orders_total")
+
+	// Grouping by orders joins them row by row
+	_, err = e.Query("User").GroupBy("orders.status").Sum("age").ToSQL()
+	if err == nil || !strings.Contains(err.Error(), "sum_age cannot be combined with grouping by 'orders'") {
+		t.Errorf("Expected a fan-out error, got %v", err)
+	}
+}
+
+func TestQueryBuilder_AggregateWithInclude(t *testing.T) {
+	e := setupTestEngine(t)
+
+	_, err := e.Query("User").Include("orders").Count().ToSQL()
+	if err == nil {
+		t.Fatal("Expected error when combining includes with aggregates")
+	}
+}
+
+func TestAggregateAlias(t *testing.T) {
+	cases := map[string][2]string{
+		"count":            {"Count", ""},
+		"sum_total":        {"Sum", "total"},
+		"avg_orders_total": {"Avg", "orders.total"},
+	}
+	for want, in := range cases {
+		if got := aggregateAlias(in[0], in[1]); got != want {
+			t.Errorf("aggregateAlias(%q, %q) = %q, want %q", in[0], in[1], got, want)
+		}
+	}
+}
+
+func TestAggregateGroup_TypedValues(t *testing.T) {
+	group := AggregateGroup{
+		Key: Row{"status": "paid"},
+		values: Row{
+			"count":     int64(3),
+			"sum_total": pgtype.Numeric{Int: big.NewInt(42550), Exp: -2, Valid: true},
+			"avg_total": pgtype.Numeric{Valid: false}, // AVG over no rows
+			"min_age":   int32(18),
+			"max_name":  "zoe",
+		},
+	}
+
+	if group.Count() != 3 {
+		t.Errorf("Count() = %d, want 3", group.Count())
+	}
+	if group.Sum("total") != 425.50 {
+		t.Errorf("Sum() = %v, want 425.50", group.Sum("total"))
+	}
+	if group.Avg("total") != 0 {
+		t.Errorf("Avg() = %v, want 0 for NULL", group.Avg("total"))
+	}
+	if group.Min("age") != int64(18) {
+		t.Errorf("Min() = %#v, want int64(18)", group.Min("age"))
+	}
+	if group.Max("name") != "zoe" {
+		t.Errorf("Max() = %#v, want \"zoe\"", group.Max("name"))
+	}
+	if group.Max("missing") != nil {
+		t.Errorf("Max() of unknown aggregate = %#v, want nil", group.Max("missing"))
+	}
+}
+
+func TestAggregateGroup_DecimalValues(t *testing.T) {
+	// 9007199254740993.25: past 2^53, float64 can't hold it
+	total, _ := new(big.Int).SetString("900719925474099325", 10)
+	group := AggregateGroup{
+		values: Row{
+			"sum_total": pgtype.Numeric{Int: total, Exp: -2, Valid: true},
+			"avg_total": pgtype.Numeric{Valid: false},
+			"sum_age":   int64(61),
+		},
+	}
+
+	if sum, err := group.SumDecimal("total"); err != nil || sum != "9007199254740993.25" {
+		t.Errorf("SumDecimal() = %q, %v", sum, err)
+	}
+	if f := strconv.FormatFloat(group.Sum("total"), 'f', -1, 64); f == "9007199254740993.25" {
+		t.Errorf("expected Sum() to round past 2^53, got %s", f)
+	}
+	if avg, err := group.AvgDecimal("total"); err != nil || avg != "0" {
+		t.Errorf("AvgDecimal() = %q, %v, want \"0\" for NULL", avg, err)
+	}
+	if sum, err := group.SumDecimal("age"); err != nil || sum != "61" {
+		t.Errorf("SumDecimal() of an int = %q, %v", sum, err)
+	}
+	if _, err := group.SumDecimal("price"); err == nil {
+		t.Error("expected an error for an aggregate the query did not compute")
+	}
+}
+
+func TestAggregateResult_FirstEmpty(t *testing.T) {
+	result := &AggregateResult{}
+	if result.First().Count() != 0 {
+		t.Error("Expected zero count from empty result")
+	}
+}
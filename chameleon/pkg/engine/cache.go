@@ -0,0 +1,310 @@
+package engine
+
+import (
+	"container/list"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ============================================================
+// COMPILED QUERY CACHE
+// ============================================================
+//
+// Generating SQL serializes the whole schema and the query and crosses
+// into Rust. Queries that differ only in their filter values generate the
+// same SQL once the values are bound as parameters, so the SQL is cached
+// by the query's shape: the query with every value replaced by $n.
+
+// defaultQueryCacheSize is how many compiled queries an engine keeps
+const defaultQueryCacheSize = 512
+
+// CacheStats reports on the compiled query cache of an engine
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int // queries cached
+	Capacity  int // 0 = disabled
+}
+
+// HitRate returns the share of lookups served from the cache
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// queryCache is an LRU of generated SQL keyed by query shape.
+// A nil cache is disabled.
+type queryCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // front = most recently used
+	schema   *Schema    // the engine's current schema (see purge)
+
+	hits, misses, evictions uint64
+}
+
+type cacheEntry struct {
+	key    string
+	schema *Schema // the schema sql was generated for
+	sql    *GeneratedSQL
+}
+
+func newQueryCache(capacity int) *queryCache {
+	return &queryCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *queryCache) enabled() bool {
+	return c != nil && c.capacity > 0
+}
+
+// get returns the SQL cached for a shape of a query on schema
+func (c *queryCache) get(key string, schema *Schema) (*GeneratedSQL, bool) {
+	if !c.enabled() {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok || elem.Value.(*cacheEntry).schema != schema {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).sql, true
+}
+
+// put caches the SQL of a shape generated for schema, evicting the least
+// recently used. SQL for a schema replaced since the query started is
+// dropped.
+func (c *queryCache) put(key string, schema *Schema, sql *GeneratedSQL) {
+	if !c.enabled() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if schema != c.schema {
+		return
+	}
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.schema, entry.sql = schema, sql
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, schema: schema, sql: sql})
+	c.evict()
+}
+
+// evict drops entries beyond capacity; c.mu must be held
+func (c *queryCache) evict() {
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.evictions++
+	}
+}
+
+// resize changes the capacity, evicting as needed (0 empties and disables)
+func (c *queryCache) resize(capacity int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.capacity = max(capacity, 0)
+	c.evict()
+}
+
+// purge drops every entry: the engine now queries schema
+func (c *queryCache) purge(schema *Schema) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	c.schema = schema
+}
+
+func (c *queryCache) stats() CacheStats {
+	if c == nil {
+		return CacheStats{}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.order.Len(),
+		Capacity:  c.capacity,
+	}
+}
+
+// SetQueryCacheSize bounds the compiled query cache (512 queries by
+// default); 0 disables it
+func (e *Engine) SetQueryCacheSize(n int) {
+	if e.queryCache == nil {
+		cache := newQueryCache(max(n, 0))
+		cache.schema = e.Schema()
+		e.queryCache = cache
+		return
+	}
+	e.queryCache.resize(n)
+}
+
+// QueryCacheStats reports hits, misses and size of the compiled query cache
+func (e *Engine) QueryCacheStats() CacheStats {
+	return e.queryCache.stats()
+}
+
+// ─────────────────────────────────────────────────────────────
+// Compiling queries
+// ─────────────────────────────────────────────────────────────
+
+// compile generates the SQL that runs a query: filter values are bound as
+// parameters (GeneratedSQL.Args) and the SQL is cached by the query's
+// shape. Timings go to profile unless it is nil.
+func (qb *QueryBuilder) compile(profile *QueryProfile) (*GeneratedSQL, error) {
+	// The whole query uses one schema, even if another is loaded meanwhile
+	schema := qb.engine.Schema()
+	if schema == nil {
+		return nil, fmt.Errorf("no schema loaded")
+	}
+
+	start := time.Now()
+	shape, args := parameterize(qb.query)
+	enc := qb.engine.Encoding()
+	payload, release, err := encodeQuery(&shape, enc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize query: %w", err)
+	}
+	defer release()
+
+	// JSON and MessagePack payloads never collide: a JSON query starts
+	// with '{', a MessagePack one with a map header (0x8x)
+	cache := qb.engine.queryCache
+	if cached, ok := cache.get(string(payload), schema); ok {
+		if profile != nil {
+			profile.Marshal += time.Since(start)
+			profile.Cached = true
+		}
+		generated := *cached
+		generated.Args = args
+		return &generated, nil
+	}
+
+	if err := qb.validateProjection(schema); err != nil {
+		return nil, err
+	}
+	if profile != nil {
+		profile.Marshal += time.Since(start)
+	}
+
+	generated, err := qb.runGenerator(schema, payload, enc, profile)
+	if err != nil {
+		return nil, err
+	}
+	cache.put(string(payload), schema, generated)
+
+	bound := *generated
+	bound.Args = args
+	return &bound, nil
+}
+
+// parameterize returns a copy of the query whose filter values, limit and
+// offset are bound parameters, and the values in parameter order. NULLs
+// stay in the SQL since they change it (IS NULL); LIKE values carry their
+// wildcards and IN lists are bound as one array (= ANY($n)).
+func parameterize(query QueryJSON) (QueryJSON, []interface{}) {
+	var args []interface{}
+
+	var bind func(expr FilterExpr) FilterExpr
+	bind = func(expr FilterExpr) FilterExpr {
+		switch {
+		case expr.Condition != nil:
+			cond := *expr.Condition
+			if value, ok := cond.bindValue(); ok {
+				args = append(args, value)
+				cond.Value = FilterValue{"Param": len(args)}
+			}
+			return FilterExpr{Condition: &cond}
+		case expr.Binary != nil:
+			return FilterExpr{Binary: &BinaryExpr{
+				Left:  bind(expr.Binary.Left),
+				Op:    expr.Binary.Op,
+				Right: bind(expr.Binary.Right),
+			}}
+		case expr.Not != nil:
+			inner := bind(*expr.Not)
+			return FilterExpr{Not: &inner}
+		}
+		return expr
+	}
+
+	shape := query
+	shape.Filters = make([]FilterExpr, len(query.Filters))
+	for i, expr := range query.Filters {
+		shape.Filters[i] = bind(expr)
+	}
+	if query.Having != nil {
+		shape.Having = make([]FilterExpr, len(query.Having))
+		for i, expr := range query.Having {
+			shape.Having[i] = bind(expr)
+		}
+	}
+
+	// Every page of a query shares one statement
+	bindCount := func(c *RowCount) *RowCount {
+		if c == nil {
+			return nil
+		}
+		args = append(args, c.Count)
+		return &RowCount{Param: len(args)}
+	}
+	shape.Limit = bindCount(query.Limit)
+	shape.Offset = bindCount(query.Offset)
+
+	return shape, args
+}
+
+// bindValue returns the value to bind for a condition, false for NULL
+func (c *FilterCondition) bindValue() (interface{}, bool) {
+	value := c.raw
+	if value == nil {
+		// Built without Cond: take the serialized value
+		for kind, v := range c.Value {
+			if kind != "Null" {
+				value = v
+			}
+		}
+	}
+	if value == nil {
+		return nil, false
+	}
+
+	switch c.Op {
+	case "Like":
+		return fmt.Sprintf("%%%v%%", value), true
+	case "In":
+		if kind := reflect.TypeOf(value).Kind(); kind != reflect.Slice && kind != reflect.Array {
+			return []interface{}{value}, true
+		}
+	}
+	return value, true
+}
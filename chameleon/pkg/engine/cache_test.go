@@ -0,0 +1,272 @@
+package engine
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestQueryCache_LRU(t *testing.T) {
+	c := newQueryCache(2)
+
+	c.put("a", nil, &GeneratedSQL{MainQuery: "A"})
+	c.put("b", nil, &GeneratedSQL{MainQuery: "B"})
+	if _, ok := c.get("a", nil); !ok { // a is now the most recent
+		t.Fatal("expected a to be cached")
+	}
+	c.put("c", nil, &GeneratedSQL{MainQuery: "C"}) // evicts b
+
+	if _, ok := c.get("b", nil); ok {
+		t.Error("b should have been evicted")
+	}
+	if sql, ok := c.get("c", nil); !ok || sql.MainQuery != "C" {
+		t.Error("expected c to be cached")
+	}
+
+	stats := c.stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Evictions != 1 || stats.Size != 2 || stats.Capacity != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if rate := stats.HitRate(); rate < 0.66 || rate > 0.67 {
+		t.Errorf("HitRate() = %v", rate)
+	}
+
+	c.resize(1)
+	if stats := c.stats(); stats.Size != 1 || stats.Evictions != 2 {
+		t.Errorf("resize should evict down to capacity: %+v", stats)
+	}
+
+	c.purge(nil)
+	if stats := c.stats(); stats.Size != 0 {
+		t.Errorf("purge should empty the cache: %+v", stats)
+	}
+
+	// Disabled caches neither store nor count
+	c.resize(0)
+	c.put("a", nil, &GeneratedSQL{})
+	if _, ok := c.get("a", nil); ok || c.stats().Misses != 1 {
+		t.Errorf("disabled cache should not be used: %+v", c.stats())
+	}
+	var nilCache *queryCache
+	nilCache.put("a", nil, &GeneratedSQL{})
+	if _, ok := nilCache.get("a", nil); ok {
+		t.Error("nil cache should be disabled")
+	}
+}
+
+func TestQueryCache_SchemaReload(t *testing.T) {
+	c := newQueryCache(4)
+	old, current := &Schema{}, &Schema{}
+
+	c.purge(old)
+	c.put("a", old, &GeneratedSQL{MainQuery: "A"})
+	c.purge(current)
+
+	// A query that started on the old schema finishes after the reload
+	c.put("b", old, &GeneratedSQL{MainQuery: "B"})
+	if stats := c.stats(); stats.Size != 0 {
+		t.Errorf("SQL for a replaced schema should not be cached: %+v", stats)
+	}
+
+	c.put("b", current, &GeneratedSQL{MainQuery: "B"})
+	if _, ok := c.get("b", old); ok {
+		t.Error("SQL for the current schema should not serve the old one")
+	}
+	if sql, ok := c.get("b", current); !ok || sql.MainQuery != "B" {
+		t.Error("expected b to be cached for the current schema")
+	}
+}
+
+func TestCompile_BindsParameters(t *testing.T) {
+	e := setupTestEngine(t)
+
+	generated, err := e.Query("User").
+		Filter("email", "eq", "ana@mail.com").
+		Filter("name", "like", "an").
+		Where(Or(Cond("age", "in", []int{20, 30}), Not(Cond("age", "gt", 65)))).
+		Filter("age", "neq", nil).
+		compile(nil)
+	if err != nil {
+		t.Fatalf("compile failed: %v", err)
+	}
+
+	sql := generated.MainQuery
+	for _, want := range []string{"email = $1", "name LIKE $2", "age = ANY($3)", "age > $4", "age != NULL"} {
+		assertContains(t, sql, want)
+	}
+	if strings.Contains(sql, "ana@mail.com") {
+		t.Errorf("values should not be inlined: %s", sql)
+	}
+
+	want := []interface{}{"ana@mail.com", "%an%", []int{20, 30}, 65}
+	if !reflect.DeepEqual(generated.Args, want) {
+		t.Errorf("Args = %#v, want %#v", generated.Args, want)
+	}
+
+	// ToSQL still inlines values
+	literal, err := e.Query("User").Filter("email", "eq", "ana@mail.com").ToSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertContains(t, literal.MainQuery, "email = 'ana@mail.com'")
+	if len(literal.Args) != 0 {
+		t.Errorf("ToSQL should not bind args, got %v", literal.Args)
+	}
+}
+
+func TestCompile_CachesByShape(t *testing.T) {
+	e := setupTestEngine(t)
+
+	first, err := e.Query("User").Filter("email", "eq", "ana@mail.com").Include("orders").compile(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	profile := &QueryProfile{}
+	second, err := e.Query("User").Filter("email", "eq", "bob@mail.com").Include("orders").compile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.MainQuery != second.MainQuery || len(second.EagerQueries) != 1 {
+		t.Errorf("same shape should share SQL:\n%s\n%s", first.MainQuery, second.MainQuery)
+	}
+	if first.Args[0] != "ana@mail.com" || second.Args[0] != "bob@mail.com" {
+		t.Errorf("args should be per query: %v / %v", first.Args, second.Args)
+	}
+	if !profile.Cached || profile.Generate != 0 {
+		t.Errorf("second compile should be a cache hit: %+v", profile)
+	}
+
+	// A different shape misses
+	if _, err := e.Query("User").Filter("email", "neq", "ana@mail.com").compile(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := e.QueryCacheStats()
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Size != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCompile_PagesShareSQL(t *testing.T) {
+	e := setupTestEngine(t)
+
+	page := func(offset uint64) *GeneratedSQL {
+		t.Helper()
+		generated, err := e.Query("User").Filter("age", "gte", 18).OrderBy("email", "asc").
+			Limit(20).Offset(offset).compile(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return generated
+	}
+
+	first, second := page(0), page(20)
+	if first.MainQuery != second.MainQuery || !strings.Contains(first.MainQuery, "LIMIT $2\nOFFSET $3") {
+		t.Errorf("pages should share SQL:\n%s\n%s", first.MainQuery, second.MainQuery)
+	}
+	if !reflect.DeepEqual(second.Args, []interface{}{18, uint64(20), uint64(20)}) {
+		t.Errorf("unexpected args %v", second.Args)
+	}
+	if stats := e.QueryCacheStats(); stats.Size != 1 || stats.Hits != 1 {
+		t.Errorf("expected one cache entry for both pages: %+v", stats)
+	}
+
+	// ToSQL still shows the values
+	sql, err := e.Query("User").Limit(20).Offset(40).ToSQL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sql.MainQuery, "LIMIT 20\nOFFSET 40") {
+		t.Errorf("ToSQL should inline limit and offset:\n%s", sql.MainQuery)
+	}
+}
+
+func TestCompile_InvalidatedOnSchemaReload(t *testing.T) {
+	e := setupTestEngine(t)
+
+	if _, err := e.Query("User").Filter("name", "eq", "Ana").compile(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := e.LoadSchemaFromString(`
+		entity User {
+			id: uuid primary,
+			name: string,
+		}
+	`); err != nil {
+		t.Fatal(err)
+	}
+	if size := e.QueryCacheStats().Size; size != 0 {
+		t.Fatalf("schema reload should purge the cache, %d entries left", size)
+	}
+
+	generated, err := e.Query("User").Filter("name", "eq", "Ana").compile(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(generated.MainQuery, "email") {
+		t.Errorf("stale SQL from the old schema: %s", generated.MainQuery)
+	}
+
+	// Unknown fields are still rejected once the shape is cached
+	e.SetQueryCacheSize(0)
+	if _, err := e.Query("User").Select("email").compile(nil); err == nil {
+		t.Error("expected unknown field error")
+	}
+}
+
+func TestParameterize_LeavesQueryUntouched(t *testing.T) {
+	e := setupTestEngine(t)
+	qb := e.Query("User").Where(Not(Cond("age", "gt", 18)))
+
+	shape, args := parameterize(qb.query)
+	if len(args) != 1 || shape.Filters[0].Not.Condition.Value["Param"] != 1 {
+		t.Fatalf("unexpected shape %+v / args %v", shape.Filters[0].Not.Condition, args)
+	}
+	if qb.query.Filters[0].Not.Condition.Value["Int"] != 18 {
+		t.Errorf("original query was modified: %+v", qb.query.Filters[0].Not.Condition.Value)
+	}
+}
+
+func TestCompile_ConcurrentSchemaReload(t *testing.T) {
+	e := NewEngineWithoutSchema()
+	schemas := []string{
+		`entity User { id: uuid primary, name: string, }`,
+		`entity User { id: uuid primary, name: string, nickname: string, }`,
+	}
+	if _, err := e.LoadSchemaFromString(schemas[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if _, err := e.Query("User").Filter("name", "eq", "ada").compile(nil); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	for i := range 20 {
+		if _, err := e.LoadSchemaFromString(schemas[(i+1)%2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+
+	// The last reload loaded the schema without nickname
+	generated, err := e.Query("User").Filter("name", "eq", "ada").compile(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(generated.MainQuery, "nickname") {
+		t.Errorf("cached SQL outlived its schema: %s", generated.MainQuery)
+	}
+}
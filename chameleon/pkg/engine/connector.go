@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -84,7 +85,7 @@ func (c *Connector) Pool() *pgxpool.Pool {
 
 // IsConnected returns true if the pool is active
 func (c *Connector) IsConnected() bool {
-	return c.pool != nil
+	return c != nil && c.pool != nil
 }
 
 // Ping verifies the connection is alive
@@ -150,16 +151,45 @@ func ParseConnectionString(connStr string) (ConnectorConfig, error) {
 	return config, nil
 }
 
-// Query executes a SQL query and returns rows
-func (c *Connector) Query(ctx context.Context, sql string) ([]map[string]interface{}, error) {
+// Query executes a SQL query with bound arguments and returns rows
+func (c *Connector) Query(ctx context.Context, sql string, args ...interface{}) ([]map[string]interface{}, error) {
 	if !c.IsConnected() {
 		return nil, fmt.Errorf("not connected to database")
 	}
 
-	rows, err := c.pool.Query(ctx, sql)
+	rows, err := c.pool.Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
+
+	return collectMaps(rows)
+}
+
+// Exec executes a SQL statement with bound arguments
+// Returns the number of rows affected
+func (c *Connector) Exec(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	if !c.IsConnected() {
+		return 0, fmt.Errorf("not connected to database")
+	}
+
+	tag, err := c.pool.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// Begin starts a database transaction on a pooled connection
+func (c *Connector) Begin(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	if !c.IsConnected() {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	return c.pool.BeginTx(ctx, opts)
+}
+
+// collectMaps reads all rows into column name → value maps and closes rows
+func collectMaps(rows pgx.Rows) ([]map[string]interface{}, error) {
 	defer rows.Close()
 
 	var result []map[string]interface{}
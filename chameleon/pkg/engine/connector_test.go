@@ -48,33 +48,6 @@ func TestNewConnectorNotConnected(t *testing.T) {
 	}
 }
 
-func TestReplacePlaceholderStrings(t *testing.T) {
-	sql := "SELECT * FROM orders WHERE user_id IN ($PARENT_IDS)"
-	ids := []interface{}{"uuid-1", "uuid-2", "uuid-3"}
-
-	result, err := replacePlaceholder(sql, ids)
-	if err != nil {
-		t.Fatalf("Unexpected error: %v", err)
-	}
-
-	assertContains(t, result, "'uuid-1'")
-	assertContains(t, result, "'uuid-2'")
-	assertContains(t, result, "'uuid-3'")
-	assertContains(t, result, "WHERE user_id IN")
-}
-
-func TestReplacePlaceholderEmpty(t *testing.T) {
-	sql := "SELECT * FROM orders WHERE user_id IN ($PARENT_IDS)"
-	ids := []interface{}{}
-
-	result, err := replacePlaceholder(sql, ids)
-	if err != nil {
-		t.Fatalf("Unexpected error: %v", err)
-	}
-
-	assertContains(t, result, "WHERE user_id IN (NULL)")
-}
-
 func TestExtractIDs(t *testing.T) {
 	rows := []Row{
 		{"id": "uuid-1", "name": "User 1"},
@@ -105,6 +78,47 @@ func TestExtractIDsMissingField(t *testing.T) {
 	}
 }
 
+func TestExtractIDsKeepsTypes(t *testing.T) {
+	uuid := [16]byte{0x11, 0x11, 0x11, 0x11}
+	rows := []Row{
+		{"id": uuid},
+		{"id": int64(7)},
+		{"id": "sku-1"},
+	}
+
+	ids := extractIDs(rows, "id")
+
+	if len(ids) != 3 {
+		t.Fatalf("Expected 3 IDs, got %d", len(ids))
+	}
+	if _, ok := ids[0].([16]byte); !ok {
+		t.Errorf("Expected UUID to stay [16]byte, got %T", ids[0])
+	}
+	if _, ok := ids[1].(int64); !ok {
+		t.Errorf("Expected int key to stay int64, got %T", ids[1])
+	}
+	if ids[2] != "sku-1" {
+		t.Errorf("Expected text key sku-1, got %v", ids[2])
+	}
+}
+
+func TestExtractIDsDistinctNonNull(t *testing.T) {
+	rows := []Row{
+		{"user_id": int64(1)},
+		{"user_id": int64(1)},
+		{"user_id": nil},
+		{"user_id": int64(2)},
+		{"user_id": []byte("a")},
+		{"user_id": []byte("a")},
+	}
+
+	ids := extractIDs(rows, "user_id")
+
+	if len(ids) != 3 {
+		t.Fatalf("Expected 3 distinct IDs, got %d: %v", len(ids), ids)
+	}
+}
+
 func TestRowHelpers(t *testing.T) {
 	row := Row{
 		"name":  "Ana",
@@ -40,6 +40,11 @@ type DeleteResult struct {
 	Affected int
 }
 
+type LinkResult struct {
+	Connected    int // join rows inserted
+	Disconnected int // join rows deleted
+}
+
 // ============================================================
 // MUTATION BUILDER INTERFACES
 // ============================================================
@@ -77,14 +82,28 @@ type DeleteMutation interface {
 	Execute(ctx context.Context) (*DeleteResult, error)
 }
 
+// LinkMutation connects and disconnects ManyToMany links
+// by writing rows of the relation's join (`through`) entity
+type LinkMutation interface {
+	// Connect links the owner to the given target primary keys
+	Connect(targetIDs ...interface{}) LinkMutation
+
+	// Disconnect removes links to the given target primary keys
+	Disconnect(targetIDs ...interface{}) LinkMutation
+
+	// Execute validates and runs the mutation (disconnects first)
+	Execute(ctx context.Context) (*LinkResult, error)
+}
+
 // ============================================================
 // FACTORY
 // ============================================================
 
 // MutationFactory creates mutation builders
 //
-// Factory is initialized once per Engine with the schema.
-// Engine delegates all mutation creation to this factory.
+// Factory is created per Engine every time a schema is loaded
+// (see RegisterMutationFactory). Engine delegates all mutation
+// creation to this factory.
 //
 // This allows multiple implementations:
 //   - SQL mutations (v0.1)
@@ -102,6 +121,39 @@ type MutationFactory interface {
 	NewDelete(entity string) DeleteMutation
 }
 
+// LinkMutationFactory is implemented by factories that can write
+// ManyToMany links (see Engine.Link)
+type LinkMutationFactory interface {
+	// NewLink creates a builder for the links of one owner row
+	NewLink(entity string, relation string, id interface{}) LinkMutation
+}
+
+// TxMutationFactory is implemented by factories whose builders
+// can run inside a transaction (see Tx.Insert)
+type TxMutationFactory interface {
+	MutationFactory
+
+	// WithQuerier returns a factory whose builders run on q
+	WithQuerier(q Querier) MutationFactory
+}
+
+// ============================================================
+// SESSION
+// ============================================================
+
+// Querier runs SQL with bound arguments against a database session
+//
+// Implemented by *Connector (pooled connections) and by Tx.Querier()
+// (a single transaction), so mutations run the same way inside and
+// outside a transaction.
+type Querier interface {
+	// Query returns all rows as column name → value maps
+	Query(ctx context.Context, sql string, args ...interface{}) ([]map[string]interface{}, error)
+
+	// Exec returns the number of rows affected
+	Exec(ctx context.Context, sql string, args ...interface{}) (int64, error)
+}
+
 // ============================================================
 // AUXILIARY CONTRACTS (for future use)
 // ============================================================
@@ -1,9 +1,11 @@
 package engine
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -22,7 +24,12 @@ type DebugContext struct {
 	Level  DebugLevel
 	Writer io.Writer // Where to write (stdout, file, etc)
 
-	// Future expansion
+	// ExplainAnalyze runs EXPLAIN ANALYZE at DebugExplain, executing
+	// each statement a second time to report actual times and rows
+	ExplainAnalyze bool
+
+	// EnableTiming prints a one-line time breakdown of each query;
+	// EnableProfiling prints the full profile (implied by DebugTrace)
 	EnableTiming    bool
 	EnableProfiling bool
 	ColorOutput     bool
@@ -103,6 +110,133 @@ func (dc *DebugContext) LogQuery(sql string, duration time.Duration, rowCount in
 	fmt.Fprintf(dc.Writer, "└─────────────────────────────────────\n\n")
 }
 
+// LogProfile prints where the time of a query went: the full breakdown at
+// DebugTrace or with EnableProfiling, a one-line summary with EnableTiming
+func (dc *DebugContext) LogProfile(entity string, p *QueryProfile) {
+	if p == nil {
+		return
+	}
+
+	if dc.Level >= DebugTrace || dc.EnableProfiling {
+		fmt.Fprintf(dc.Writer, "\n")
+		fmt.Fprintf(dc.Writer, "┌─────────────────────────────────────\n")
+		fmt.Fprintf(dc.Writer, "│ Query Profile (%s)\n", entity)
+		fmt.Fprintf(dc.Writer, "├─────────────────────────────────────\n")
+		fmt.Fprintf(dc.Writer, "│ Marshal:   %v\n", p.Marshal)
+		if p.Cached {
+			fmt.Fprintf(dc.Writer, "│ Generate:  cached\n")
+		} else {
+			fmt.Fprintf(dc.Writer, "│ Generate:  %v (FFI)\n", p.Generate)
+		}
+		for _, stmt := range append([]StatementProfile{p.Main}, p.Eager...) {
+			name := "main"
+			if stmt.Path != "" {
+				name = stmt.Path
+			}
+			fmt.Fprintf(dc.Writer, "│ %-10s db %v, scan %v (%d rows)\n", name+":", stmt.Database, stmt.Scan, stmt.Rows)
+		}
+		fmt.Fprintf(dc.Writer, "│ Other:     %v\n", p.Other())
+		fmt.Fprintf(dc.Writer, "│ Total:     %v\n", p.Total)
+		fmt.Fprintf(dc.Writer, "└─────────────────────────────────────\n\n")
+		return
+	}
+
+	if dc.EnableTiming {
+		prefix := "[TIMING] "
+		if dc.ColorOutput {
+			prefix = "\033[32m[TIMING]\033[0m "
+		}
+		fmt.Fprintf(dc.Writer, "%s%s: %v (generate %v, db %v, scan %v)\n",
+			prefix, entity, p.Total, p.Marshal+p.Generate, p.Database(), p.Scan())
+	}
+}
+
+// BeforeQuery implements QueryHook: logs the SQL at DebugSQL
+func (dc *DebugContext) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	dc.LogSQL(event.SQL)
+	return ctx
+}
+
+// AfterQuery implements QueryHook: logs the trace at DebugTrace
+func (dc *DebugContext) AfterQuery(_ context.Context, event *QueryEvent) {
+	dc.LogQuery(event.SQL, event.Duration, event.Rows)
+}
+
+// LogExplain pretty-prints the plans of a query
+func (dc *DebugContext) LogExplain(result *ExplainResult) {
+	if dc.Level < DebugExplain || result == nil {
+		return
+	}
+
+	for _, plan := range result.Plans() {
+		title := "Query Plan"
+		if plan.Path != "" {
+			title += " (include " + plan.Path + ")"
+		}
+
+		fmt.Fprintf(dc.Writer, "\n")
+		fmt.Fprintf(dc.Writer, "┌─────────────────────────────────────\n")
+		fmt.Fprintf(dc.Writer, "│ %s\n", title)
+		fmt.Fprintf(dc.Writer, "├─────────────────────────────────────\n")
+		fmt.Fprintf(dc.Writer, "│ SQL:\n│   %s\n", plan.SQL)
+		fmt.Fprintf(dc.Writer, "│ Plan:\n")
+
+		plan.Root.Walk(func(n *PlanNode, depth int) {
+			indent := strings.Repeat("      ", depth)
+			arrow := ""
+			if depth > 0 {
+				arrow = "->  "
+			}
+			fmt.Fprintf(dc.Writer, "│   %s%s%s\n", indent, arrow, planNodeLine(n, result.Analyzed))
+
+			detail := "│   " + indent + strings.Repeat(" ", len(arrow)) + "  "
+			for _, cond := range []struct{ name, value string }{
+				{"Index Cond", n.IndexCond},
+				{"Hash Cond", n.HashCond},
+				{"Filter", n.Filter},
+			} {
+				if cond.value != "" {
+					fmt.Fprintf(dc.Writer, "%s%s: %s\n", detail, cond.name, cond.value)
+				}
+			}
+			if result.Analyzed && n.RowsRemovedByFilter > 0 {
+				fmt.Fprintf(dc.Writer, "%sRows Removed by Filter: %.0f\n", detail, n.RowsRemovedByFilter)
+			}
+			if result.Analyzed && n.SharedHitBlocks+n.SharedReadBlocks > 0 {
+				fmt.Fprintf(dc.Writer, "%sBuffers: shared hit=%d read=%d\n", detail, n.SharedHitBlocks, n.SharedReadBlocks)
+			}
+		})
+
+		if result.Analyzed {
+			fmt.Fprintf(dc.Writer, "│ Planning: %v\n", plan.PlanningTime)
+			fmt.Fprintf(dc.Writer, "│ Execution: %v\n", plan.ExecutionTime)
+		}
+		for _, w := range plan.Warnings {
+			if dc.ColorOutput {
+				fmt.Fprintf(dc.Writer, "│ \033[33m⚠ %s\033[0m\n", w)
+			} else {
+				fmt.Fprintf(dc.Writer, "│ ⚠ %s\n", w)
+			}
+		}
+		fmt.Fprintf(dc.Writer, "└─────────────────────────────────────\n\n")
+	}
+}
+
+// planNodeLine formats a plan node like psql:
+// "Seq Scan on users  (cost=0.00..35.50 rows=2550 width=36) (actual time=0.010..0.020 rows=3 loops=1)"
+func planNodeLine(n *PlanNode, analyzed bool) string {
+	line := fmt.Sprintf("%s  (cost=%.2f..%.2f rows=%.0f width=%d)",
+		n.Label(), n.Startup, n.Total, n.PlanRows, n.PlanWidth)
+	if analyzed {
+		if n.ActualLoops == 0 {
+			return line + " (never executed)"
+		}
+		line += fmt.Sprintf(" (actual time=%.3f..%.3f rows=%.0f loops=%.0f)",
+			n.ActualStartup, n.ActualTotal, n.ActualRows, n.ActualLoops)
+	}
+	return line
+}
+
 func colorPrefix(level DebugLevel) string {
 	switch level {
 	case DebugSQL:
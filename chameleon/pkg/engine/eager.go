@@ -0,0 +1,145 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// throughParentKey is the column alias carrying the parent key on
+// ManyToMany eager rows (mirrors THROUGH_PARENT_KEY in chameleon-core/src/sql/generator.rs)
+const throughParentKey = "__parent_key"
+
+// eagerStep describes how one include level joins onto its parent level
+type eagerStep struct {
+	Path       string // full include path, e.g. "orders.items"
+	ParentPath string // "" for the root entity
+	Relation   *Relation
+	Target     *Entity
+
+	// ParentKey is read from parent rows and bound as $1;
+	// ChildKey is the matching column on the loaded rows
+	ParentKey string
+	ChildKey  string
+}
+
+// toMany returns true if each parent carries a list of children
+func (s *eagerStep) toMany() bool {
+	return s.Relation.Kind == RelationHasMany || s.Relation.Kind == RelationManyToMany
+}
+
+// planEagerStep resolves the join keys for an eager query path
+// using the relation's Kind and ForeignKey from the schema
+func planEagerStep(schema *Schema, rootEntity string, path string) (*eagerStep, error) {
+	segments := strings.Split(path, ".")
+
+	entity := schema.GetEntity(rootEntity)
+	if entity == nil {
+		return nil, fmt.Errorf("unknown entity '%s'", rootEntity)
+	}
+
+	// Walk to the parent of the last segment
+	for _, seg := range segments[:len(segments)-1] {
+		rel, ok := entity.Relations[seg]
+		if !ok {
+			return nil, fmt.Errorf("unknown relation '%s' in entity '%s'", seg, entity.Name)
+		}
+		if entity = schema.GetEntity(rel.TargetEntity); entity == nil {
+			return nil, fmt.Errorf("unknown entity '%s'", rel.TargetEntity)
+		}
+	}
+
+	relName := segments[len(segments)-1]
+	rel, ok := entity.Relations[relName]
+	if !ok {
+		return nil, fmt.Errorf("unknown relation '%s' in entity '%s'", relName, entity.Name)
+	}
+
+	target := schema.GetEntity(rel.TargetEntity)
+	if target == nil {
+		return nil, fmt.Errorf("unknown entity '%s'", rel.TargetEntity)
+	}
+
+	step := &eagerStep{
+		Path:       path,
+		ParentPath: strings.Join(segments[:len(segments)-1], "."),
+		Relation:   rel,
+		Target:     target,
+	}
+
+	// ManyToMany rows are loaded through the join entity and carry
+	// the parent key they were loaded for
+	if rel.Kind == RelationManyToMany {
+		if _, _, ok := schema.ThroughKeys(entity, rel); !ok {
+			return nil, fmt.Errorf("invalid 'through' entity for relation '%s' in '%s'", relName, entity.Name)
+		}
+		step.ParentKey = entity.PrimaryKey()
+		step.ChildKey = throughParentKey
+		return step, nil
+	}
+
+	fk, ok := schema.ForeignKey(entity, rel)
+	if !ok {
+		return nil, fmt.Errorf("missing foreign key for relation '%s' in '%s'", relName, entity.Name)
+	}
+
+	switch rel.Kind {
+	case RelationBelongsTo:
+		// FK lives on the parent: parent.user_id → users.id
+		step.ParentKey = fk
+		step.ChildKey = target.PrimaryKey()
+	default:
+		// FK lives on the child: users.id → orders.user_id
+		step.ParentKey = entity.PrimaryKey()
+		step.ChildKey = fk
+	}
+
+	return step, nil
+}
+
+// stitch attaches children to their parent rows under the relation name
+//
+// To-many relations always get a (possibly empty) []Row; to-one relations
+// get a Row, or nil when no match was found.
+func stitch(parents []Row, children []Row, step *eagerStep) {
+	name := step.Relation.Name
+
+	byKey := make(map[interface{}][]Row, len(children))
+	for _, child := range children {
+		if key := comparableKey(child[step.ChildKey]); key != nil {
+			byKey[key] = append(byKey[key], child)
+		}
+	}
+
+	// The join key alias is not part of the target entity
+	if step.ChildKey == throughParentKey {
+		for _, child := range children {
+			delete(child, throughParentKey)
+		}
+	}
+
+	for _, parent := range parents {
+		matches := byKey[comparableKey(parent[step.ParentKey])]
+
+		if step.toMany() {
+			if matches == nil {
+				matches = []Row{}
+			}
+			parent[name] = matches
+			continue
+		}
+
+		if len(matches) > 0 {
+			parent[name] = matches[0]
+		} else {
+			parent[name] = nil
+		}
+	}
+}
+
+// comparableKey normalizes a key value so it can be used as a map key
+func comparableKey(v interface{}) interface{} {
+	if b, ok := v.([]byte); ok {
+		return string(b) // slices are not comparable
+	}
+	return v
+}
@@ -0,0 +1,179 @@
+package engine
+
+import "testing"
+
+func TestPlanEagerStep_HasMany(t *testing.T) {
+	e := setupTestEngine(t)
+
+	step, err := planEagerStep(e.schema, "User", "orders")
+	if err != nil {
+		t.Fatalf("planEagerStep failed: %v", err)
+	}
+
+	if step.ParentPath != "" {
+		t.Errorf("Expected root parent, got %q", step.ParentPath)
+	}
+	if step.ParentKey != "id" || step.ChildKey != "user_id" {
+		t.Errorf("Expected users.id → orders.user_id, got %s → %s", step.ParentKey, step.ChildKey)
+	}
+	if !step.toMany() {
+		t.Error("HasMany should be to-many")
+	}
+}
+
+func TestPlanEagerStep_Nested(t *testing.T) {
+	e := setupTestEngine(t)
+
+	step, err := planEagerStep(e.schema, "User", "orders.items")
+	if err != nil {
+		t.Fatalf("planEagerStep failed: %v", err)
+	}
+
+	if step.ParentPath != "orders" {
+		t.Errorf("Expected parent path 'orders', got %q", step.ParentPath)
+	}
+	if step.ParentKey != "id" || step.ChildKey != "order_id" {
+		t.Errorf("Expected orders.id → order_items.order_id, got %s → %s", step.ParentKey, step.ChildKey)
+	}
+}
+
+func TestPlanEagerStep_BelongsTo(t *testing.T) {
+	e := setupTestEngine(t)
+
+	step, err := planEagerStep(e.schema, "OrderItem", "order.user")
+	if err != nil {
+		t.Fatalf("planEagerStep failed: %v", err)
+	}
+
+	// FK inferred from User.orders: [Order] via user_id
+	if step.ParentKey != "user_id" || step.ChildKey != "id" {
+		t.Errorf("Expected orders.user_id → users.id, got %s → %s", step.ParentKey, step.ChildKey)
+	}
+	if step.toMany() {
+		t.Error("BelongsTo should be to-one")
+	}
+}
+
+func TestPlanEagerStep_UnknownRelation(t *testing.T) {
+	e := setupTestEngine(t)
+
+	if _, err := planEagerStep(e.schema, "User", "orders.payments"); err == nil {
+		t.Fatal("Expected error for unknown relation")
+	}
+}
+
+func TestStitch_ToMany(t *testing.T) {
+	e := setupTestEngine(t)
+	step, _ := planEagerStep(e.schema, "User", "orders")
+
+	users := []Row{
+		{"id": "u1"},
+		{"id": "u2"},
+	}
+	orders := []Row{
+		{"id": "o1", "user_id": "u1"},
+		{"id": "o2", "user_id": "u1"},
+	}
+
+	stitch(users, orders, step)
+
+	if got := len(users[0].Many("orders")); got != 2 {
+		t.Errorf("Expected 2 orders for u1, got %d", got)
+	}
+	if users[1].Many("orders") == nil || len(users[1].Many("orders")) != 0 {
+		t.Errorf("Expected empty orders for u2, got %v", users[1]["orders"])
+	}
+}
+
+func TestStitch_ToOne(t *testing.T) {
+	e := setupTestEngine(t)
+	step, _ := planEagerStep(e.schema, "Order", "user")
+
+	orders := []Row{
+		{"id": "o1", "user_id": [16]byte{1}},
+		{"id": "o2", "user_id": [16]byte{9}},
+	}
+	users := []Row{
+		{"id": [16]byte{1}, "email": "ana@mail.com"},
+	}
+
+	stitch(orders, users, step)
+
+	if orders[0].One("user").String("email") != "ana@mail.com" {
+		t.Errorf("Expected ana as o1 user, got %v", orders[0]["user"])
+	}
+	if orders[1].One("user") != nil {
+		t.Errorf("Expected no user for o2, got %v", orders[1]["user"])
+	}
+}
+
+func setupManyToManyEngine(t *testing.T) *Engine {
+	t.Helper()
+
+	e := NewEngine()
+	_, err := e.LoadSchemaFromString(`
+		entity Post {
+			id: uuid primary,
+			title: string,
+			tags: [Tag] through PostTag,
+		}
+
+		entity Tag {
+			id: uuid primary,
+			name: string,
+		}
+
+		entity PostTag {
+			id: uuid primary,
+			post_id: uuid,
+			tag_id: uuid,
+			post: Post,
+			tag: Tag,
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	return e
+}
+
+func TestThroughKeys(t *testing.T) {
+	e := setupManyToManyEngine(t)
+	post := e.schema.GetEntity("Post")
+
+	source, target, ok := e.schema.ThroughKeys(post, post.Relations["tags"])
+	if !ok {
+		t.Fatal("Expected join keys to resolve")
+	}
+	if source != "post_id" || target != "tag_id" {
+		t.Errorf("Expected post_id/tag_id, got %s/%s", source, target)
+	}
+}
+
+func TestPlanEagerStep_ManyToMany(t *testing.T) {
+	e := setupManyToManyEngine(t)
+
+	step, err := planEagerStep(e.schema, "Post", "tags")
+	if err != nil {
+		t.Fatalf("planEagerStep failed: %v", err)
+	}
+	if step.ParentKey != "id" || step.ChildKey != throughParentKey {
+		t.Errorf("Expected posts.id → %s, got %s → %s", throughParentKey, step.ParentKey, step.ChildKey)
+	}
+
+	posts := []Row{{"id": "p1"}, {"id": "p2"}}
+	tags := []Row{
+		{"id": "t1", "name": "go", throughParentKey: "p1"},
+		{"id": "t2", "name": "db", throughParentKey: "p1"},
+		{"id": "t2", "name": "db", throughParentKey: "p2"},
+	}
+
+	stitch(posts, tags, step)
+
+	if len(posts[0].Many("tags")) != 2 || len(posts[1].Many("tags")) != 1 {
+		t.Errorf("Unexpected tag distribution: %v / %v", posts[0]["tags"], posts[1]["tags"])
+	}
+	if _, ok := posts[1].Many("tags")[0][throughParentKey]; ok {
+		t.Error("Join key alias should be removed from child rows")
+	}
+}
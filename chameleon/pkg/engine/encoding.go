@@ -0,0 +1,334 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/chameleon-db/chameleondb/chameleon/internal/ffi"
+	"github.com/chameleon-db/chameleondb/chameleon/internal/msgpack"
+)
+
+// ============================================================
+// FFI ENCODING
+// ============================================================
+
+// Encoding is the format queries are sent to the Rust core in
+type Encoding int
+
+const (
+	// EncodingJSON is readable in traces and debuggers (default)
+	EncodingJSON Encoding = iota
+	// EncodingMsgPack is MessagePack: smaller payloads, fewer allocations
+	EncodingMsgPack
+)
+
+func (enc Encoding) String() string {
+	switch enc {
+	case EncodingJSON:
+		return "json"
+	case EncodingMsgPack:
+		return "msgpack"
+	}
+	return fmt.Sprintf("Encoding(%d)", int(enc))
+}
+
+func (enc Encoding) ffi() ffi.Encoding {
+	if enc == EncodingMsgPack {
+		return ffi.EncodingMsgPack
+	}
+	return ffi.EncodingJSON
+}
+
+// SetEncoding selects the format of queries sent to the Rust core.
+// Generated SQL is the same whatever the encoding.
+func (e *Engine) SetEncoding(enc Encoding) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.encoding = enc
+}
+
+// Encoding returns the format of queries sent to the Rust core
+func (e *Engine) Encoding() Encoding {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.encoding
+}
+
+// ─────────────────────────────────────────────────────────────
+// Queries
+// ─────────────────────────────────────────────────────────────
+
+// payloadPool holds the buffers MessagePack queries are encoded into
+var payloadPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
+
+// encodeQuery serializes a query for the Rust core. The payload is only
+// valid until release is called.
+func encodeQuery(query *QueryJSON, enc Encoding) (payload []byte, release func(), err error) {
+	if enc != EncodingMsgPack {
+		payload, err = json.Marshal(query)
+		return payload, func() {}, err
+	}
+
+	buf := payloadPool.Get().(*[]byte)
+	payload, err = appendQuery((*buf)[:0], query)
+	if err != nil {
+		payloadPool.Put(buf)
+		return nil, nil, err
+	}
+	return payload, func() {
+		*buf = payload
+		payloadPool.Put(buf)
+	}, nil
+}
+
+// appendQuery appends the MessagePack form of a query. It mirrors the
+// JSON tags of QueryJSON, omitempty included.
+func appendQuery(b []byte, q *QueryJSON) ([]byte, error) {
+	fields := 6
+	if len(q.Select) > 0 {
+		fields++
+	}
+	if len(q.Aggregates) > 0 {
+		fields++
+	}
+	if len(q.GroupBy) > 0 {
+		fields++
+	}
+	if len(q.Having) > 0 {
+		fields++
+	}
+	b = msgpack.AppendMapHeader(b, fields)
+
+	var err error
+	b = msgpack.AppendString(b, "entity")
+	b = msgpack.AppendString(b, q.Entity)
+	if len(q.Select) > 0 {
+		b = msgpack.AppendString(b, "select")
+		b = msgpack.AppendStrings(b, q.Select)
+	}
+	b = msgpack.AppendString(b, "filters")
+	if b, err = appendFilters(b, q.Filters); err != nil {
+		return b, err
+	}
+
+	b = msgpack.AppendString(b, "includes")
+	if q.Includes == nil {
+		b = msgpack.AppendNil(b)
+	} else {
+		b = msgpack.AppendArrayHeader(b, len(q.Includes))
+		for _, inc := range q.Includes {
+			if len(inc.Fields) > 0 {
+				b = msgpack.AppendMapHeader(b, 2)
+			} else {
+				b = msgpack.AppendMapHeader(b, 1)
+			}
+			b = msgpack.AppendString(b, "path")
+			b = msgpack.AppendStrings(b, inc.Path)
+			if len(inc.Fields) > 0 {
+				b = msgpack.AppendString(b, "fields")
+				b = msgpack.AppendStrings(b, inc.Fields)
+			}
+		}
+	}
+
+	b = msgpack.AppendString(b, "order_by")
+	if q.OrderBy == nil {
+		b = msgpack.AppendNil(b)
+	} else {
+		b = msgpack.AppendArrayHeader(b, len(q.OrderBy))
+		for _, order := range q.OrderBy {
+			b = msgpack.AppendMapHeader(b, 2)
+			b = msgpack.AppendString(b, "field")
+			b = msgpack.AppendString(b, order.Field)
+			b = msgpack.AppendString(b, "direction")
+			b = msgpack.AppendString(b, order.Direction)
+		}
+	}
+
+	b = msgpack.AppendString(b, "limit")
+	b = appendRowCount(b, q.Limit)
+	b = msgpack.AppendString(b, "offset")
+	b = appendRowCount(b, q.Offset)
+
+	if len(q.Aggregates) > 0 {
+		b = msgpack.AppendString(b, "aggregates")
+		b = msgpack.AppendArrayHeader(b, len(q.Aggregates))
+		for _, agg := range q.Aggregates {
+			b = msgpack.AppendMapHeader(b, 2)
+			b = msgpack.AppendString(b, "func")
+			b = msgpack.AppendString(b, agg.Func)
+			b = msgpack.AppendString(b, "field")
+			if agg.Field == nil {
+				b = msgpack.AppendNil(b)
+			} else {
+				b = appendFieldPath(b, *agg.Field)
+			}
+		}
+	}
+	if len(q.GroupBy) > 0 {
+		b = msgpack.AppendString(b, "group_by")
+		b = msgpack.AppendArrayHeader(b, len(q.GroupBy))
+		for _, field := range q.GroupBy {
+			b = appendFieldPath(b, field)
+		}
+	}
+	if len(q.Having) > 0 {
+		b = msgpack.AppendString(b, "having")
+		if b, err = appendFilters(b, q.Having); err != nil {
+			return b, err
+		}
+	}
+
+	return b, nil
+}
+
+func appendFilters(b []byte, filters []FilterExpr) ([]byte, error) {
+	if filters == nil {
+		return msgpack.AppendNil(b), nil
+	}
+	b = msgpack.AppendArrayHeader(b, len(filters))
+	for _, expr := range filters {
+		var err error
+		if b, err = appendFilter(b, expr); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+// appendFilter appends a filter expression: a map holding whichever of
+// Condition, Binary and Not is set
+func appendFilter(b []byte, expr FilterExpr) ([]byte, error) {
+	n := 0
+	for _, set := range []bool{expr.Condition != nil, expr.Binary != nil, expr.Not != nil} {
+		if set {
+			n++
+		}
+	}
+	b = msgpack.AppendMapHeader(b, n)
+
+	var err error
+	if cond := expr.Condition; cond != nil {
+		b = msgpack.AppendString(b, "Condition")
+		b = msgpack.AppendMapHeader(b, 3)
+		b = msgpack.AppendString(b, "field")
+		b = appendFieldPath(b, cond.Field)
+		b = msgpack.AppendString(b, "op")
+		b = msgpack.AppendString(b, cond.Op)
+		b = msgpack.AppendString(b, "value")
+		if b, err = msgpack.AppendValue(b, map[string]interface{}(cond.Value)); err != nil {
+			return b, fmt.Errorf("filter on %v: %w", cond.Field.Segments, err)
+		}
+	}
+	if bin := expr.Binary; bin != nil {
+		b = msgpack.AppendString(b, "Binary")
+		b = msgpack.AppendMapHeader(b, 3)
+		b = msgpack.AppendString(b, "left")
+		if b, err = appendFilter(b, bin.Left); err != nil {
+			return b, err
+		}
+		b = msgpack.AppendString(b, "op")
+		b = msgpack.AppendString(b, bin.Op)
+		b = msgpack.AppendString(b, "right")
+		if b, err = appendFilter(b, bin.Right); err != nil {
+			return b, err
+		}
+	}
+	if expr.Not != nil {
+		b = msgpack.AppendString(b, "Not")
+		if b, err = appendFilter(b, *expr.Not); err != nil {
+			return b, err
+		}
+	}
+	return b, nil
+}
+
+func appendFieldPath(b []byte, path FieldPath) []byte {
+	b = msgpack.AppendMapHeader(b, 1)
+	b = msgpack.AppendString(b, "segments")
+	return msgpack.AppendStrings(b, path.Segments)
+}
+
+func appendRowCount(b []byte, c *RowCount) []byte {
+	switch {
+	case c == nil:
+		return msgpack.AppendNil(b)
+	case c.Param > 0:
+		b = msgpack.AppendMapHeader(b, 1)
+		b = msgpack.AppendString(b, "Param")
+		return msgpack.AppendUint(b, uint64(c.Param))
+	}
+	return msgpack.AppendUint(b, c.Count)
+}
+
+// ─────────────────────────────────────────────────────────────
+// Generated SQL
+// ─────────────────────────────────────────────────────────────
+
+// decodeGeneratedSQL decodes a GeneratedSQL the core answered in
+// MessagePack, like json.Unmarshal does a JSON one
+func decodeGeneratedSQL(data []byte, result *GeneratedSQL) error {
+	r := msgpack.NewReader(data)
+	n, err := r.ReadMapHeader()
+	if err != nil {
+		return err
+	}
+
+	for range n {
+		key, err := r.ReadString()
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "main_query":
+			if result.MainQuery, err = r.ReadString(); err != nil {
+				return err
+			}
+		case "eager_queries":
+			if result.EagerQueries, err = readEagerQueries(r); err != nil {
+				return err
+			}
+		default:
+			if err := r.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+	if !r.Done() {
+		return fmt.Errorf("msgpack: trailing bytes")
+	}
+	return nil
+}
+
+// readEagerQueries reads the (relation, SQL) pairs of eager_queries
+func readEagerQueries(r *msgpack.Reader) ([][]string, error) {
+	if r.TryNil() {
+		return nil, nil
+	}
+	n, err := r.ReadArrayHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	queries := make([][]string, 0, n)
+	for range n {
+		size, err := r.ReadArrayHeader()
+		if err != nil {
+			return nil, err
+		}
+		pair := make([]string, size)
+		for i := range pair {
+			if pair[i], err = r.ReadString(); err != nil {
+				return nil, err
+			}
+		}
+		queries = append(queries, pair)
+	}
+	return queries, nil
+}
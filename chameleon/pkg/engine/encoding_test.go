@@ -0,0 +1,226 @@
+package engine
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/chameleon-db/chameleondb/chameleon/internal/msgpack"
+)
+
+// encodingTestQueries covers every field of QueryJSON
+func encodingTestQueries(e *Engine) map[string]*QueryBuilder {
+	return map[string]*QueryBuilder{
+		"simple": e.Query("User"),
+		"filters": e.Query("User").
+			Filter("email", "eq", "ana@mail.com").
+			Filter("age", "gte", 18).
+			Filter("name", "neq", nil).
+			Where(Or(Cond("age", "in", []int{20, 30}), Not(Cond("name", "like", "an")))),
+		"projection": e.Query("User").
+			Select("id", "email").
+			Include("orders", "id", "total").
+			Include("orders.items").
+			OrderBy("email", "asc").
+			Limit(10).
+			Offset(20),
+		"aggregate": e.Query("User").
+			Count().
+			Sum("orders.total").
+			GroupBy("name").
+			Having(Cond(aggregateAlias("Sum", "orders.total"), "gt", 100.5)),
+	}
+}
+
+// canonicalJSON re-encodes a JSON or MessagePack payload as JSON with
+// sorted keys
+func canonicalJSON(t *testing.T, payload []byte, enc Encoding) string {
+	t.Helper()
+
+	var value interface{}
+	var err error
+	if enc == EncodingMsgPack {
+		value, err = msgpack.Decode(payload)
+	} else {
+		err = json.Unmarshal(payload, &value)
+	}
+	if err != nil {
+		t.Fatalf("decoding %s payload: %v", enc, err)
+	}
+
+	out, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestEncodeQuery_MatchesJSON(t *testing.T) {
+	e := setupTestEngine(t)
+
+	for name, qb := range encodingTestQueries(e) {
+		t.Run(name, func(t *testing.T) {
+			jsonPayload, release, err := encodeQuery(&qb.query, EncodingJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer release()
+
+			packed, release, err := encodeQuery(&qb.query, EncodingMsgPack)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer release()
+
+			want := canonicalJSON(t, jsonPayload, EncodingJSON)
+			if got := canonicalJSON(t, packed, EncodingMsgPack); got != want {
+				t.Errorf("MessagePack payload differs from JSON:\n got: %s\nwant: %s", got, want)
+			}
+			if len(packed) >= len(jsonPayload) {
+				t.Errorf("MessagePack payload is %d bytes, JSON %d", len(packed), len(jsonPayload))
+			}
+		})
+	}
+}
+
+func TestEncoding_SameSQL(t *testing.T) {
+	e := setupTestEngine(t)
+	if e.Encoding() != EncodingJSON {
+		t.Fatalf("default encoding is %s", e.Encoding())
+	}
+
+	for name := range encodingTestQueries(e) {
+		t.Run(name, func(t *testing.T) {
+			e.SetEncoding(EncodingJSON)
+			want, err := encodingTestQueries(e)[name].ToSQL()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			e.SetEncoding(EncodingMsgPack)
+			got, err := encodingTestQueries(e)[name].ToSQL()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("ToSQL differs:\n msgpack: %+v\n    json: %+v", got, want)
+			}
+
+			// Compiled queries are cached per encoding
+			compiled, err := encodingTestQueries(e)[name].compile(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e.SetEncoding(EncodingJSON)
+			expected, err := encodingTestQueries(e)[name].compile(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(compiled, expected) {
+				t.Errorf("compile differs:\n msgpack: %+v\n    json: %+v", compiled, expected)
+			}
+		})
+	}
+
+	e.SetEncoding(EncodingMsgPack)
+	if _, err := e.Query("Ghost").ToSQL(); err == nil {
+		t.Error("expected an error for an unknown entity")
+	}
+}
+
+func TestDecodeGeneratedSQL(t *testing.T) {
+	b := msgpack.AppendMapHeader(nil, 3)
+	b = msgpack.AppendString(b, "main_query")
+	b = msgpack.AppendString(b, "SELECT 1")
+	b = msgpack.AppendString(b, "future_field")
+	b = msgpack.AppendInt(b, 1)
+	b = msgpack.AppendString(b, "eager_queries")
+	b = msgpack.AppendArrayHeader(b, 1)
+	b = msgpack.AppendStrings(b, []string{"orders", "SELECT 2"})
+
+	var got GeneratedSQL
+	if err := decodeGeneratedSQL(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := GeneratedSQL{MainQuery: "SELECT 1", EagerQueries: [][]string{{"orders", "SELECT 2"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if err := decodeGeneratedSQL(b[:len(b)-3], &got); err == nil {
+		t.Error("expected an error for a truncated answer")
+	}
+	if err := decodeGeneratedSQL([]byte{0x01}, &got); err == nil {
+		t.Error("expected an error for a non-map answer")
+	}
+}
+
+// ─────────────────────────────────────────────────────────────
+// Benchmarks
+// go test ./pkg/engine -run '^$' -bench Encoding -benchmem
+// ─────────────────────────────────────────────────────────────
+
+var benchmarkEncodings = []Encoding{EncodingJSON, EncodingMsgPack}
+
+func benchmarkQuery(e *Engine) *QueryBuilder {
+	return e.Query("User").
+		Select("id", "email", "name").
+		Filter("email", "like", "ana").
+		Where(Or(Cond("age", "gte", 18), Cond("name", "eq", nil))).
+		Include("orders", "id", "total").
+		Include("orders.items").
+		OrderBy("email", "asc").
+		Limit(50)
+}
+
+func BenchmarkEncoding_EncodeQuery(b *testing.B) {
+	e := setupTestEngine(b)
+	qb := benchmarkQuery(e)
+
+	for _, enc := range benchmarkEncodings {
+		b.Run(enc.String(), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_, release, err := encodeQuery(&qb.query, enc)
+				if err != nil {
+					b.Fatal(err)
+				}
+				release()
+			}
+		})
+	}
+}
+
+func BenchmarkEncoding_GenerateSQL(b *testing.B) {
+	e := setupTestEngine(b)
+
+	for _, enc := range benchmarkEncodings {
+		b.Run(enc.String(), func(b *testing.B) {
+			e.SetEncoding(enc)
+			qb := benchmarkQuery(e)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := qb.ToSQL(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkEncoding_Compile(b *testing.B) {
+	e := setupTestEngine(b)
+
+	for _, enc := range benchmarkEncodings {
+		b.Run(enc.String(), func(b *testing.B) {
+			e.SetEncoding(enc)
+			qb := benchmarkQuery(e)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := qb.compile(nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -5,27 +5,51 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"unsafe"
+	"sync"
 
 	"github.com/chameleon-db/chameleondb/chameleon/internal/ffi"
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/chamerr"
+	"github.com/jackc/pgx/v5"
 )
 
 // Engine is the main entry point for ChameleonDB
 type Engine struct {
+	// Guards schema, encoding and mutations: a schema may be reloaded
+	// while queries run (see setSchema)
+	mu        sync.RWMutex
 	schema    *Schema
 	connector *Connector
 	executor  *Executor
-	ffiHandle unsafe.Pointer
+
+	// Schema loaded into the Rust core (see core)
+	coreMu     sync.Mutex
+	coreSchema *ffi.Schema
+	coreFor    *Schema // the schema coreSchema was loaded from
+
+	// Format of queries sent to the core (see SetEncoding)
+	encoding Encoding
 
 	// Debug context
 	Debug *DebugContext
 
+	// Query hooks (see AddHook)
+	hooks    []QueryHook
+	redactor Redactor
+
+	// Generated SQL by query shape (see SetQueryCacheSize)
+	queryCache *queryCache
+
 	// Mutation factory (abstract, injected)
 	mutations MutationFactory
+	// True when mutations came from the registered default constructor
+	defaultMutations bool
 }
 
+// Schema returns the currently loaded schema (nil if none)
 func (e *Engine) Schema() *Schema {
-	panic("unimplemented")
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.schema
 }
 
 // ============================================================
@@ -36,7 +60,7 @@ func (e *Engine) Schema() *Schema {
 //
 // Default behavior:
 //   - Loads schema from "schema.cham" if it exists
-//   - Auto-initializes mutation factory
+//   - Auto-initializes mutation factory (see RegisterMutationFactory)
 //   - Ready to use immediately
 //
 // If "schema.cham" doesn't exist, returns engine without schema
@@ -73,22 +97,22 @@ func NewEngineWithSchema(schemaPath string) (*Engine, error) {
 // User must call LoadSchemaFromFile or LoadSchemaFromString manually
 func NewEngineWithoutSchema() *Engine {
 	return &Engine{
-		Debug: DefaultDebugContext(),
+		Debug:      DefaultDebugContext(),
+		queryCache: newQueryCache(defaultQueryCacheSize),
 	}
 }
 
 // newEngineWithPath is the internal helper
 func newEngineWithPath(schemaPath string) *Engine {
 	eng := &Engine{
-		Debug: DefaultDebugContext(),
+		Debug:      DefaultDebugContext(),
+		queryCache: newQueryCache(defaultQueryCacheSize),
 	}
 
 	// Try to load schema silently (don't fail if missing)
+	// The mutation factory is initialized by LoadSchemaFromFile
 	if _, err := os.Stat(schemaPath); err == nil {
-		if _, err := eng.LoadSchemaFromFile(schemaPath); err == nil {
-			// Initialize mutation factory after schema load
-			//eng.SetMutationFactory(mutation.NewFactory(schema))
-		}
+		eng.LoadSchemaFromFile(schemaPath)
 	}
 
 	return eng
@@ -111,10 +135,13 @@ func (e *Engine) WithDebug(level DebugLevel) *Engine {
 
 // LoadSchemaFromString parses a schema from a string
 func (e *Engine) LoadSchemaFromString(input string) (*Schema, error) {
+	if err := CheckCore(); err != nil {
+		return nil, err
+	}
+
 	schemaJSON, err := ffi.ParseSchema(input)
 	if err != nil {
-		formattedErr := FormatError(err.Error())
-		return nil, fmt.Errorf("%s", formattedErr)
+		return nil, chamerr.FromCore(err.Error())
 	}
 
 	var schema Schema
@@ -122,10 +149,73 @@ func (e *Engine) LoadSchemaFromString(input string) (*Schema, error) {
 		return nil, fmt.Errorf("failed to deserialize schema: %w", err)
 	}
 
-	e.schema = &schema
+	e.setSchema(&schema)
 	return &schema, nil
 }
 
+// setSchema installs a freshly loaded schema and rebuilds the default
+// mutation factory so it never outlives the schema it was built for.
+// Queries already running finish with the schema they started with.
+func (e *Engine) setSchema(schema *Schema) {
+	e.mu.Lock()
+	e.schema = schema
+	e.queryCache.purge(schema)
+	keep := e.mutations != nil && !e.defaultMutations
+	e.mu.Unlock()
+	e.dropCore()
+
+	if keep {
+		// Custom factory injected via SetMutationFactory
+		return
+	}
+
+	// The constructor reads the schema back through Schema()
+	mutations := newDefaultMutationFactory(e)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.schema == schema && (e.mutations == nil || e.defaultMutations) {
+		e.mutations = mutations
+		e.defaultMutations = mutations != nil
+	}
+}
+
+// core returns schema as loaded in the Rust core, loading it on first use
+// after a schema is loaded (or after Close). Every engine has its own, so
+// engines with different schemas can generate SQL in parallel.
+func (e *Engine) core(schema *Schema) (*ffi.Schema, error) {
+	e.coreMu.Lock()
+	defer e.coreMu.Unlock()
+
+	if schema == nil {
+		return nil, fmt.Errorf("no schema loaded")
+	}
+	if e.coreSchema != nil && e.coreFor == schema {
+		return e.coreSchema, nil
+	}
+
+	schemaJSON, err := json.Marshal(schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize schema: %w", err)
+	}
+	core, err := ffi.LoadSchema(string(schemaJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema into core: %w", err)
+	}
+
+	e.coreSchema, e.coreFor = core, schema
+	return core, nil
+}
+
+// dropCore forgets the core schema of a replaced schema. It is not closed:
+// queries may still be generating SQL with it, and its finalizer frees it.
+func (e *Engine) dropCore() {
+	e.coreMu.Lock()
+	defer e.coreMu.Unlock()
+
+	e.coreSchema, e.coreFor = nil, nil
+}
+
 // LoadSchemaFromFile loads a schema from a .cham file
 func (e *Engine) LoadSchemaFromFile(filepath string) (*Schema, error) {
 	content, err := os.ReadFile(filepath)
@@ -137,7 +227,7 @@ func (e *Engine) LoadSchemaFromFile(filepath string) (*Schema, error) {
 
 // GetSchema returns the currently loaded schema
 func (e *Engine) GetSchema() *Schema {
-	return e.schema
+	return e.Schema()
 }
 
 //
@@ -146,11 +236,23 @@ func (e *Engine) GetSchema() *Schema {
 // ─────────────────────────────────────────────────────────────
 //
 
+// Connector returns the database connector (nil if not connected)
+func (e *Engine) Connector() *Connector {
+	return e.connector
+}
+
 // Version returns the engine version
 func (e *Engine) Version() string {
 	return ffi.Version()
 }
 
+// CheckCore reports whether the loaded Rust core library matches this
+// build: same ABI version and every capability the engine relies on.
+// The engine refuses to load schemas with a mismatched library.
+func CheckCore() error {
+	return ffi.CheckABI()
+}
+
 // Connect establishes a database connection
 func (e *Engine) Connect(ctx context.Context, config ConnectorConfig) error {
 	e.connector = NewConnector(config)
@@ -161,11 +263,19 @@ func (e *Engine) Connect(ctx context.Context, config ConnectorConfig) error {
 	return nil
 }
 
-// Close closes the database connection
+// Close closes the database connection and frees the schema held by the
+// Rust core (it is loaded again if the engine is used afterwards)
 func (e *Engine) Close() {
 	if e.connector != nil {
 		e.connector.Close()
 	}
+
+	e.coreMu.Lock()
+	defer e.coreMu.Unlock()
+	if e.coreSchema != nil {
+		e.coreSchema.Close()
+		e.coreSchema, e.coreFor = nil, nil
+	}
 }
 
 // IsConnected returns true if connected to a database
@@ -189,16 +299,46 @@ func (e *Engine) Ping(ctx context.Context) error {
 
 // GenerateMigration generates DDL SQL from the loaded schema
 func (e *Engine) GenerateMigration() (string, error) {
-	if e.schema == nil {
+	schema := e.Schema()
+	if schema == nil {
 		return "", fmt.Errorf("no schema loaded")
 	}
 
-	schemaJSON, err := json.Marshal(e.schema)
+	core, err := e.core(schema)
+	if err != nil {
+		return "", err
+	}
+	return core.GenerateMigration()
+}
+
+// ApplyMigration runs migration DDL (see GenerateMigration) in a single
+// transaction. Hooks see it as an OpMigration event.
+func (e *Engine) ApplyMigration(ctx context.Context, sql string) error {
+	if e.connector == nil {
+		return fmt.Errorf("not connected - call engine.Connect() first")
+	}
+
+	ctx, done := TraceQuery(ctx, e.Hook(), &QueryEvent{Operation: OpMigration, SQL: sql})
+	err := e.applyMigration(ctx, sql)
+	done(0, err)
+
+	return err
+}
+
+func (e *Engine) applyMigration(ctx context.Context, sql string) error {
+	tx, err := e.connector.Begin(ctx, pgx.TxOptions{})
 	if err != nil {
-		return "", fmt.Errorf("failed to serialize schema: %w", err)
+		return fmt.Errorf("failed to begin migration: %w", err)
 	}
+	defer tx.Rollback(context.Background())
 
-	return ffi.GenerateMigration(string(schemaJSON))
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return fmt.Errorf("migration failed: %w", TranslatePgError(e.Schema(), err))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+	return nil
 }
 
 // ─────────────────────────────────────────────────────────────
@@ -206,38 +346,64 @@ func (e *Engine) GenerateMigration() (string, error) {
 // ─────────────────────────────────────────────────────────────
 
 // SetMutationFactory injects a mutation factory implementation
+// A custom factory is kept across schema reloads
 func (e *Engine) SetMutationFactory(factory MutationFactory) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	e.mutations = factory
+	e.defaultMutations = false
 }
 
-func (e *Engine) ensureMutationFactory() {
-	if e.mutations == nil {
+// mutationFactory returns the mutation factory, panicking without one
+func (e *Engine) mutationFactory() MutationFactory {
+	e.mu.RLock()
+	mutations := e.mutations
+	e.mu.RUnlock()
+
+	if mutations == nil {
 		panic(
 			"mutation factory not initialized\n" +
-				"Call mutation.InitFactory(engine) after loading schema",
+				"Import the default implementation:\n" +
+				"  import _ \"github.com/chameleon-db/chameleondb/chameleon/pkg/engine/mutation\"\n" +
+				"or call engine.SetMutationFactory(...) after loading schema",
 		)
 	}
+	return mutations
 }
 
 // Insert starts a new INSERT mutation
 func (e *Engine) Insert(entity string) InsertMutation {
 	e.ensureSchemaLoaded()
-	e.ensureMutationFactory()
-	return e.mutations.NewInsert(entity)
+	return e.mutationFactory().NewInsert(entity)
 }
 
 // Update starts a new UPDATE mutation
 func (e *Engine) Update(entity string) UpdateMutation {
 	e.ensureSchemaLoaded()
-	e.ensureMutationFactory()
-	return e.mutations.NewUpdate(entity)
+	return e.mutationFactory().NewUpdate(entity)
 }
 
 // Delete starts a new DELETE mutation
 func (e *Engine) Delete(entity string) DeleteMutation {
 	e.ensureSchemaLoaded()
-	e.ensureMutationFactory()
-	return e.mutations.NewDelete(entity)
+	return e.mutationFactory().NewDelete(entity)
+}
+
+// Link starts a connect/disconnect mutation on a ManyToMany relation
+// of the row identified by id
+//
+//	eng.Link("Post", "tags", postID).Connect(goID, rustID).Execute(ctx)
+func (e *Engine) Link(entity string, relation string, id interface{}) LinkMutation {
+	e.ensureSchemaLoaded()
+	return linkFactory(e.mutationFactory()).NewLink(entity, relation, id)
+}
+
+func linkFactory(factory MutationFactory) LinkMutationFactory {
+	links, ok := factory.(LinkMutationFactory)
+	if !ok {
+		panic("mutation factory does not support links (must implement engine.LinkMutationFactory)")
+	}
+	return links
 }
 
 // ─────────────────────────────────────────────────────────────
@@ -255,7 +421,7 @@ func (s *Schema) GetEntity(name string) *Entity {
 }
 
 func (e *Engine) ensureSchemaLoaded() {
-	if e.schema == nil {
+	if e.Schema() == nil {
 		panic("schema not loaded")
 	}
 }
@@ -1,7 +1,13 @@
 package engine
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/chamerr"
 )
 
 func TestEngineLoadSchema(t *testing.T) {
@@ -77,6 +83,9 @@ func TestEngineVersion(t *testing.T) {
 	if version == "" {
 		t.Error("Version should not be empty")
 	}
+	if err := CheckCore(); err != nil {
+		t.Errorf("Core library should match this build: %v", err)
+	}
 
 	t.Logf("ChameleonDB version: %s", version)
 }
@@ -89,5 +98,84 @@ func TestInvalidSchema(t *testing.T) {
 		t.Error("Expected error for invalid syntax")
 	}
 
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || !errors.Is(err, chamerr.ErrSchema) {
+		t.Errorf("Expected a ParseError, got %T", err)
+	}
+
 	t.Logf("Got expected error: %v", err)
 }
+
+func TestEngineCoreSchema(t *testing.T) {
+	eng := NewEngineWithoutSchema()
+	if _, err := eng.GenerateMigration(); err == nil {
+		t.Error("Expected an error without a schema")
+	}
+
+	if _, err := eng.LoadSchemaFromString(`entity User { id: uuid primary, }`); err != nil {
+		t.Fatal(err)
+	}
+	first, err := eng.core(eng.Schema())
+	if err != nil {
+		t.Fatalf("core() failed: %v", err)
+	}
+	if again, _ := eng.core(eng.Schema()); again != first {
+		t.Error("Expected the core schema to be loaded once")
+	}
+
+	// Reloading the schema loads a new core schema
+	if _, err := eng.LoadSchemaFromString(`entity Post { id: uuid primary, }`); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := eng.core(eng.Schema())
+	if second == first {
+		t.Error("Expected a new core schema after reload")
+	}
+	migration, err := eng.GenerateMigration()
+	if err != nil || !strings.Contains(migration, "CREATE TABLE posts") {
+		t.Errorf("Expected the posts migration, got %q (%v)", migration, err)
+	}
+
+	// Close frees it; the engine loads it again when used
+	eng.Close()
+	if _, err := second.GenerateSQL(`{}`); err == nil {
+		t.Error("Expected a closed core schema to fail")
+	}
+	if _, err := eng.Query("Post").ToSQL(); err != nil {
+		t.Errorf("Expected the engine to work after Close: %v", err)
+	}
+}
+
+func TestEngineCoreSchema_ParallelEngines(t *testing.T) {
+	engines := make([]*Engine, 4)
+	for i := range engines {
+		engines[i] = NewEngineWithoutSchema()
+		source := fmt.Sprintf(`entity Item%d { id: uuid primary, }`, i)
+		if _, err := engines[i].LoadSchemaFromString(source); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i, eng := range engines {
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				entity := fmt.Sprintf("Item%d", i)
+				for range 50 {
+					generated, err := eng.Query(entity).ToSQL()
+					if err != nil {
+						t.Errorf("%s: %v", entity, err)
+						return
+					}
+					if !strings.Contains(generated.MainQuery, "FROM item"+fmt.Sprint(i)) {
+						t.Errorf("%s: got SQL of another schema: %s", entity, generated.MainQuery)
+						return
+					}
+				}
+			}()
+		}
+	}
+	wg.Wait()
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/chameleon-db/chameleondb/chameleon/internal/ffi"
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/chamerr"
 	"github.com/fatih/color"
 )
 
@@ -76,16 +77,23 @@ func formatParseError(detail ParseErrorDetail) string {
 
 // LoadSchemaFromStringRaw loads schema and returns raw error (no formatting)
 func (e *Engine) LoadSchemaFromStringRaw(input string) (*Schema, string, error) {
+	if err := CheckCore(); err != nil {
+		return nil, err.Error(), err
+	}
+
 	// 1. Validate schema (handles BOTH parse errors and type check errors)
 	rawErr, err := ffi.ValidateSchemaRaw(input)
 	if err != nil {
+		if rawErr != "" {
+			err = chamerr.FromCore(rawErr)
+		}
 		return nil, rawErr, err
 	}
 
 	// 2. If validation passed, parse schema
 	schemaJSON, err := ffi.ParseSchema(input)
 	if err != nil {
-		return nil, err.Error(), err
+		return nil, err.Error(), chamerr.FromCore(err.Error())
 	}
 
 	var schema Schema
@@ -93,6 +101,6 @@ func (e *Engine) LoadSchemaFromStringRaw(input string) (*Schema, string, error)
 		return nil, "", fmt.Errorf("failed to deserialize schema: %w", err)
 	}
 
-	e.schema = &schema
+	e.setSchema(&schema)
 	return &schema, "", nil
 }
@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ============================================================
+// DATABASE ERRORS (Postgres errors mapped back to the schema)
+// ============================================================
+
+// SQLSTATE codes translated by TranslatePgError
+const (
+	sqlStateNotNullViolation     = "23502"
+	sqlStateForeignKeyViolation  = "23503"
+	sqlStateUniqueViolation      = "23505"
+	sqlStateCheckViolation       = "23514"
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
+// TranslatePgError maps a Postgres error onto the typed errors of this
+// package, naming the entity, field and relation involved:
+//
+//	23505 unique_violation      → *UniqueViolationError
+//	23502 not_null_violation    → *NotNullError
+//	23503 foreign_key_violation → *ConstraintError (foreign_key)
+//	23514 check_violation       → *ConstraintError (check)
+//	40001, 40P01                → *SerializationError
+//
+// Other errors are returned unchanged.
+func TranslatePgError(schema *Schema, err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	ent := schema.entityForTable(pgErr.TableName)
+	entity := pgErr.TableName
+	if ent != nil {
+		entity = ent.Name
+	}
+	columns, value := parseKeyDetail(pgErr.Detail)
+
+	switch pgErr.Code {
+	case sqlStateUniqueViolation:
+		field := strings.Join(columns, ", ")
+		if field == "" {
+			field = constraintField(ent, pgErr.TableName, pgErr.ConstraintName)
+		}
+		return &UniqueViolationError{
+			Entity:     entity,
+			Field:      field,
+			Value:      value,
+			Constraint: pgErr.ConstraintName,
+			Suggestion: fmt.Sprintf("Another %s already has %s = %v; use a different value or update the existing row", entity, field, value),
+			Err:        pgErr,
+		}
+
+	case sqlStateNotNullViolation:
+		return &NotNullError{
+			Entity:     entity,
+			Field:      pgErr.ColumnName,
+			Suggestion: fmt.Sprintf("Set '%s' when writing %s", pgErr.ColumnName, entity),
+			Err:        pgErr,
+		}
+
+	case sqlStateForeignKeyViolation:
+		return foreignKeyViolation(schema, ent, entity, pgErr, columns, value)
+
+	case sqlStateCheckViolation:
+		field := constraintField(ent, pgErr.TableName, pgErr.ConstraintName)
+		return &ConstraintError{
+			Type:       "check",
+			Entity:     entity,
+			Field:      field,
+			Constraint: pgErr.ConstraintName,
+			Suggestion: checkSuggestion(ent, pgErr.TableName, field, pgErr.ConstraintName),
+			Err:        pgErr,
+		}
+
+	case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+		return &SerializationError{SQLState: pgErr.Code, Message: pgErr.Message, Err: pgErr}
+	}
+
+	return err
+}
+
+// foreignKeyViolation covers both directions of a FK violation: writing a
+// key with no referenced row, and deleting a row that is still referenced.
+// Postgres reports the referencing table in both cases.
+func foreignKeyViolation(schema *Schema, ent *Entity, entity string, pgErr *pgconn.PgError, columns []string, value interface{}) error {
+	referenced := strings.Contains(pgErr.Detail, "is still referenced")
+
+	field := constraintField(ent, pgErr.TableName, pgErr.ConstraintName)
+	if field == "" && !referenced && len(columns) == 1 {
+		field = columns[0]
+	}
+
+	cerr := &ConstraintError{
+		Type:       "foreign_key",
+		Entity:     entity,
+		Field:      field,
+		Value:      value,
+		Constraint: pgErr.ConstraintName,
+		Err:        pgErr,
+	}
+
+	target, pk := "referenced row", "id"
+	if ent != nil && schema != nil {
+		for _, ref := range schema.foreignKeyRefs(ent) {
+			if ref.Field == field {
+				cerr.Relation = ref.Relation
+				target, pk = ref.Target.Name, ref.Target.PrimaryKey()
+				break
+			}
+		}
+	}
+
+	if referenced {
+		cerr.Suggestion = fmt.Sprintf("%s rows still reference this %s through '%s'; delete or reassign them first", entity, target, field)
+	} else {
+		cerr.Suggestion = fmt.Sprintf("No %s with %s = %v exists; create it first or fix '%s'", target, pk, value, field)
+	}
+	return cerr
+}
+
+// keyDetail matches the DETAIL of unique and FK violations:
+//
+//	Key (email)=(ana@mail.com) already exists.
+//	Key (user_id)=(…) is not present in table "users".
+var keyDetail = regexp.MustCompile(`^Key \((.+?)\)=\((.*)\) (?:already exists|is not present|is still referenced)`)
+
+// parseKeyDetail extracts the columns and value from a violation DETAIL
+func parseKeyDetail(detail string) ([]string, interface{}) {
+	m := keyDetail.FindStringSubmatch(detail)
+	if m == nil {
+		return nil, nil
+	}
+	return strings.Split(m[1], ", "), m[2]
+}
+
+// constraintField finds the field a constraint is named after. Postgres
+// names constraints {table}_{column}_{suffix} ("users_email_key",
+// "orders_user_id_fkey"), and the migration generator names CHECKs
+// {table}_{column}_{kind} ("users_age_min"), so the longest field name
+// after the table prefix wins.
+func constraintField(ent *Entity, table, constraint string) string {
+	if ent == nil {
+		return ""
+	}
+	rest, ok := strings.CutPrefix(constraint, table+"_")
+	if !ok {
+		return ""
+	}
+
+	best := ""
+	for name := range ent.Fields {
+		if strings.HasPrefix(rest, name+"_") && len(name) > len(best) {
+			best = name
+		}
+	}
+	return best
+}
+
+// checkSuggestion names the schema constraint behind a CHECK violation,
+// e.g. "users_age_min" → "'age' must satisfy min(0)"
+func checkSuggestion(ent *Entity, table, field, constraint string) string {
+	fallback := fmt.Sprintf("Value violates the CHECK constraint '%s'", constraint)
+	if ent == nil || field == "" {
+		return fallback
+	}
+
+	// "min", "length", "check", "check_2" (second check of the field)
+	kind := strings.TrimPrefix(constraint, table+"_"+field+"_")
+	nth := 1
+	if base, n, ok := strings.Cut(kind, "_"); ok {
+		if i, err := strconv.Atoi(n); err == nil {
+			kind, nth = base, i
+		}
+	}
+
+	seen := 0
+	for _, c := range ent.Fields[field].Constraints {
+		if !strings.EqualFold(c.Kind, kind) {
+			continue
+		}
+		if seen++; seen == nth {
+			return fmt.Sprintf("'%s' must satisfy %s", field, c)
+		}
+	}
+	return fallback
+}
+
+// entityForTable finds the entity stored in table
+func (s *Schema) entityForTable(table string) *Entity {
+	if s == nil || table == "" {
+		return nil
+	}
+	for _, ent := range s.Entities {
+		if EntityToTable(ent.Name) == table {
+			return ent
+		}
+	}
+	return nil
+}
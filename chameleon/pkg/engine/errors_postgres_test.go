@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func pgErrorSchema(t *testing.T) *Schema {
+	t.Helper()
+	schema, err := ParseSchemaJSON(`{"entities": [
+		{"name": "User", "fields": {
+			"id": {"name": "id", "field_type": "UUID", "nullable": false, "unique": false, "primary_key": true},
+			"email": {"name": "email", "field_type": "String", "nullable": false, "unique": true, "primary_key": false},
+			"age": {"name": "age", "field_type": "Int", "nullable": true, "unique": false, "primary_key": false,
+				"constraints": [{"Min": "0"}, {"Check": "age < 150"}, {"Check": "age <> 13"}]}
+		}, "relations": {
+			"orders": {"name": "orders", "kind": "HasMany", "target_entity": "Order", "foreign_key": "user_id", "through": null}
+		}},
+		{"name": "Order", "fields": {
+			"id": {"name": "id", "field_type": "UUID", "nullable": false, "unique": false, "primary_key": true},
+			"user_id": {"name": "user_id", "field_type": "UUID", "nullable": false, "unique": false, "primary_key": false}
+		}, "relations": {
+			"user": {"name": "user", "kind": "BelongsTo", "target_entity": "User", "foreign_key": null, "through": null}
+		}}
+	]}`)
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	return schema
+}
+
+func TestTranslatePgError_Unique(t *testing.T) {
+	err := TranslatePgError(pgErrorSchema(t), fmt.Errorf("exec: %w", &pgconn.PgError{
+		Code:           "23505",
+		TableName:      "users",
+		ConstraintName: "users_email_key",
+		Detail:         "Key (email)=(ana@mail.com) already exists.",
+	}))
+
+	var unique *UniqueViolationError
+	if !errors.As(err, &unique) {
+		t.Fatalf("Expected UniqueViolationError, got %v", err)
+	}
+	if unique.Entity != "User" || unique.Field != "email" || unique.Value != "ana@mail.com" {
+		t.Errorf("Unexpected error: %+v", unique)
+	}
+	if unique.Code() != "UNIQUE_VIOLATION" || !IsConstraintError(err) {
+		t.Errorf("Unexpected classification: %s", unique.Code())
+	}
+
+	// The Postgres error stays reachable
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.ConstraintName != "users_email_key" {
+		t.Errorf("Expected wrapped PgError, got %v", pgErr)
+	}
+}
+
+func TestTranslatePgError_NotNull(t *testing.T) {
+	err := TranslatePgError(pgErrorSchema(t), &pgconn.PgError{
+		Code:       "23502",
+		TableName:  "users",
+		ColumnName: "email",
+	})
+
+	var notNull *NotNullError
+	if !errors.As(err, &notNull) || notNull.Entity != "User" || notNull.Field != "email" {
+		t.Fatalf("Expected NotNullError on User.email, got %v", err)
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.ColumnName != "email" {
+		t.Errorf("Expected wrapped PgError, got %v", pgErr)
+	}
+	if ErrorCode(fmt.Errorf("insert into User failed: %w", err)) != "NOT_NULL_VIOLATION" {
+		t.Errorf("Expected ErrorCode to look through wrapping")
+	}
+}
+
+func TestTranslatePgError_ForeignKey(t *testing.T) {
+	schema := pgErrorSchema(t)
+
+	// Insert with a missing parent
+	err := TranslatePgError(schema, &pgconn.PgError{
+		Code:           "23503",
+		TableName:      "orders",
+		ConstraintName: "orders_user_id_fkey",
+		Detail:         `Key (user_id)=(99999999-9999-9999-9999-999999999999) is not present in table "users".`,
+	})
+	var cerr *ConstraintError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("Expected ConstraintError, got %v", err)
+	}
+	if cerr.Type != "foreign_key" || cerr.Entity != "Order" || cerr.Field != "user_id" || cerr.Relation != "user" ||
+		cerr.Value != "99999999-9999-9999-9999-999999999999" {
+		t.Errorf("Unexpected error: %+v", cerr)
+	}
+	assertContains(t, cerr.Suggestion, "No User with id")
+	assertContains(t, cerr.Error(), "Field: Order.user_id (relation 'user')")
+
+	// Delete of a parent that still has children
+	err = TranslatePgError(schema, &pgconn.PgError{
+		Code:           "23503",
+		TableName:      "orders",
+		ConstraintName: "orders_user_id_fkey",
+		Detail:         `Key (id)=(11111111-1111-1111-1111-111111111111) is still referenced from table "orders".`,
+	})
+	if !errors.As(err, &cerr) || cerr.Entity != "Order" || cerr.Field != "user_id" {
+		t.Fatalf("Expected ConstraintError on Order.user_id, got %v", err)
+	}
+	assertContains(t, cerr.Suggestion, "Order rows still reference this User")
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23503" {
+		t.Errorf("Expected wrapped PgError, got %v", pgErr)
+	}
+}
+
+func TestTranslatePgError_Check(t *testing.T) {
+	cases := map[string]string{
+		"users_age_min":     "'age' must satisfy min(0)",
+		"users_age_check":   `'age' must satisfy check("age < 150")`,
+		"users_age_check_2": `'age' must satisfy check("age <> 13")`,
+		"users_other":       "Value violates the CHECK constraint 'users_other'",
+	}
+	for constraint, suggestion := range cases {
+		err := TranslatePgError(pgErrorSchema(t), &pgconn.PgError{
+			Code:           "23514",
+			TableName:      "users",
+			ConstraintName: constraint,
+		})
+		var cerr *ConstraintError
+		if !errors.As(err, &cerr) || cerr.Type != "check" || cerr.Constraint != constraint {
+			t.Errorf("%s: expected check ConstraintError, got %v", constraint, err)
+			continue
+		}
+		if cerr.Suggestion != suggestion {
+			t.Errorf("%s: suggestion = %q, want %q", constraint, cerr.Suggestion, suggestion)
+		}
+		if cerr.Entity != "User" {
+			t.Errorf("%s: entity = %q, want User", constraint, cerr.Entity)
+		}
+		if !errors.Is(err, cerr.Err) || cerr.Err.ConstraintName != constraint {
+			t.Errorf("%s: expected the PgError to be kept, got %v", constraint, cerr.Err)
+		}
+	}
+}
+
+func TestTranslatePgError_Serialization(t *testing.T) {
+	for _, code := range []string{"40001", "40P01"} {
+		err := TranslatePgError(nil, &pgconn.PgError{Code: code, Message: "could not serialize access"})
+		if !IsSerializationError(err) || ErrorCode(err) != "SERIALIZATION_FAILURE" {
+			t.Errorf("%s: expected SerializationError, got %v", code, err)
+		}
+	}
+}
+
+func TestTranslatePgError_Passthrough(t *testing.T) {
+	plain := errors.New("connection refused")
+	if TranslatePgError(pgErrorSchema(t), plain) != plain {
+		t.Errorf("Expected non-Postgres errors to pass through")
+	}
+
+	syntax := &pgconn.PgError{Code: "42601", Message: "syntax error"}
+	if err := TranslatePgError(pgErrorSchema(t), syntax); err != syntax {
+		t.Errorf("Expected untranslated SQLSTATE to pass through, got %v", err)
+	}
+
+	// Unknown tables still produce a typed error
+	err := TranslatePgError(pgErrorSchema(t), &pgconn.PgError{
+		Code:      "23505",
+		TableName: "audit_log",
+		Detail:    "Key (ref)=(x) already exists.",
+	})
+	var unique *UniqueViolationError
+	if !errors.As(err, &unique) || unique.Entity != "audit_log" || !strings.Contains(unique.Error(), "audit_log.ref") {
+		t.Errorf("Unexpected error for unknown table: %v", err)
+	}
+}
@@ -3,11 +3,17 @@ package engine
 import (
 	"context"
 	"fmt"
-	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// rowQuerier is satisfied by both *pgxpool.Pool and pgx.Tx
+type rowQuerier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
 // Executor runs queries against PostgreSQL
 type Executor struct {
 	connector *Connector
@@ -18,152 +24,196 @@ func NewExecutor(connector *Connector) *Executor {
 	return &Executor{connector: connector}
 }
 
-// Execute runs a QueryBuilder against the database
-func (ex *Executor) Execute(ctx context.Context, qb *QueryBuilder) (*QueryResult, error) {
+// Execute runs the SQL generated for a QueryBuilder against the database.
+// The result's Profile holds the time spent on each statement.
+func (ex *Executor) Execute(ctx context.Context, qb *QueryBuilder, generated *GeneratedSQL) (*QueryResult, error) {
 	if !ex.connector.IsConnected() {
 		return nil, fmt.Errorf("not connected to database")
 	}
 
-	// Generate SQL
-	generated, err := qb.ToSQL()
-	if err != nil {
-		return nil, fmt.Errorf("SQL generation failed: %w", err)
-	}
+	// Run on the query's transaction if it has one
+	db := ex.querier(qb)
+	profile := &QueryProfile{}
 
 	// Execute main query
-	mainRows, err := ex.executeQuery(ctx, generated.MainQuery)
+	mainRows, main, err := ex.runQuery(ctx, qb, db, &QueryEvent{
+		Operation: OpQuery,
+		Entity:    qb.query.Entity,
+		SQL:       generated.MainQuery,
+	}, generated.Args...)
 	if err != nil {
 		return nil, fmt.Errorf("main query failed: %w", err)
 	}
 
-	// Execute eager queries
+	profile.Main = main
+
+	relations, err := ex.loadIncludes(ctx, db, qb, generated.EagerQueries, mainRows, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryResult{
+		Entity:    qb.query.Entity,
+		Rows:      mainRows,
+		Relations: relations,
+		Profile:   profile,
+	}, nil
+}
+
+// querier returns the query's transaction if it has one, else the pool
+func (ex *Executor) querier(qb *QueryBuilder) rowQuerier {
+	if qb.tx != nil {
+		return qb.tx.tx
+	}
+	return ex.connector.Pool()
+}
+
+// loadIncludes runs the eager queries for a set of root rows and stitches
+// the children onto their parents. Returns all rows loaded per include path;
+// statement timings are added to profile unless it is nil.
+func (ex *Executor) loadIncludes(
+	ctx context.Context,
+	db rowQuerier,
+	qb *QueryBuilder,
+	eagerQueries [][]string,
+	mainRows []Row,
+	profile *QueryProfile,
+) (map[string][]Row, error) {
+	// Parents always come before their children
 	relations := make(map[string][]Row)
-	parentIDs := extractIDs(mainRows, "id")
+	levels := map[string][]Row{"": mainRows}
 
-	for _, eager := range generated.EagerQueries {
-		relName := eager[0]
+	for _, eager := range eagerQueries {
+		path := eager[0]
 		relSQL := eager[1]
 
-		// Replace $PARENT_IDS placeholder with actual values
-		sql, err := replacePlaceholder(relSQL, parentIDs)
+		step, err := planEagerStep(qb.engine.Schema(), qb.query.Entity, path)
 		if err != nil {
-			return nil, fmt.Errorf("eager query '%s' failed: %w", relName, err)
+			return nil, fmt.Errorf("eager query '%s' failed: %w", path, err)
 		}
 
-		eagerRows, err := ex.executeQuery(ctx, sql)
-		if err != nil {
-			return nil, fmt.Errorf("eager query '%s' failed: %w", relName, err)
+		parents := levels[step.ParentPath]
+		parentIDs := extractIDs(parents, step.ParentKey)
+
+		// No parents → nothing to load
+		var children []Row
+		stats := StatementProfile{Path: path}
+		if len(parentIDs) > 0 {
+			// Parent keys are bound as one array parameter ($1)
+			children, stats, err = ex.runQuery(ctx, qb, db, &QueryEvent{
+				Operation: OpEager,
+				Entity:    step.Target.Name,
+				Path:      path,
+				SQL:       relSQL,
+			}, parentIDs)
+			if err != nil {
+				return nil, fmt.Errorf("eager query '%s' failed: %w", path, err)
+			}
+			stats.Path = path
+		}
+		if profile != nil {
+			profile.Eager = append(profile.Eager, stats)
+		}
+		if children == nil {
+			children = []Row{}
 		}
 
-		relations[relName] = eagerRows
+		stitch(parents, children, step)
 
-		// Update parentIDs for next level (nested includes)
-		// The FK field name is in the WHERE clause, extract the relevant IDs
-		parentIDs = extractIDs(eagerRows, "id")
+		levels[path] = children
+		relations[path] = children
 	}
 
-	return &QueryResult{
-		Entity:    qb.query.Entity,
-		Rows:      mainRows,
-		Relations: relations,
-	}, nil
+	return relations, nil
 }
 
 // executeQuery runs a single SQL query and returns rows
-func (ex *Executor) executeQuery(ctx context.Context, sql string) ([]Row, error) {
-	rows, err := ex.connector.Pool().Query(ctx, sql)
+func (ex *Executor) executeQuery(ctx context.Context, db rowQuerier, sql string, args ...interface{}) ([]Row, error) {
+	rows, _, err := ex.executeProfiled(ctx, db, sql, args...)
+	return rows, err
+}
+
+// executeProfiled runs a single SQL query, timing the database apart
+// from row scanning
+func (ex *Executor) executeProfiled(ctx context.Context, db rowQuerier, sql string, args ...interface{}) ([]Row, StatementProfile, error) {
+	start := time.Now()
+
+	rows, err := db.Query(ctx, sql, args...)
 	if err != nil {
-		return nil, err
+		return nil, StatementProfile{Database: time.Since(start)}, err
 	}
 	defer rows.Close()
 
-	return scanRows(rows)
+	result, scan, err := scanRows(rows)
+	stats := StatementProfile{
+		Database: time.Since(start) - scan,
+		Scan:     scan,
+		Rows:     len(result),
+	}
+	return result, stats, err
 }
 
-// scanRows converts pgx rows into our Row type
-func scanRows(rows pgx.Rows) ([]Row, error) {
+// scanRows converts pgx rows into our Row type, returning the time
+// spent converting (as opposed to waiting for rows)
+func scanRows(rows pgx.Rows) ([]Row, time.Duration, error) {
 	var result []Row
+	var scan time.Duration
 	columns := rows.FieldDescriptions()
 
 	for rows.Next() {
-		values, err := rows.Values()
+		start := time.Now()
+		row, err := scanRow(rows, columns)
+		scan += time.Since(start)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
-		}
-
-		row := make(Row)
-		for i, col := range columns {
-			row[col.Name] = values[i]
+			return nil, scan, err
 		}
 		result = append(result, row)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, scan, err
 	}
 
-	return result, nil
+	return result, scan, nil
 }
 
-// extractIDs pulls a specific field from all rows and converts to string if needed
-func extractIDs(rows []Row, field string) []interface{} {
-	ids := make([]interface{}, 0, len(rows))
-	for _, row := range rows {
-		if id, ok := row[field]; ok {
-			// Convert UUID types to string
-			switch v := id.(type) {
-			case []byte:
-				ids = append(ids, string(v))
-			case [16]byte: // PostgreSQL UUID type
-				// Convert to standard UUID string format
-				ids = append(ids, uuidToString(v))
-			case string:
-				ids = append(ids, v)
-			default:
-				ids = append(ids, id)
-			}
-		}
+// scanRow converts the current pgx row into a Row
+func scanRow(rows pgx.Rows, columns []pgconn.FieldDescription) (Row, error) {
+	values, err := rows.Values()
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
 	}
-	return ids
-}
 
-// uuidToString converts a [16]byte UUID to standard string format
-func uuidToString(uuid [16]byte) string {
-	return fmt.Sprintf("%x-%x-%x-%x-%x",
-		uuid[0:4],
-		uuid[4:6],
-		uuid[6:8],
-		uuid[8:10],
-		uuid[10:16])
+	row := make(Row, len(columns))
+	for i, col := range columns {
+		row[col.Name] = values[i]
+	}
+	return row, nil
 }
 
-// replacePlaceholder replaces $PARENT_IDS with actual IN clause values
-func replacePlaceholder(sql string, ids []interface{}) (string, error) {
-	if len(ids) == 0 {
-		return strings.Replace(sql, "$PARENT_IDS", "NULL", 1), nil
-	}
+// extractIDs collects the distinct non-NULL values of a key field
+//
+// Values keep the type pgx scanned them as (int64, [16]byte UUID,
+// string, ...) so they can be bound directly as an array parameter.
+func extractIDs(rows []Row, field string) []interface{} {
+	ids := make([]interface{}, 0, len(rows))
+	seen := make(map[interface{}]bool, len(rows))
 
-	placeholders := make([]string, len(ids))
-	for i, id := range ids {
-		switch v := id.(type) {
-		case string:
-			// Escape single quotes and wrap in quotes
-			escaped := strings.ReplaceAll(v, "'", "''")
-			placeholders[i] = fmt.Sprintf("'%s'", escaped)
-		case int, int32, int64, uint, uint32, uint64:
-			placeholders[i] = fmt.Sprintf("%d", v)
-		case float32, float64:
-			placeholders[i] = fmt.Sprintf("%f", v)
-		default:
-			// Fallback: convert to string and quote
-			placeholders[i] = fmt.Sprintf("'%v'", v)
+	for _, row := range rows {
+		id, ok := row[field]
+		if !ok || id == nil {
+			continue
 		}
-	}
 
-	return strings.Replace(
-		sql,
-		"$PARENT_IDS",
-		strings.Join(placeholders, ", "),
-		1,
-	), nil
+		key := id
+		if b, isBytes := id.([]byte); isBytes {
+			key = string(b) // slices are not comparable
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ids = append(ids, id)
+	}
+	return ids
 }
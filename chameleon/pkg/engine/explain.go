@@ -0,0 +1,291 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// ============================================================
+// EXPLAIN
+// ============================================================
+
+// ExplainResult holds the Postgres plans of a query: the main query and
+// each eager query, in execution order
+type ExplainResult struct {
+	Entity   string
+	Analyzed bool // plans carry actual times and row counts (EXPLAIN ANALYZE)
+	Main     *QueryPlan
+	Eager    []*QueryPlan
+}
+
+// Plans returns the main plan followed by the eager plans
+func (r *ExplainResult) Plans() []*QueryPlan {
+	return append([]*QueryPlan{r.Main}, r.Eager...)
+}
+
+// Warnings collects the warnings of every plan
+func (r *ExplainResult) Warnings() []string {
+	var warnings []string
+	for _, plan := range r.Plans() {
+		warnings = append(warnings, plan.Warnings...)
+	}
+	return warnings
+}
+
+// QueryPlan is the plan of a single SQL statement
+type QueryPlan struct {
+	Path string // include path; "" for the main query
+	SQL  string
+	Root *PlanNode
+
+	// Set by EXPLAIN ANALYZE only
+	PlanningTime  time.Duration
+	ExecutionTime time.Duration
+
+	Warnings []string
+}
+
+// PlanNode is one node of a plan tree, as reported by EXPLAIN (FORMAT JSON).
+// Costs are in planner units, times in milliseconds.
+type PlanNode struct {
+	NodeType  string  `json:"Node Type"`
+	Relation  string  `json:"Relation Name,omitempty"`
+	Alias     string  `json:"Alias,omitempty"`
+	Index     string  `json:"Index Name,omitempty"`
+	JoinType  string  `json:"Join Type,omitempty"`
+	Filter    string  `json:"Filter,omitempty"`
+	IndexCond string  `json:"Index Cond,omitempty"`
+	HashCond  string  `json:"Hash Cond,omitempty"`
+	Startup   float64 `json:"Startup Cost"`
+	Total     float64 `json:"Total Cost"`
+	PlanRows  float64 `json:"Plan Rows"`
+	PlanWidth int     `json:"Plan Width"`
+
+	// ANALYZE
+	ActualStartup       float64 `json:"Actual Startup Time,omitempty"`
+	ActualTotal         float64 `json:"Actual Total Time,omitempty"`
+	ActualRows          float64 `json:"Actual Rows,omitempty"`
+	ActualLoops         float64 `json:"Actual Loops,omitempty"`
+	RowsRemovedByFilter float64 `json:"Rows Removed by Filter,omitempty"`
+
+	// BUFFERS
+	SharedHitBlocks  int64 `json:"Shared Hit Blocks,omitempty"`
+	SharedReadBlocks int64 `json:"Shared Read Blocks,omitempty"`
+
+	Plans []*PlanNode `json:"Plans,omitempty"`
+}
+
+// Walk visits the node and its descendants, parents first
+func (n *PlanNode) Walk(fn func(node *PlanNode, depth int)) {
+	n.walk(fn, 0)
+}
+
+func (n *PlanNode) walk(fn func(node *PlanNode, depth int), depth int) {
+	fn(n, depth)
+	for _, child := range n.Plans {
+		child.walk(fn, depth+1)
+	}
+}
+
+// Label describes the node the way psql does,
+// e.g. "Index Scan using users_pkey on users u"
+func (n *PlanNode) Label() string {
+	label := n.NodeType
+	if n.Index != "" {
+		label += " using " + n.Index
+	}
+	if n.Relation != "" {
+		label += " on " + n.Relation
+		if n.Alias != "" && n.Alias != n.Relation {
+			label += " " + n.Alias
+		}
+	}
+	return label
+}
+
+// Plan warning thresholds
+const (
+	seqScanRowsWarning  = 10000 // unfiltered Seq Scans above this many rows
+	estimateFactorLimit = 10    // planned vs actual rows, either direction
+	estimateRowsMinimum = 100   // ignore estimate errors on tiny results
+)
+
+// Explain runs EXPLAIN for the main query and each eager query. With
+// analyze the statements are executed (EXPLAIN ANALYZE, BUFFERS) and the
+// plans carry actual times, row counts and buffer usage.
+//
+// Eager queries are bound to the keys of their parent rows, so the main
+// query and each eager level also run once to collect those keys.
+func (qb *QueryBuilder) Explain(ctx context.Context, analyze bool) (*ExplainResult, error) {
+	if qb.engine.executor == nil {
+		return nil, fmt.Errorf("executor not initialized - call engine.Connect() first")
+	}
+	if qb.isAggregate() {
+		return nil, fmt.Errorf("query has aggregates - EXPLAIN is not supported for Aggregate()")
+	}
+
+	generated, err := qb.compile(nil)
+	if err != nil {
+		return nil, err
+	}
+	return qb.engine.executor.Explain(ctx, qb, generated, analyze)
+}
+
+// Explain runs EXPLAIN for generated SQL on the query's connection
+func (ex *Executor) Explain(ctx context.Context, qb *QueryBuilder, generated *GeneratedSQL, analyze bool) (*ExplainResult, error) {
+	if !ex.connector.IsConnected() {
+		return nil, fmt.Errorf("not connected to database")
+	}
+	db := ex.querier(qb)
+	schema := qb.engine.Schema()
+
+	main, err := ex.explainQuery(ctx, db, generated.MainQuery, analyze, generated.Args...)
+	if err != nil {
+		return nil, fmt.Errorf("EXPLAIN main query failed: %w", TranslatePgError(schema, err))
+	}
+
+	result := &ExplainResult{
+		Entity:   qb.query.Entity,
+		Analyzed: analyze,
+		Main:     main,
+	}
+	if len(generated.EagerQueries) == 0 {
+		return result, nil
+	}
+
+	mainRows, err := ex.executeQuery(ctx, db, generated.MainQuery, generated.Args...)
+	if err != nil {
+		return nil, fmt.Errorf("main query failed: %w", TranslatePgError(schema, err))
+	}
+	levels := map[string][]Row{"": mainRows}
+
+	for _, eager := range generated.EagerQueries {
+		path, relSQL := eager[0], eager[1]
+
+		step, err := planEagerStep(schema, qb.query.Entity, path)
+		if err != nil {
+			return nil, fmt.Errorf("eager query '%s' failed: %w", path, err)
+		}
+		parentIDs := extractIDs(levels[step.ParentPath], step.ParentKey)
+
+		plan, err := ex.explainQuery(ctx, db, relSQL, analyze, parentIDs)
+		if err != nil {
+			return nil, fmt.Errorf("EXPLAIN eager query '%s' failed: %w", path, TranslatePgError(schema, err))
+		}
+		plan.Path = path
+		result.Eager = append(result.Eager, plan)
+
+		var children []Row
+		if len(parentIDs) > 0 {
+			children, err = ex.executeQuery(ctx, db, relSQL, parentIDs)
+			if err != nil {
+				return nil, fmt.Errorf("eager query '%s' failed: %w", path, TranslatePgError(schema, err))
+			}
+		}
+		levels[path] = children
+	}
+
+	return result, nil
+}
+
+// explainQuery runs EXPLAIN (FORMAT JSON) for one statement
+func (ex *Executor) explainQuery(ctx context.Context, db rowQuerier, sql string, analyze bool, args ...interface{}) (*QueryPlan, error) {
+	rows, err := db.Query(ctx, explainSQL(sql, analyze), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("EXPLAIN returned no plan")
+	}
+	var raw []byte
+	if err := rows.Scan(&raw); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	plan, err := parseExplain(raw)
+	if err != nil {
+		return nil, err
+	}
+	plan.SQL = sql
+	plan.Warnings = planWarnings(plan.Root, analyze)
+	return plan, nil
+}
+
+// explainSQL prefixes a statement with EXPLAIN
+func explainSQL(sql string, analyze bool) string {
+	if analyze {
+		return "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) " + sql
+	}
+	return "EXPLAIN (FORMAT JSON) " + sql
+}
+
+// parseExplain decodes the output of EXPLAIN (FORMAT JSON):
+//
+//	[{"Plan": {...}, "Planning Time": 0.1, "Execution Time": 0.2}]
+func parseExplain(raw []byte) (*QueryPlan, error) {
+	var out []struct {
+		Plan          *PlanNode `json:"Plan"`
+		PlanningTime  float64   `json:"Planning Time"`
+		ExecutionTime float64   `json:"Execution Time"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse EXPLAIN output: %w", err)
+	}
+	if len(out) == 0 || out[0].Plan == nil {
+		return nil, fmt.Errorf("EXPLAIN output has no plan")
+	}
+
+	return &QueryPlan{
+		Root:          out[0].Plan,
+		PlanningTime:  millis(out[0].PlanningTime),
+		ExecutionTime: millis(out[0].ExecutionTime),
+	}, nil
+}
+
+// millis converts EXPLAIN milliseconds to a Duration
+func millis(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// planWarnings flags the nodes worth a second look: sequential scans that
+// filter (an index may help), large unfiltered sequential scans, and, under
+// ANALYZE, row estimates off by an order of magnitude (stale statistics).
+func planWarnings(root *PlanNode, analyzed bool) []string {
+	var warnings []string
+
+	root.Walk(func(n *PlanNode, _ int) {
+		if n.NodeType == "Seq Scan" {
+			switch {
+			case n.Filter != "":
+				warnings = append(warnings, fmt.Sprintf(
+					"Seq Scan on %s with filter %s: consider an index on the filtered column", n.Relation, n.Filter))
+			case n.PlanRows >= seqScanRowsWarning:
+				warnings = append(warnings, fmt.Sprintf(
+					"Seq Scan on %s reads ~%.0f rows", n.Relation, n.PlanRows))
+			}
+		}
+
+		if analyzed && n.ActualLoops > 0 {
+			planned, actual := n.PlanRows, n.ActualRows
+			if math.Max(planned, actual) >= estimateRowsMinimum &&
+				math.Max(planned, actual) >= estimateFactorLimit*math.Max(math.Min(planned, actual), 1) {
+				warnings = append(warnings, fmt.Sprintf(
+					"%s: planned %.0f rows, got %.0f; statistics may be stale (run ANALYZE)", n.Label(), planned, actual))
+			}
+		}
+	})
+
+	return warnings
+}
@@ -0,0 +1,179 @@
+package engine
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleExplainAnalyze = `[
+  {
+    "Plan": {
+      "Node Type": "Sort",
+      "Startup Cost": 180.12,
+      "Total Cost": 182.62,
+      "Plan Rows": 1000,
+      "Plan Width": 72,
+      "Actual Startup Time": 3.1,
+      "Actual Total Time": 3.2,
+      "Actual Rows": 12,
+      "Actual Loops": 1,
+      "Plans": [
+        {
+          "Node Type": "Seq Scan",
+          "Parent Relationship": "Outer",
+          "Relation Name": "users",
+          "Alias": "users",
+          "Startup Cost": 0.00,
+          "Total Cost": 130.00,
+          "Plan Rows": 1000,
+          "Plan Width": 72,
+          "Actual Startup Time": 0.01,
+          "Actual Total Time": 2.9,
+          "Actual Rows": 12,
+          "Actual Loops": 1,
+          "Filter": "(age > 30)",
+          "Rows Removed by Filter": 4988,
+          "Shared Hit Blocks": 55,
+          "Shared Read Blocks": 0
+        }
+      ]
+    },
+    "Planning Time": 0.25,
+    "Triggers": [],
+    "Execution Time": 3.5
+  }
+]`
+
+const sampleExplainIndex = `[{"Plan": {
+  "Node Type": "Index Scan",
+  "Relation Name": "orders",
+  "Alias": "o",
+  "Index Name": "orders_user_id_idx",
+  "Index Cond": "(user_id = ANY ($1))",
+  "Startup Cost": 0.29,
+  "Total Cost": 8.31,
+  "Plan Rows": 4,
+  "Plan Width": 48
+}}]`
+
+func TestParseExplain(t *testing.T) {
+	plan, err := parseExplain([]byte(sampleExplainAnalyze))
+	if err != nil {
+		t.Fatalf("parseExplain: %v", err)
+	}
+
+	if plan.Root.NodeType != "Sort" || len(plan.Root.Plans) != 1 {
+		t.Fatalf("unexpected root: %+v", plan.Root)
+	}
+	scan := plan.Root.Plans[0]
+	if scan.Relation != "users" || scan.Filter != "(age > 30)" || scan.ActualRows != 12 || scan.SharedHitBlocks != 55 {
+		t.Errorf("unexpected scan node: %+v", scan)
+	}
+	if plan.PlanningTime != 250*time.Microsecond || plan.ExecutionTime != 3500*time.Microsecond {
+		t.Errorf("times = %v / %v", plan.PlanningTime, plan.ExecutionTime)
+	}
+
+	var depths []int
+	plan.Root.Walk(func(_ *PlanNode, depth int) { depths = append(depths, depth) })
+	if len(depths) != 2 || depths[0] != 0 || depths[1] != 1 {
+		t.Errorf("walk depths = %v", depths)
+	}
+}
+
+func TestParseExplain_Invalid(t *testing.T) {
+	for _, raw := range []string{`not json`, `[]`, `[{}]`} {
+		if _, err := parseExplain([]byte(raw)); err == nil {
+			t.Errorf("parseExplain(%q) should fail", raw)
+		}
+	}
+}
+
+func TestExplainSQL(t *testing.T) {
+	if got := explainSQL("SELECT 1", false); got != "EXPLAIN (FORMAT JSON) SELECT 1" {
+		t.Errorf("explainSQL = %q", got)
+	}
+	if got := explainSQL("SELECT 1", true); got != "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) SELECT 1" {
+		t.Errorf("explainSQL analyze = %q", got)
+	}
+}
+
+func TestPlanWarnings(t *testing.T) {
+	plan, err := parseExplain([]byte(sampleExplainAnalyze))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	warnings := planWarnings(plan.Root, true)
+	joined := strings.Join(warnings, "\n")
+	assertContains(t, joined, "Seq Scan on users with filter (age > 30)")
+	assertContains(t, joined, "planned 1000 rows, got 12")
+
+	// Without ANALYZE there are no actual rows to compare
+	if warnings := planWarnings(plan.Root, false); len(warnings) != 1 {
+		t.Errorf("expected only the seq scan warning, got %v", warnings)
+	}
+
+	// Index scans are fine
+	index, err := parseExplain([]byte(sampleExplainIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if warnings := planWarnings(index.Root, false); len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+
+	// Large unfiltered sequential scans
+	big := &PlanNode{NodeType: "Seq Scan", Relation: "events", PlanRows: 250000}
+	if warnings := planWarnings(big, false); len(warnings) != 1 || !strings.Contains(warnings[0], "~250000 rows") {
+		t.Errorf("expected a large scan warning, got %v", warnings)
+	}
+}
+
+func TestLogExplain(t *testing.T) {
+	main, err := parseExplain([]byte(sampleExplainAnalyze))
+	if err != nil {
+		t.Fatal(err)
+	}
+	main.SQL = "SELECT * FROM users WHERE age > 30 ORDER BY name"
+	main.Warnings = planWarnings(main.Root, true)
+
+	var buf bytes.Buffer
+	dc := &DebugContext{Level: DebugExplain, Writer: &buf}
+	dc.LogExplain(&ExplainResult{Entity: "User", Analyzed: true, Main: main})
+
+	out := buf.String()
+	assertContains(t, out, "│ Query Plan")
+	assertContains(t, out, "Sort  (cost=180.12..182.62 rows=1000 width=72) (actual time=3.100..3.200 rows=12 loops=1)")
+	assertContains(t, out, "->  Seq Scan on users  (cost=0.00..130.00 rows=1000 width=72)")
+	assertContains(t, out, "Filter: (age > 30)")
+	assertContains(t, out, "Rows Removed by Filter: 4988")
+	assertContains(t, out, "Buffers: shared hit=55 read=0")
+	assertContains(t, out, "│ Execution: 3.5ms")
+	assertContains(t, out, "⚠ Seq Scan on users")
+
+	// Eager plans are titled by include path
+	eager, err := parseExplain([]byte(sampleExplainIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eager.Path = "orders"
+	buf.Reset()
+	dc.LogExplain(&ExplainResult{Entity: "User", Main: main, Eager: []*QueryPlan{eager}})
+	out = buf.String()
+	assertContains(t, out, "│ Query Plan (include orders)")
+	assertContains(t, out, "Index Scan using orders_user_id_idx on orders o  (cost=0.29..8.31 rows=4 width=48)")
+	assertContains(t, out, "Index Cond: (user_id = ANY ($1))")
+	if strings.Contains(out, "actual time") {
+		t.Error("plain EXPLAIN should not print actual times")
+	}
+
+	// Below DebugExplain nothing is printed
+	buf.Reset()
+	dc.Level = DebugTrace
+	dc.LogExplain(&ExplainResult{Main: main})
+	if buf.Len() != 0 {
+		t.Errorf("expected no output at DebugTrace, got %q", buf.String())
+	}
+}
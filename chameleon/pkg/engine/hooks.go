@@ -0,0 +1,163 @@
+package engine
+
+import (
+	"context"
+	"time"
+)
+
+// ============================================================
+// QUERY HOOKS
+// ============================================================
+
+// Operation is the kind of statement a hook is told about
+type Operation string
+
+const (
+	OpQuery     Operation = "query"     // main query of Execute or Stream
+	OpEager     Operation = "eager"     // eager-loading query of an Include
+	OpAggregate Operation = "aggregate" // Aggregate()
+	OpInsert    Operation = "insert"
+	OpUpdate    Operation = "update"
+	OpDelete    Operation = "delete"
+	OpLink      Operation = "link" // join rows written by Link
+	OpMigration Operation = "migration"
+)
+
+// QueryEvent describes one statement run by the engine. BeforeQuery sees
+// the statement; AfterQuery also sees its duration, row count and error.
+type QueryEvent struct {
+	Operation Operation
+	Entity    string // entity read or written ("" for migrations)
+	Path      string // include path, for OpEager
+	SQL       string
+	Args      []interface{} // after the engine's Redactor, if any
+
+	Start    time.Time
+	Duration time.Duration
+	Rows     int   // rows returned, or affected by mutations
+	Err      error // translated like the error returned to the caller
+}
+
+// QueryHook observes the statements the engine runs, e.g. for tracing or
+// metrics. BeforeQuery may return a derived context (say, carrying a span);
+// the statement runs and AfterQuery is called with it.
+//
+// For Stream, the OpQuery event spans the whole stream, and the eager
+// queries of each chunk are reported in between.
+type QueryHook interface {
+	BeforeQuery(ctx context.Context, event *QueryEvent) context.Context
+	AfterQuery(ctx context.Context, event *QueryEvent)
+}
+
+// Redactor rewrites statement arguments before hooks see them. It gets a
+// copy of the arguments; those bound to the statement are never changed.
+type Redactor func(event *QueryEvent, args []interface{}) []interface{}
+
+// RedactArgs is a Redactor that hides every argument
+func RedactArgs(_ *QueryEvent, args []interface{}) []interface{} {
+	for i := range args {
+		args[i] = "[REDACTED]"
+	}
+	return args
+}
+
+// AddHook registers a hook for every statement the engine runs: queries,
+// eager queries, aggregates, mutations and migrations. Hooks run in the
+// order they were added (AfterQuery in reverse), after the debug context.
+// Register hooks before running queries; AddHook is not synchronized.
+func (e *Engine) AddHook(hook QueryHook) {
+	e.hooks = append(e.hooks, hook)
+}
+
+// SetRedactor sets the function applied to arguments before hooks (and
+// the debug context) see them; nil shows arguments as they are
+func (e *Engine) SetRedactor(redactor Redactor) {
+	e.redactor = redactor
+}
+
+// Hook returns the engine's debug context and registered hooks as a single
+// QueryHook. Mutation implementations report their statements through it
+// with TraceQuery.
+func (e *Engine) Hook() QueryHook {
+	return e.hookChain(e.Debug)
+}
+
+// hookChain returns the hooks with a given debug context (a query's
+// override, or the engine's)
+func (e *Engine) hookChain(debug *DebugContext) QueryHook {
+	return &hookChain{debug: debug, hooks: e.hooks, redactor: e.redactor}
+}
+
+// hookChain runs the debug context, then the registered hooks
+type hookChain struct {
+	debug    *DebugContext
+	hooks    []QueryHook
+	redactor Redactor
+}
+
+func (c *hookChain) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	if c.redactor != nil && len(event.Args) > 0 {
+		args := append([]interface{}(nil), event.Args...)
+		event.Args = c.redactor(event, args)
+	}
+
+	if c.debug != nil {
+		ctx = c.debug.BeforeQuery(ctx, event)
+	}
+	for _, hook := range c.hooks {
+		ctx = hook.BeforeQuery(ctx, event)
+	}
+	return ctx
+}
+
+func (c *hookChain) AfterQuery(ctx context.Context, event *QueryEvent) {
+	for i := len(c.hooks) - 1; i >= 0; i-- {
+		c.hooks[i].AfterQuery(ctx, event)
+	}
+	if c.debug != nil {
+		c.debug.AfterQuery(ctx, event)
+	}
+}
+
+// TraceQuery reports a statement to hook: BeforeQuery is called now, and
+// the returned function records the row count and error and calls
+// AfterQuery. Run the statement with the returned context.
+//
+//	ctx, done := engine.TraceQuery(ctx, hook, &engine.QueryEvent{...})
+//	rows, err := q.Query(ctx, sql, args...)
+//	done(len(rows), err)
+func TraceQuery(ctx context.Context, hook QueryHook, event *QueryEvent) (context.Context, func(rows int, err error)) {
+	if hook == nil {
+		return ctx, func(int, error) {}
+	}
+
+	event.Start = time.Now()
+	ctx = hook.BeforeQuery(ctx, event)
+
+	return ctx, func(rows int, err error) {
+		event.Duration = time.Since(event.Start)
+		event.Rows = rows
+		event.Err = err
+		hook.AfterQuery(ctx, event)
+	}
+}
+
+// hook returns the hooks for this query, honoring its debug override
+func (qb *QueryBuilder) hook() QueryHook {
+	return qb.engine.hookChain(qb.getDebugContext())
+}
+
+// runQuery runs a statement of qb through its hooks. The returned error
+// is translated (see TranslatePgError).
+func (ex *Executor) runQuery(ctx context.Context, qb *QueryBuilder, db rowQuerier, event *QueryEvent, args ...interface{}) ([]Row, StatementProfile, error) {
+	event.Args = args
+	ctx, done := TraceQuery(ctx, qb.hook(), event)
+
+	rows, stats, err := ex.executeProfiled(ctx, db, event.SQL, args...)
+	if err != nil {
+		err = TranslatePgError(qb.engine.Schema(), err)
+	}
+	done(len(rows), err)
+
+	return rows, stats, err
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// orderHook records the order hooks run in
+type orderHook struct {
+	name string
+	log  *[]string
+}
+
+type ctxKey string
+
+func (h orderHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	*h.log = append(*h.log, "before "+h.name)
+	return context.WithValue(ctx, ctxKey(h.name), true)
+}
+
+func (h orderHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	if ctx.Value(ctxKey(h.name)) == nil {
+		*h.log = append(*h.log, "missing context "+h.name)
+	}
+	*h.log = append(*h.log, "after "+h.name)
+}
+
+func TestTraceQuery_Order(t *testing.T) {
+	var log []string
+	eng := NewEngineWithoutSchema()
+	eng.Debug = &DebugContext{Level: DebugNone, Writer: &bytes.Buffer{}}
+	eng.AddHook(orderHook{"a", &log})
+	eng.AddHook(orderHook{"b", &log})
+
+	event := &QueryEvent{Operation: OpQuery, Entity: "User", SQL: "SELECT 1"}
+	ctx, done := TraceQuery(context.Background(), eng.Hook(), event)
+	if ctx.Value(ctxKey("a")) == nil || ctx.Value(ctxKey("b")) == nil {
+		t.Error("statement context should carry values set by BeforeQuery")
+	}
+
+	failure := errors.New("boom")
+	done(7, failure)
+
+	want := "before a,before b,after b,after a"
+	if got := strings.Join(log, ","); got != want {
+		t.Errorf("hook order = %s, want %s", got, want)
+	}
+	if event.Rows != 7 || event.Err != failure || event.Start.IsZero() || event.Duration <= 0 {
+		t.Errorf("event not completed: %+v", event)
+	}
+}
+
+func TestTraceQuery_NilHook(t *testing.T) {
+	ctx := context.Background()
+	got, done := TraceQuery(ctx, nil, &QueryEvent{})
+	if got != ctx {
+		t.Error("nil hook should keep the context")
+	}
+	done(0, nil)
+}
+
+func TestHooks_Redactor(t *testing.T) {
+	eng := NewEngineWithoutSchema()
+	eng.SetRedactor(RedactArgs)
+
+	args := []interface{}{"ana@mail.com", 42}
+	event := &QueryEvent{Operation: OpInsert, Args: args}
+	_, done := TraceQuery(context.Background(), eng.Hook(), event)
+	done(1, nil)
+
+	if event.Args[0] != "[REDACTED]" || event.Args[1] != "[REDACTED]" {
+		t.Errorf("expected redacted args, got %v", event.Args)
+	}
+	if args[0] != "ana@mail.com" || args[1] != 42 {
+		t.Errorf("bound args must not change, got %v", args)
+	}
+}
+
+func TestHooks_DebugContext(t *testing.T) {
+	var buf bytes.Buffer
+	eng := NewEngineWithoutSchema()
+	eng.Debug = &DebugContext{Level: DebugTrace, Writer: &buf}
+
+	_, done := TraceQuery(context.Background(), eng.Hook(), &QueryEvent{
+		Operation: OpEager,
+		Entity:    "Order",
+		Path:      "orders",
+		SQL:       "SELECT * FROM orders WHERE user_id = ANY($1)",
+	})
+	done(3, nil)
+
+	out := buf.String()
+	assertContains(t, out, "[SQL]\nSELECT * FROM orders WHERE user_id = ANY($1)")
+	assertContains(t, out, "│ Query Trace")
+	assertContains(t, out, "│ Rows: 3")
+
+	// A query's own debug level overrides the engine's
+	buf.Reset()
+	eng.Debug.Level = DebugNone
+	qb := eng.Query("User").Debug()
+	_, done = TraceQuery(context.Background(), qb.hook(), &QueryEvent{SQL: "SELECT 1"})
+	done(1, nil)
+	assertContains(t, buf.String(), "[SQL]\nSELECT 1")
+	if strings.Contains(buf.String(), "Query Trace") {
+		t.Error("Debug() should log SQL only")
+	}
+}
@@ -43,3 +43,29 @@ func TestGenerateMigrationNoSchema(t *testing.T) {
 		t.Fatal("Expected error when no schema loaded")
 	}
 }
+
+func TestGenerateMigrationCheckConstraints(t *testing.T) {
+	eng := NewEngine()
+	_, err := eng.LoadSchemaFromString(`
+		entity Product {
+			id: uuid primary,
+			name: string length(1..120),
+			code: string pattern("^[A-Z]{3}$"),
+			price: decimal min(0) max(9999.99) check("price <> 13"),
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	sql, err := eng.GenerateMigration()
+	if err != nil {
+		t.Fatalf("GenerateMigration failed: %v", err)
+	}
+
+	assertContains(t, sql, "CONSTRAINT products_name_length CHECK (char_length(name) BETWEEN 1 AND 120)")
+	assertContains(t, sql, "CONSTRAINT products_code_pattern CHECK (code ~ '^[A-Z]{3}$')")
+	assertContains(t, sql, "CONSTRAINT products_price_min CHECK (price >= 0)")
+	assertContains(t, sql, "CONSTRAINT products_price_max CHECK (price <= 9999.99)")
+	assertContains(t, sql, "CONSTRAINT products_price_check CHECK (price <> 13)")
+}
@@ -3,6 +3,7 @@ package mutation
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
@@ -14,14 +15,16 @@ import (
 //
 
 type InsertBuilder struct {
-	schema *engine.Schema
-	entity string
-	values map[string]interface{}
-	config engine.ValidatorConfig
-
-	debug  bool
-	dryRun bool
-	sql    string
+	schema    *engine.Schema
+	entity    string
+	values    map[string]interface{}
+	config    engine.ValidatorConfig
+	connector engine.Querier
+	hook      engine.QueryHook
+	debug     bool
+	dryRun    bool
+	sql       string
+	args      []interface{}
 }
 
 func NewInsertBuilder(schema *engine.Schema, entity string) *InsertBuilder {
@@ -33,6 +36,18 @@ func NewInsertBuilder(schema *engine.Schema, entity string) *InsertBuilder {
 	}
 }
 
+// WithConnector sets the connector (or transaction querier) used to run the mutation
+func (ib *InsertBuilder) WithConnector(connector engine.Querier) *InsertBuilder {
+	ib.connector = connector
+	return ib
+}
+
+// WithHook sets the hook the statement is reported to (see engine.AddHook)
+func (ib *InsertBuilder) WithHook(hook engine.QueryHook) *InsertBuilder {
+	ib.hook = hook
+	return ib
+}
+
 func (ib *InsertBuilder) Set(field string, value interface{}) *InsertBuilder {
 	ib.values[field] = value
 	return ib
@@ -55,7 +70,7 @@ func (ib *InsertBuilder) Build() (*engine.Mutation, error) {
 		return nil, err
 	}
 
-	ib.sql = ib.generateInsertSQL()
+	ib.sql, ib.args = ib.generateInsertSQL()
 
 	return &engine.Mutation{
 		Type:      engine.MutationInsert,
@@ -64,62 +79,88 @@ func (ib *InsertBuilder) Build() (*engine.Mutation, error) {
 	}, nil
 }
 
-// Exec implements engine.MutationBuilder
-func (ib *InsertBuilder) Exec() error {
+// Exec runs the mutation and discards the result
+func (ib *InsertBuilder) Exec(ctx context.Context) error {
+	_, err := ib.Execute(ctx)
+	return err
+}
+
+// Execute validates the mutation and runs it against the database
+// The inserted row (RETURNING *) is returned in Record
+func (ib *InsertBuilder) Execute(ctx context.Context) (*InsertResult, error) {
 	if ib.sql == "" {
 		if _, err := ib.Build(); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if ib.debug {
-		fmt.Printf("\n[SQL]\n%s\n\n", ib.sql)
+		printSQL(ib.sql, ib.args)
 	}
 
 	if ib.dryRun {
-		return nil
+		return &InsertResult{SQL: ib.sql, DryRun: true}, nil
 	}
 
-	// TODO: ejecutar vía executor / FFI
-	return nil
-}
+	if err := ensureConnected(ib.connector); err != nil {
+		return nil, err
+	}
 
-// Execute mantiene compatibilidad con tu API actual
-func (ib *InsertBuilder) Execute(ctx context.Context) (*InsertResult, error) {
-	if err := ib.Exec(); err != nil {
+	validator := engine.NewValidator(ib.schema, ib.config)
+	if err := validator.ValidateForeignKeys(ctx, ib.connector, ib.entity, ib.values); err != nil {
 		return nil, err
 	}
 
-	return &InsertResult{
-		SQL:      ib.sql,
-		Affected: 1,
-		DryRun:   ib.dryRun,
-	}, nil
+	ctx, done := engine.TraceQuery(ctx, ib.hook, &engine.QueryEvent{
+		Operation: engine.OpInsert,
+		Entity:    ib.entity,
+		SQL:       ib.sql,
+		Args:      ib.args,
+	})
+	rows, err := ib.connector.Query(ctx, ib.sql, ib.args...)
+	if err != nil {
+		err = engine.TranslatePgError(ib.schema, err)
+	}
+	done(len(rows), err)
+	if err != nil {
+		return nil, fmt.Errorf("insert into %s failed: %w", ib.entity, err)
+	}
+
+	result := &InsertResult{SQL: ib.sql}
+	result.Affected = len(rows)
+	if len(rows) > 0 {
+		result.Record = rows[0]
+		result.ID = rows[0][primaryKeyName(ib.schema, ib.entity)]
+	}
+
+	return result, nil
 }
 
-func (ib *InsertBuilder) generateInsertSQL() string {
+func (ib *InsertBuilder) generateInsertSQL() (string, []interface{}) {
 	var fields []string
 	var placeholders []string
-	i := 1
+	var args []interface{}
 
-	for field := range ib.values {
-		fields = append(fields, fmt.Sprintf(`"%s"`, field))
-		placeholders = append(placeholders, fmt.Sprintf("$%d", i))
-		i++
+	for i, field := range sortedKeys(ib.values) {
+		fields = append(fields, engine.QuoteIdent(field))
+		placeholders = append(placeholders, fmt.Sprintf("$%d", i+1))
+		args = append(args, ib.values[field])
 	}
 
-	return fmt.Sprintf(
-		`INSERT INTO "%s" (%s) VALUES (%s) RETURNING *`,
-		ib.entity,
+	sql := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) RETURNING *`,
+		engine.QuoteIdent(engine.EntityToTable(ib.entity)),
 		formatList(fields),
 		formatList(placeholders),
 	)
+	return sql, args
 }
 
+// InsertResult is the engine result plus the SQL that produced it
 type InsertResult struct {
-	SQL      string
-	Affected int
-	DryRun   bool
+	engine.InsertResult
+	SQL    string
+	DryRun bool
 }
 
 //
@@ -129,15 +170,18 @@ type InsertResult struct {
 //
 
 type UpdateBuilder struct {
-	schema   *engine.Schema
-	entity   string
-	filters  map[string]interface{}
-	updates  map[string]interface{}
-	config   engine.ValidatorConfig
-	debug    bool
-	dryRun   bool
-	sql      string
-	forceAll bool
+	schema    *engine.Schema
+	entity    string
+	filters   map[string]interface{}
+	updates   map[string]interface{}
+	config    engine.ValidatorConfig
+	connector engine.Querier
+	hook      engine.QueryHook
+	debug     bool
+	dryRun    bool
+	sql       string
+	args      []interface{}
+	forceAll  bool
 }
 
 func NewUpdateBuilder(schema *engine.Schema, entity string) *UpdateBuilder {
@@ -150,6 +194,18 @@ func NewUpdateBuilder(schema *engine.Schema, entity string) *UpdateBuilder {
 	}
 }
 
+// WithConnector sets the connector (or transaction querier) used to run the mutation
+func (ub *UpdateBuilder) WithConnector(connector engine.Querier) *UpdateBuilder {
+	ub.connector = connector
+	return ub
+}
+
+// WithHook sets the hook the statement is reported to (see engine.AddHook)
+func (ub *UpdateBuilder) WithHook(hook engine.QueryHook) *UpdateBuilder {
+	ub.hook = hook
+	return ub
+}
+
 func (ub *UpdateBuilder) Filter(field string, op string, value interface{}) *UpdateBuilder {
 	key := fmt.Sprintf("%s:%s", field, op)
 	ub.filters[key] = value
@@ -186,7 +242,11 @@ func (ub *UpdateBuilder) Build() (*engine.Mutation, error) {
 		return nil, err
 	}
 
-	ub.sql = ub.generateUpdateSQL()
+	sql, args, err := ub.generateUpdateSQL()
+	if err != nil {
+		return nil, err
+	}
+	ub.sql, ub.args = sql, args
 
 	return &engine.Mutation{
 		Type:      engine.MutationUpdate,
@@ -195,50 +255,92 @@ func (ub *UpdateBuilder) Build() (*engine.Mutation, error) {
 	}, nil
 }
 
-// Exec executes the validated mutation
-func (ub *UpdateBuilder) Exec() error {
+// Exec runs the mutation and discards the result
+func (ub *UpdateBuilder) Exec(ctx context.Context) error {
+	_, err := ub.Execute(ctx)
+	return err
+}
+
+// Execute validates the mutation and runs it against the database
+// Updated rows (RETURNING *) are returned in Records
+func (ub *UpdateBuilder) Execute(ctx context.Context) (*UpdateResult, error) {
 	if ub.sql == "" {
 		if _, err := ub.Build(); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if ub.debug {
-		fmt.Printf("\n[SQL]\n%s\n\n", ub.sql)
+		printSQL(ub.sql, ub.args)
 	}
 
 	if ub.dryRun {
-		return nil
+		return &UpdateResult{SQL: ub.sql, DryRun: true}, nil
 	}
 
-	// TODO: ejecutar vía executor / FFI
-	return nil
+	if err := ensureConnected(ub.connector); err != nil {
+		return nil, err
+	}
+
+	validator := engine.NewValidator(ub.schema, ub.config)
+	if err := validator.ValidateForeignKeys(ctx, ub.connector, ub.entity, ub.updates); err != nil {
+		return nil, err
+	}
+
+	ctx, done := engine.TraceQuery(ctx, ub.hook, &engine.QueryEvent{
+		Operation: engine.OpUpdate,
+		Entity:    ub.entity,
+		SQL:       ub.sql,
+		Args:      ub.args,
+	})
+	rows, err := ub.connector.Query(ctx, ub.sql, ub.args...)
+	if err != nil {
+		err = engine.TranslatePgError(ub.schema, err)
+	}
+	done(len(rows), err)
+	if err != nil {
+		return nil, fmt.Errorf("update %s failed: %w", ub.entity, err)
+	}
+
+	result := &UpdateResult{SQL: ub.sql}
+	result.Records = rows
+	result.Affected = len(rows)
+
+	return result, nil
 }
 
-func (ub *UpdateBuilder) generateUpdateSQL() string {
+func (ub *UpdateBuilder) generateUpdateSQL() (string, []interface{}, error) {
 	var setClauses []string
-	i := 1
+	var args []interface{}
 
-	for field := range ub.updates {
-		setClauses = append(setClauses, fmt.Sprintf(`"%s"=$%d`, field, i))
-		i++
+	for _, field := range sortedKeys(ub.updates) {
+		args = append(args, ub.updates[field])
+		setClauses = append(setClauses, fmt.Sprintf("%s=$%d", engine.QuoteIdent(field), len(args)))
 	}
 
-	var filterClauses []string
-	for field := range ub.filters {
-		fieldName := strings.Split(field, ":")[0]
-		filterClauses = append(filterClauses, fmt.Sprintf(`"%s"=$%d`, fieldName, i))
-		i++
+	filterClauses, args, err := buildWhere(ub.filters, args)
+	if err != nil {
+		return "", nil, err
 	}
 
-	table := strings.ToLower(ub.entity)
-
-	return fmt.Sprintf(
-		`UPDATE "%s" SET %s WHERE %s RETURNING *`,
-		table,
+	sql := fmt.Sprintf(
+		`UPDATE %s SET %s`,
+		engine.QuoteIdent(engine.EntityToTable(ub.entity)),
 		strings.Join(setClauses, ", "),
-		strings.Join(filterClauses, " AND "),
 	)
+	if len(filterClauses) > 0 {
+		sql += " WHERE " + strings.Join(filterClauses, " AND ")
+	}
+	sql += " RETURNING *"
+
+	return sql, args, nil
+}
+
+// UpdateResult is the engine result plus the SQL that produced it
+type UpdateResult struct {
+	engine.UpdateResult
+	SQL    string
+	DryRun bool
 }
 
 //
@@ -252,9 +354,12 @@ type DeleteBuilder struct {
 	entity         string
 	filters        map[string]interface{}
 	config         engine.ValidatorConfig
+	connector      engine.Querier
+	hook           engine.QueryHook
 	debug          bool
 	dryRun         bool
 	sql            string
+	args           []interface{}
 	forceDeleteAll bool
 }
 
@@ -267,6 +372,18 @@ func NewDeleteBuilder(schema *engine.Schema, entity string) *DeleteBuilder {
 	}
 }
 
+// WithConnector sets the connector (or transaction querier) used to run the mutation
+func (db *DeleteBuilder) WithConnector(connector engine.Querier) *DeleteBuilder {
+	db.connector = connector
+	return db
+}
+
+// WithHook sets the hook the statement is reported to (see engine.AddHook)
+func (db *DeleteBuilder) WithHook(hook engine.QueryHook) *DeleteBuilder {
+	db.hook = hook
+	return db
+}
+
 func (db *DeleteBuilder) Filter(field string, op string, value interface{}) *DeleteBuilder {
 	key := fmt.Sprintf("%s:%s", field, op)
 	db.filters[key] = value
@@ -298,7 +415,11 @@ func (db *DeleteBuilder) Build() (*engine.Mutation, error) {
 		return nil, err
 	}
 
-	db.sql = db.generateDeleteSQL()
+	sql, args, err := db.generateDeleteSQL()
+	if err != nil {
+		return nil, err
+	}
+	db.sql, db.args = sql, args
 
 	return &engine.Mutation{
 		Type:      engine.MutationDelete,
@@ -307,43 +428,75 @@ func (db *DeleteBuilder) Build() (*engine.Mutation, error) {
 	}, nil
 }
 
-// Exec executes the validated mutation
-func (db *DeleteBuilder) Exec() error {
+// Exec runs the mutation and discards the result
+func (db *DeleteBuilder) Exec(ctx context.Context) error {
+	_, err := db.Execute(ctx)
+	return err
+}
+
+// Execute validates the mutation and runs it against the database
+func (db *DeleteBuilder) Execute(ctx context.Context) (*DeleteResult, error) {
 	if db.sql == "" {
 		if _, err := db.Build(); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	if db.debug {
-		fmt.Printf("\n[SQL]\n%s\n\n", db.sql)
+		printSQL(db.sql, db.args)
 	}
 
 	if db.dryRun {
-		return nil
+		return &DeleteResult{SQL: db.sql, DryRun: true}, nil
 	}
 
-	// TODO: ejecutar vía executor / FFI
-	return nil
+	if err := ensureConnected(db.connector); err != nil {
+		return nil, err
+	}
+
+	ctx, done := engine.TraceQuery(ctx, db.hook, &engine.QueryEvent{
+		Operation: engine.OpDelete,
+		Entity:    db.entity,
+		SQL:       db.sql,
+		Args:      db.args,
+	})
+	affected, err := db.connector.Exec(ctx, db.sql, db.args...)
+	if err != nil {
+		err = engine.TranslatePgError(db.schema, err)
+	}
+	done(int(affected), err)
+	if err != nil {
+		return nil, fmt.Errorf("delete from %s failed: %w", db.entity, err)
+	}
+
+	result := &DeleteResult{SQL: db.sql}
+	result.Affected = int(affected)
+
+	return result, nil
 }
 
-func (db *DeleteBuilder) generateDeleteSQL() string {
-	var filterClauses []string
-	i := 1
+func (db *DeleteBuilder) generateDeleteSQL() (string, []interface{}, error) {
+	filterClauses, args, err := buildWhere(db.filters, nil)
+	if err != nil {
+		return "", nil, err
+	}
 
-	for field := range db.filters {
-		fieldName := strings.Split(field, ":")[0]
-		filterClauses = append(filterClauses, fmt.Sprintf(`"%s"=$%d`, fieldName, i))
-		i++
+	sql := fmt.Sprintf(
+		`DELETE FROM %s`,
+		engine.QuoteIdent(engine.EntityToTable(db.entity)),
+	)
+	if len(filterClauses) > 0 {
+		sql += " WHERE " + strings.Join(filterClauses, " AND ")
 	}
 
-	table := strings.ToLower(db.entity)
+	return sql, args, nil
+}
 
-	return fmt.Sprintf(
-		`DELETE FROM "%s" WHERE %s`,
-		table,
-		strings.Join(filterClauses, " AND "),
-	)
+// DeleteResult is the engine result plus the SQL that produced it
+type DeleteResult struct {
+	engine.DeleteResult
+	SQL    string
+	DryRun bool
 }
 
 //
@@ -352,6 +505,108 @@ func (db *DeleteBuilder) generateDeleteSQL() string {
 // ============================================================
 //
 
+// buildWhere turns "field:op" filter keys into WHERE clauses
+// Placeholders continue after the args already bound
+func buildWhere(filters map[string]interface{}, args []interface{}) ([]string, []interface{}, error) {
+	var clauses []string
+
+	for _, key := range sortedKeys(filters) {
+		parts := formatStringSplit(key, ":")
+		if len(parts) != 2 {
+			return nil, nil, &engine.ValidationError{
+				Field:    key,
+				Type:     "invalid_filter",
+				Expected: "field:operator",
+				Message:  "Filter key must have a field and an operator",
+			}
+		}
+		field, op := parts[0], parts[1]
+		value := filters[key]
+
+		if op == "like" {
+			value = fmt.Sprintf("%%%v%%", value)
+		}
+		args = append(args, value)
+
+		clause, err := filterClause(field, op, len(args))
+		if err != nil {
+			return nil, nil, err
+		}
+		clauses = append(clauses, clause)
+	}
+
+	return clauses, args, nil
+}
+
+// filterClause renders a single bound comparison
+func filterClause(field string, op string, index int) (string, error) {
+	column := engine.QuoteIdent(field)
+
+	switch op {
+	case "eq":
+		return fmt.Sprintf("%s = $%d", column, index), nil
+	case "neq":
+		return fmt.Sprintf("%s != $%d", column, index), nil
+	case "gt":
+		return fmt.Sprintf("%s > $%d", column, index), nil
+	case "gte":
+		return fmt.Sprintf("%s >= $%d", column, index), nil
+	case "lt":
+		return fmt.Sprintf("%s < $%d", column, index), nil
+	case "lte":
+		return fmt.Sprintf("%s <= $%d", column, index), nil
+	case "like":
+		return fmt.Sprintf("%s LIKE $%d", column, index), nil
+	case "in":
+		return fmt.Sprintf("%s = ANY($%d)", column, index), nil
+	default:
+		return "", &engine.ValidationError{
+			Field:    field,
+			Type:     "invalid_operator",
+			Value:    op,
+			Expected: "eq, neq, gt, gte, lt, lte, like, in",
+			Message:  fmt.Sprintf("Unsupported filter operator '%s'", op),
+		}
+	}
+}
+
+// primaryKeyName returns the primary key field of an entity ("id" if none)
+func primaryKeyName(schema *engine.Schema, entity string) string {
+	if ent := schema.GetEntity(entity); ent != nil {
+		for name, field := range ent.Fields {
+			if field.PrimaryKey {
+				return name
+			}
+		}
+	}
+	return "id"
+}
+
+func ensureConnected(connector engine.Querier) error {
+	if connector == nil {
+		return fmt.Errorf("not connected to database - call engine.Connect() first")
+	}
+	if c, ok := connector.(*engine.Connector); ok && !c.IsConnected() {
+		return fmt.Errorf("not connected to database - call engine.Connect() first")
+	}
+	return nil
+}
+
+func printSQL(sql string, args []interface{}) {
+	fmt.Printf("\n[SQL]\n%s\n[ARGS] %v\n\n", sql, args)
+}
+
+// sortedKeys returns map keys in a stable order so placeholders
+// always line up with their bound arguments
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func formatList(items []string) string {
 	result := ""
 	for i, item := range items {
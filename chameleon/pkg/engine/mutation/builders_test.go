@@ -2,9 +2,13 @@ package mutation
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/chamerr"
 	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // Helper: create test schema
@@ -159,18 +163,36 @@ func TestInsertBuilder_Execute(t *testing.T) {
 	builder := NewInsertBuilder(schema, "User")
 	builder.Set("email", "ana@mail.com").Set("name", "Ana")
 
-	result, err := builder.Execute(context.Background())
+	_, err := builder.Execute(context.Background())
 
-	if err != nil {
-		t.Fatalf("Execute() failed: %v", err)
+	if err == nil {
+		t.Fatal("Execute() should fail without a connector")
 	}
 
-	if result == nil {
-		t.Error("Execute() returned nil result")
+	if builder.sql == "" {
+		t.Error("Execute() should generate SQL before connecting")
+	}
+}
+
+func TestInsertBuilder_BoundArgs(t *testing.T) {
+	schema := testSchema()
+	builder := NewInsertBuilder(schema, "User")
+	builder.Set("name", "Ana").Set("email", "ana@mail.com").Set("age", 28)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() failed: %v", err)
 	}
 
-	if !result.DryRun && result.SQL == "" {
-		t.Error("Execute() should generate SQL")
+	expected := `INSERT INTO "users" ("age", "email", "name") VALUES ($1, $2, $3) RETURNING *`
+	if builder.sql != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, builder.sql)
+	}
+
+	if len(builder.args) != 3 {
+		t.Fatalf("Expected 3 args, got %d", len(builder.args))
+	}
+	if builder.args[0] != 28 || builder.args[1] != "ana@mail.com" || builder.args[2] != "Ana" {
+		t.Errorf("Args not in placeholder order: %v", builder.args)
 	}
 }
 
@@ -273,6 +295,25 @@ func TestUpdateBuilder_Build_NoFilter(t *testing.T) {
 	}
 }
 
+func TestUpdateBuilder_Build_NoSet(t *testing.T) {
+	schema := testSchema()
+	builder := NewUpdateBuilder(schema, "User")
+	builder.Filter("email", "eq", "ana@mail.com")
+
+	_, err := builder.Build()
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.Type != "empty_update" {
+		t.Fatalf("Build() should fail without Set(), got %v", err)
+	}
+	if !errors.Is(err, chamerr.ErrValidation) {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+	if builder.sql != "" {
+		t.Errorf("No SQL should be generated, got %q", builder.sql)
+	}
+}
+
 func TestUpdateBuilder_Build_UpdatePrimaryKey(t *testing.T) {
 	schema := testSchema()
 	builder := NewUpdateBuilder(schema, "User")
@@ -303,9 +344,9 @@ func TestUpdateBuilder_ForceUpdateAll(t *testing.T) {
 func TestUpdateBuilder_Exec(t *testing.T) {
 	schema := testSchema()
 	builder := NewUpdateBuilder(schema, "User")
-	builder.Filter("id", "eq", "uuid-123").Set("name", "Ana")
+	builder.Filter("id", "eq", "uuid-123").Set("name", "Ana").DryRun()
 
-	err := builder.Exec()
+	err := builder.Exec(context.Background())
 
 	if err != nil {
 		t.Fatalf("Exec() failed: %v", err)
@@ -316,6 +357,46 @@ func TestUpdateBuilder_Exec(t *testing.T) {
 	}
 }
 
+func TestUpdateBuilder_BoundArgs(t *testing.T) {
+	schema := testSchema()
+	builder := NewUpdateBuilder(schema, "User")
+	builder.Filter("email", "eq", "ana@mail.com").
+		Filter("age", "gte", 18).
+		Set("name", "Ana").
+		Set("age", 30)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	expected := `UPDATE "users" SET "age"=$1, "name"=$2 WHERE "age" >= $3 AND "email" = $4 RETURNING *`
+	if builder.sql != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, builder.sql)
+	}
+
+	want := []interface{}{30, "Ana", 18, "ana@mail.com"}
+	if len(builder.args) != len(want) {
+		t.Fatalf("Expected %d args, got %d", len(want), len(builder.args))
+	}
+	for i := range want {
+		if builder.args[i] != want[i] {
+			t.Errorf("Arg $%d: expected %v, got %v", i+1, want[i], builder.args[i])
+		}
+	}
+}
+
+func TestUpdateBuilder_Execute_NotConnected(t *testing.T) {
+	schema := testSchema()
+	builder := NewUpdateBuilder(schema, "User")
+	builder.Filter("id", "eq", "uuid-123").Set("name", "Ana")
+
+	_, err := builder.Execute(context.Background())
+
+	if err == nil {
+		t.Fatal("Execute() should fail without a connector")
+	}
+}
+
 // ============================================================
 // DELETE BUILDER TESTS
 // ============================================================
@@ -400,9 +481,9 @@ func TestDeleteBuilder_Build_ForceDeleteAll(t *testing.T) {
 func TestDeleteBuilder_Exec(t *testing.T) {
 	schema := testSchema()
 	builder := NewDeleteBuilder(schema, "User")
-	builder.Filter("id", "eq", "uuid-123")
+	builder.Filter("id", "eq", "uuid-123").DryRun()
 
-	err := builder.Exec()
+	err := builder.Exec(context.Background())
 
 	if err != nil {
 		t.Fatalf("Exec() failed: %v", err)
@@ -413,6 +494,34 @@ func TestDeleteBuilder_Exec(t *testing.T) {
 	}
 }
 
+func TestDeleteBuilder_Operators(t *testing.T) {
+	schema := testSchema()
+	builder := NewDeleteBuilder(schema, "User")
+	builder.Filter("name", "like", "an").Filter("age", "lt", 18)
+
+	if _, err := builder.Build(); err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	expected := `DELETE FROM "users" WHERE "age" < $1 AND "name" LIKE $2`
+	if builder.sql != expected {
+		t.Errorf("Expected SQL:\n%s\nGot:\n%s", expected, builder.sql)
+	}
+	if builder.args[1] != "%an%" {
+		t.Errorf("Expected LIKE arg '%%an%%', got %v", builder.args[1])
+	}
+}
+
+func TestDeleteBuilder_InvalidOperator(t *testing.T) {
+	schema := testSchema()
+	builder := NewDeleteBuilder(schema, "User")
+	builder.Filter("age", "approx", 18)
+
+	if _, err := builder.Build(); err == nil {
+		t.Error("Build() should fail for unsupported operator")
+	}
+}
+
 // ============================================================
 // CHAINING TESTS
 // ============================================================
@@ -447,7 +556,7 @@ func TestUpdateBuilder_Chaining(t *testing.T) {
 		Debug().
 		DryRun()
 
-	err := builder.Exec()
+	err := builder.Exec(context.Background())
 
 	if err != nil {
 		t.Fatalf("Chaining failed: %v", err)
@@ -466,7 +575,7 @@ func TestDeleteBuilder_Chaining(t *testing.T) {
 		Debug().
 		DryRun()
 
-	err := builder.Exec()
+	err := builder.Exec(context.Background())
 
 	if err != nil {
 		t.Fatalf("Chaining failed: %v", err)
@@ -517,3 +626,119 @@ func TestDeleteBuilder_MultipleFilters(t *testing.T) {
 		t.Errorf("Expected 2 filters, got %d", len(builder.filters))
 	}
 }
+
+// lookupQuerier finds no referenced rows and records every statement
+type lookupQuerier struct {
+	sql []string
+}
+
+func (q *lookupQuerier) Query(ctx context.Context, sql string, args ...interface{}) ([]map[string]interface{}, error) {
+	q.sql = append(q.sql, sql)
+	return nil, nil
+}
+
+func (q *lookupQuerier) Exec(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	q.sql = append(q.sql, sql)
+	return 0, nil
+}
+
+func TestInsertBuilder_MissingForeignKey(t *testing.T) {
+	eng := loadEngine(t, `
+		entity User {
+			id: uuid primary,
+			orders: [Order] via user_id,
+		}
+
+		entity Order {
+			id: uuid primary,
+			user_id: uuid,
+			user: User,
+		}
+	`)
+
+	q := &lookupQuerier{}
+	missing := "99999999-9999-9999-9999-999999999999"
+	_, err := NewInsertBuilder(eng.Schema(), "Order").
+		WithConnector(q).
+		Set("user_id", missing).
+		Execute(context.Background())
+
+	var cerr *engine.ConstraintError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("Expected ConstraintError, got %v", err)
+	}
+	if cerr.Type != "foreign_key" || cerr.Field != "user_id" || cerr.Relation != "user" || cerr.Value != missing {
+		t.Errorf("Unexpected error: %+v", cerr)
+	}
+	if len(q.sql) != 1 || strings.Contains(q.sql[0], "INSERT") {
+		t.Errorf("Expected only the lookup to run, got %v", q.sql)
+	}
+
+	// Updates check the keys they set
+	q.sql = nil
+	_, err = NewUpdateBuilder(eng.Schema(), "Order").
+		WithConnector(q).
+		Filter("id", "eq", "11111111-1111-1111-1111-111111111111").
+		Set("user_id", missing).
+		Execute(context.Background())
+	if !errors.As(err, &cerr) {
+		t.Errorf("Expected ConstraintError on update, got %v", err)
+	}
+}
+
+// failingQuerier fails every statement with a Postgres error
+type failingQuerier struct {
+	err error
+}
+
+func (q *failingQuerier) Query(ctx context.Context, sql string, args ...interface{}) ([]map[string]interface{}, error) {
+	return nil, q.err
+}
+
+func (q *failingQuerier) Exec(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	return 0, q.err
+}
+
+func TestInsertBuilder_TranslatesPgError(t *testing.T) {
+	q := &failingQuerier{err: &pgconn.PgError{
+		Code:           "23505",
+		TableName:      "users",
+		ConstraintName: "users_email_key",
+		Detail:         "Key (email)=(ana@mail.com) already exists.",
+	}}
+
+	_, err := NewInsertBuilder(testSchema(), "User").
+		WithConnector(q).
+		Set("id", "11111111-1111-1111-1111-111111111111").
+		Set("email", "ana@mail.com").
+		Set("name", "Ana").
+		Execute(context.Background())
+
+	var unique *engine.UniqueViolationError
+	if !errors.As(err, &unique) {
+		t.Fatalf("Expected UniqueViolationError, got %v", err)
+	}
+	if unique.Entity != "User" || unique.Field != "email" {
+		t.Errorf("Unexpected error: %+v", unique)
+	}
+	if engine.ErrorCode(err) != "UNIQUE_VIOLATION" {
+		t.Errorf("ErrorCode = %s", engine.ErrorCode(err))
+	}
+}
+
+func TestErrors_SharedWithEngine(t *testing.T) {
+	_, err := NewInsertBuilder(testSchema(), "User").
+		Set("id", "11111111-1111-1111-1111-111111111111").
+		Set("email", 42).
+		Set("name", "Ana").
+		Build()
+
+	// The engine validator's errors are the mutation package's errors
+	var mismatch *TypeMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Field != "email" {
+		t.Fatalf("Expected mutation.TypeMismatchError, got %v", err)
+	}
+	if !errors.Is(err, chamerr.ErrValidation) || ErrorCode(err) != "VALIDATION_ERRORS" {
+		t.Errorf("Unexpected classification: %s", ErrorCode(err))
+	}
+}
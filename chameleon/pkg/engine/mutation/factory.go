@@ -1,27 +1,173 @@
 package mutation
 
+import (
+	"context"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+)
+
 // ============================================================
-// MUTATION FACTORY NOW LIVES IN engine/registry.go
+// SQL MUTATION FACTORY
 // ============================================================
 //
 
-/* type Factory struct {
-	schema *engine.Schema
+// Factory creates SQL mutation builders for a single engine
+//
+// The schema is captured when the factory is created (on schema load),
+// the connector is resolved when each builder is created so the engine
+// can connect after loading its schema.
+type Factory struct {
+	engine  *engine.Engine
+	schema  *engine.Schema
+	querier engine.Querier // nil = engine connector
+}
+
+// NewFactory creates a factory bound to the engine's current schema
+func NewFactory(eng *engine.Engine) *Factory {
+	return &Factory{
+		engine: eng,
+		schema: eng.Schema(),
+	}
+}
+
+// WithQuerier implements engine.TxMutationFactory
+// Builders from the returned factory run on q (e.g. a transaction)
+func (f *Factory) WithQuerier(q engine.Querier) engine.MutationFactory {
+	return &Factory{
+		engine:  f.engine,
+		schema:  f.schema,
+		querier: q,
+	}
+}
+
+// NewInsert implements engine.MutationFactory
+func (f *Factory) NewInsert(entity string) engine.InsertMutation {
+	return &insertMutation{
+		builder: NewInsertBuilder(f.schema, entity).
+			WithConnector(f.session()).
+			WithHook(f.engine.Hook()),
+	}
+}
+
+// NewUpdate implements engine.MutationFactory
+func (f *Factory) NewUpdate(entity string) engine.UpdateMutation {
+	return &updateMutation{
+		builder: NewUpdateBuilder(f.schema, entity).
+			WithConnector(f.session()).
+			WithHook(f.engine.Hook()),
+	}
+}
+
+// NewDelete implements engine.MutationFactory
+func (f *Factory) NewDelete(entity string) engine.DeleteMutation {
+	return &deleteMutation{
+		builder: NewDeleteBuilder(f.schema, entity).
+			WithConnector(f.session()).
+			WithHook(f.engine.Hook()),
+	}
+}
+
+// NewLink implements engine.LinkMutationFactory
+func (f *Factory) NewLink(entity string, relation string, id interface{}) engine.LinkMutation {
+	return &linkMutation{
+		builder: NewLinkBuilder(f.schema, entity, relation, id).
+			WithConnector(f.session()).
+			WithHook(f.engine.Hook()),
+	}
+}
+
+func (f *Factory) session() engine.Querier {
+	if f.querier != nil {
+		return f.querier
+	}
+	if c := f.engine.Connector(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// ============================================================
+// ENGINE INTERFACE ADAPTERS
+// ============================================================
+//
+// Builders return concrete types from their chainable methods so Debug()
+// and DryRun() stay reachable; these adapters expose them through the
+// engine.*Mutation interfaces.
+
+type insertMutation struct {
+	builder *InsertBuilder
+}
+
+func (m *insertMutation) Set(field string, value interface{}) engine.InsertMutation {
+	m.builder.Set(field, value)
+	return m
+}
+
+func (m *insertMutation) Execute(ctx context.Context) (*engine.InsertResult, error) {
+	result, err := m.builder.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &result.InsertResult, nil
+}
+
+type updateMutation struct {
+	builder *UpdateBuilder
+}
+
+func (m *updateMutation) Set(field string, value interface{}) engine.UpdateMutation {
+	m.builder.Set(field, value)
+	return m
+}
+
+func (m *updateMutation) Filter(field string, operator string, value interface{}) engine.UpdateMutation {
+	m.builder.Filter(field, operator, value)
+	return m
+}
+
+func (m *updateMutation) Execute(ctx context.Context) (*engine.UpdateResult, error) {
+	result, err := m.builder.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &result.UpdateResult, nil
+}
+
+type deleteMutation struct {
+	builder *DeleteBuilder
+}
+
+func (m *deleteMutation) Filter(field string, operator string, value interface{}) engine.DeleteMutation {
+	m.builder.Filter(field, operator, value)
+	return m
+}
+
+func (m *deleteMutation) Execute(ctx context.Context) (*engine.DeleteResult, error) {
+	result, err := m.builder.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &result.DeleteResult, nil
 }
 
-func NewFactory(schema *engine.Schema) *Factory {
-	return &Factory{schema: schema}
+type linkMutation struct {
+	builder *LinkBuilder
 }
 
-func (f *Factory) Insert(entity string) engine.InsertMutation {
-	return NewInsertBuilder(f.schema, entity)
+func (m *linkMutation) Connect(targetIDs ...interface{}) engine.LinkMutation {
+	m.builder.Connect(targetIDs...)
+	return m
 }
 
-func (f *Factory) Update(entity string) engine.UpdateMutation {
-	return NewUpdateBuilder(f.schema, entity)
+func (m *linkMutation) Disconnect(targetIDs ...interface{}) engine.LinkMutation {
+	m.builder.Disconnect(targetIDs...)
+	return m
 }
 
-func (f *Factory) Delete(entity string) engine.DeleteMutation {
-	return NewDeleteBuilder(f.schema, entity)
+func (m *linkMutation) Execute(ctx context.Context) (*engine.LinkResult, error) {
+	result, err := m.builder.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &result.LinkResult, nil
 }
-*/
@@ -0,0 +1,224 @@
+package mutation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+)
+
+func loadEngine(t *testing.T, source string) *engine.Engine {
+	t.Helper()
+
+	eng := engine.NewEngineWithoutSchema()
+	if _, err := eng.LoadSchemaFromString(source); err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	return eng
+}
+
+func TestFactory_WiredOnSchemaLoad(t *testing.T) {
+	eng := loadEngine(t, `
+		entity User {
+			id: uuid primary,
+			email: string,
+		}
+	`)
+
+	// Would panic if the default factory were not initialized
+	insert := eng.Insert("User").Set("email", "ana@mail.com")
+	if insert == nil {
+		t.Fatal("Insert() returned nil")
+	}
+
+	_, err := insert.Execute(context.Background())
+	if err == nil {
+		t.Fatal("Execute() should fail when engine is not connected")
+	}
+}
+
+func TestFactory_PerEngineSchema(t *testing.T) {
+	users := loadEngine(t, `
+		entity User {
+			id: uuid primary,
+			email: string,
+		}
+	`)
+	products := loadEngine(t, `
+		entity Product {
+			id: uuid primary,
+			sku: string,
+		}
+	`)
+
+	if _, err := users.Delete("Product").Filter("sku", "eq", "A1").Execute(context.Background()); !isUnknownEntity(err) {
+		t.Errorf("User engine should not know Product, got: %v", err)
+	}
+	if _, err := products.Delete("User").Filter("email", "eq", "x").Execute(context.Background()); !isUnknownEntity(err) {
+		t.Errorf("Product engine should not know User, got: %v", err)
+	}
+}
+
+func TestFactory_RebuiltOnReload(t *testing.T) {
+	eng := loadEngine(t, `
+		entity User {
+			id: uuid primary,
+			email: string,
+		}
+	`)
+
+	if _, err := eng.LoadSchemaFromString(`
+		entity Product {
+			id: uuid primary,
+			sku: string,
+		}
+	`); err != nil {
+		t.Fatalf("Failed to reload schema: %v", err)
+	}
+
+	_, err := eng.Update("User").Filter("email", "eq", "x").Set("email", "y").Execute(context.Background())
+	if !isUnknownEntity(err) {
+		t.Errorf("Factory should use the reloaded schema, got: %v", err)
+	}
+}
+
+func TestFactory_CustomFactoryKept(t *testing.T) {
+	eng := loadEngine(t, `
+		entity User {
+			id: uuid primary,
+			email: string,
+		}
+	`)
+
+	custom := NewFactory(eng)
+	eng.SetMutationFactory(custom)
+
+	if _, err := eng.LoadSchemaFromString(`
+		entity Product {
+			id: uuid primary,
+			sku: string,
+		}
+	`); err != nil {
+		t.Fatalf("Failed to reload schema: %v", err)
+	}
+
+	// The custom factory still holds the original schema
+	_, err := eng.Update("User").Filter("email", "eq", "x").Set("email", "y").Execute(context.Background())
+	if isUnknownEntity(err) {
+		t.Error("Custom factory should not be replaced on schema reload")
+	}
+}
+
+func isUnknownEntity(err error) bool {
+	_, ok := err.(*engine.UnknownEntityError)
+	return ok
+}
+
+// recordingQuerier captures SQL instead of running it
+type recordingQuerier struct {
+	sql  []string
+	args [][]interface{}
+}
+
+func (q *recordingQuerier) Query(ctx context.Context, sql string, args ...interface{}) ([]map[string]interface{}, error) {
+	q.sql = append(q.sql, sql)
+	q.args = append(q.args, args)
+	return []map[string]interface{}{{"id": "u1", "email": args[0]}}, nil
+}
+
+func (q *recordingQuerier) Exec(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	q.sql = append(q.sql, sql)
+	q.args = append(q.args, args)
+	return 3, nil
+}
+
+func TestFactory_WithQuerier(t *testing.T) {
+	eng := loadEngine(t, `
+		entity User {
+			id: uuid primary,
+			email: string,
+		}
+	`)
+
+	q := &recordingQuerier{}
+	factory := NewFactory(eng).WithQuerier(q)
+
+	inserted, err := factory.NewInsert("User").Set("email", "ana@mail.com").Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Insert on querier failed: %v", err)
+	}
+	if inserted.ID != "u1" {
+		t.Errorf("Expected ID from querier rows, got %v", inserted.ID)
+	}
+
+	deleted, err := factory.NewDelete("User").Filter("email", "eq", "ana@mail.com").Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Delete on querier failed: %v", err)
+	}
+	if deleted.Affected != 3 {
+		t.Errorf("Expected affected rows from querier, got %d", deleted.Affected)
+	}
+
+	if len(q.sql) != 2 {
+		t.Fatalf("Expected 2 statements on querier, got %d", len(q.sql))
+	}
+}
+
+// eventHook records the events it is told about
+type eventHook struct {
+	before []engine.QueryEvent
+	after  []engine.QueryEvent
+}
+
+func (h *eventHook) BeforeQuery(ctx context.Context, event *engine.QueryEvent) context.Context {
+	h.before = append(h.before, *event)
+	return ctx
+}
+
+func (h *eventHook) AfterQuery(ctx context.Context, event *engine.QueryEvent) {
+	h.after = append(h.after, *event)
+}
+
+func TestFactory_ReportsToHooks(t *testing.T) {
+	eng := loadEngine(t, `
+		entity User {
+			id: uuid primary,
+			email: string,
+		}
+	`)
+	hook := &eventHook{}
+	eng.AddHook(hook)
+	eng.SetRedactor(engine.RedactArgs)
+
+	q := &recordingQuerier{}
+	factory := NewFactory(eng).WithQuerier(q)
+
+	if _, err := factory.NewInsert("User").Set("email", "ana@mail.com").Execute(context.Background()); err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+	if _, err := factory.NewDelete("User").Filter("email", "eq", "ana@mail.com").Execute(context.Background()); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if len(hook.before) != 2 || len(hook.after) != 2 {
+		t.Fatalf("Expected 2 before and 2 after events, got %d/%d", len(hook.before), len(hook.after))
+	}
+
+	insert := hook.after[0]
+	if insert.Operation != engine.OpInsert || insert.Entity != "User" || insert.Rows != 1 || insert.Err != nil {
+		t.Errorf("Unexpected insert event: %+v", insert)
+	}
+	if insert.SQL != q.sql[0] {
+		t.Errorf("Expected event SQL %q, got %q", q.sql[0], insert.SQL)
+	}
+	if insert.Args[0] != "[REDACTED]" {
+		t.Errorf("Expected redacted args, got %v", insert.Args)
+	}
+	if q.args[0][0] != "ana@mail.com" {
+		t.Errorf("Redaction must not change bound args, got %v", q.args[0])
+	}
+
+	if del := hook.after[1]; del.Operation != engine.OpDelete || del.Rows != 3 {
+		t.Errorf("Unexpected delete event: %+v", del)
+	}
+}
@@ -71,6 +71,10 @@ func GenerateMutationSQL(
 
 // SetSchemaCache sets the schema cache in FFI for batch operations
 // Call this once before batch mutations, then pass nil for schema_json in GenerateMutationSQL
+//
+// Deprecated: the cache is global to the process, so engines with different
+// schemas overwrite each other's. Each engine holds its own schema in the
+// core instead.
 func SetSchemaCache(schemaJSON string) error {
 	resultJSON := ffi.SetSchemaCache(schemaJSON)
 
@@ -92,6 +96,8 @@ func SetSchemaCache(schemaJSON string) error {
 
 // ClearSchemaCache clears the schema cache in FFI
 // Call this after batch operations to free memory
+//
+// Deprecated: see SetSchemaCache.
 func ClearSchemaCache() error {
 	resultJSON := ffi.ClearSchemaCache()
 
@@ -0,0 +1,204 @@
+package mutation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+)
+
+// ============================================================
+// LINK BUILDER (ManyToMany connect / disconnect)
+// ============================================================
+//
+// Links are rows of the relation's join entity:
+//
+//	Post.tags: [Tag] through PostTag
+//
+//	eng.Link("Post", "tags", postID).Connect(tagID).Execute(ctx)
+//	→ INSERT INTO "post_tags" ("post_id", "tag_id") VALUES ($1, $2) ON CONFLICT DO NOTHING
+
+type LinkBuilder struct {
+	schema     *engine.Schema
+	entity     string
+	relation   string
+	id         interface{}
+	connect    []interface{}
+	disconnect []interface{}
+	config     engine.ValidatorConfig
+	connector  engine.Querier
+	hook       engine.QueryHook
+	debug      bool
+	dryRun     bool
+	statements []linkStatement
+	built      bool
+}
+
+type linkStatement struct {
+	connect bool
+	sql     string
+	args    []interface{}
+}
+
+func NewLinkBuilder(schema *engine.Schema, entity string, relation string, id interface{}) *LinkBuilder {
+	return &LinkBuilder{
+		schema:   schema,
+		entity:   entity,
+		relation: relation,
+		id:       id,
+		config:   engine.DefaultValidatorConfig(),
+	}
+}
+
+// WithConnector sets the connector (or transaction querier) used to run the mutation
+func (lb *LinkBuilder) WithConnector(connector engine.Querier) *LinkBuilder {
+	lb.connector = connector
+	return lb
+}
+
+// WithHook sets the hook the statements are reported to (see engine.AddHook)
+func (lb *LinkBuilder) WithHook(hook engine.QueryHook) *LinkBuilder {
+	lb.hook = hook
+	return lb
+}
+
+func (lb *LinkBuilder) Connect(targetIDs ...interface{}) *LinkBuilder {
+	lb.connect = append(lb.connect, targetIDs...)
+	return lb
+}
+
+func (lb *LinkBuilder) Disconnect(targetIDs ...interface{}) *LinkBuilder {
+	lb.disconnect = append(lb.disconnect, targetIDs...)
+	return lb
+}
+
+func (lb *LinkBuilder) Debug() *LinkBuilder {
+	lb.debug = true
+	return lb
+}
+
+func (lb *LinkBuilder) DryRun() *LinkBuilder {
+	lb.dryRun = true
+	return lb
+}
+
+// Build validates the relation and generates the join table statements
+func (lb *LinkBuilder) Build() error {
+	targets := append(append([]interface{}{}, lb.connect...), lb.disconnect...)
+
+	validator := engine.NewValidator(lb.schema, lb.config)
+	id, err := validator.ValidateLinkInput(lb.entity, lb.relation, lb.id, targets)
+	if err != nil {
+		return err
+	}
+
+	// Bind the coerced keys (e.g. "5" for an int key)
+	lb.id = id
+	lb.connect = targets[:len(lb.connect):len(lb.connect)]
+	lb.disconnect = targets[len(lb.connect):]
+
+	ent := lb.schema.GetEntity(lb.entity)
+	rel := ent.Relations[lb.relation]
+	sourceKey, targetKey, _ := lb.schema.ThroughKeys(ent, rel)
+
+	table := engine.QuoteIdent(engine.EntityToTable(*rel.Through))
+	source := engine.QuoteIdent(sourceKey)
+	target := engine.QuoteIdent(targetKey)
+
+	lb.statements = nil
+
+	if len(lb.disconnect) > 0 {
+		lb.statements = append(lb.statements, linkStatement{
+			sql: fmt.Sprintf(
+				"DELETE FROM %s WHERE %s = $1 AND %s = ANY($2)",
+				table, source, target,
+			),
+			args: []interface{}{lb.id, lb.disconnect},
+		})
+	}
+
+	if len(lb.connect) > 0 {
+		rows := make([]string, len(lb.connect))
+		args := []interface{}{lb.id}
+		for i, id := range lb.connect {
+			args = append(args, id)
+			rows[i] = fmt.Sprintf("($1, $%d)", len(args))
+		}
+
+		lb.statements = append(lb.statements, linkStatement{
+			connect: true,
+			sql: fmt.Sprintf(
+				"INSERT INTO %s (%s, %s) VALUES %s ON CONFLICT DO NOTHING",
+				table, source, target, strings.Join(rows, ", "),
+			),
+			args: args,
+		})
+	}
+
+	lb.built = true
+	return nil
+}
+
+// Exec runs the mutation and discards the result
+func (lb *LinkBuilder) Exec(ctx context.Context) error {
+	_, err := lb.Execute(ctx)
+	return err
+}
+
+// Execute validates the mutation and runs it against the database
+// Disconnects run before connects; use engine.Tx to make both atomic
+func (lb *LinkBuilder) Execute(ctx context.Context) (*LinkResult, error) {
+	if !lb.built {
+		if err := lb.Build(); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &LinkResult{DryRun: lb.dryRun}
+	for _, stmt := range lb.statements {
+		result.SQL = append(result.SQL, stmt.sql)
+		if lb.debug {
+			printSQL(stmt.sql, stmt.args)
+		}
+	}
+
+	if lb.dryRun || len(lb.statements) == 0 {
+		return result, nil
+	}
+
+	if err := ensureConnected(lb.connector); err != nil {
+		return nil, err
+	}
+
+	for _, stmt := range lb.statements {
+		stmtCtx, done := engine.TraceQuery(ctx, lb.hook, &engine.QueryEvent{
+			Operation: engine.OpLink,
+			Entity:    lb.entity,
+			Path:      lb.relation,
+			SQL:       stmt.sql,
+			Args:      stmt.args,
+		})
+		affected, err := lb.connector.Exec(stmtCtx, stmt.sql, stmt.args...)
+		if err != nil {
+			err = engine.TranslatePgError(lb.schema, err)
+		}
+		done(int(affected), err)
+		if err != nil {
+			return nil, fmt.Errorf("link %s.%s failed: %w", lb.entity, lb.relation, err)
+		}
+		if stmt.connect {
+			result.Connected = int(affected)
+		} else {
+			result.Disconnected = int(affected)
+		}
+	}
+
+	return result, nil
+}
+
+type LinkResult struct {
+	engine.LinkResult
+	SQL    []string
+	DryRun bool
+}
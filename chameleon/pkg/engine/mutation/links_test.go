@@ -0,0 +1,201 @@
+package mutation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+)
+
+const (
+	testPostID = "10000000-0000-0000-0000-000000000001"
+	testTagGo  = "20000000-0000-0000-0000-000000000001"
+	testTagDB  = "20000000-0000-0000-0000-000000000002"
+)
+
+func loadLinkEngine(t *testing.T) *engine.Engine {
+	t.Helper()
+
+	return loadEngine(t, `
+		entity Post {
+			id: uuid primary,
+			title: string,
+			author_id: uuid,
+			author: Author,
+			tags: [Tag] through PostTag,
+		}
+
+		entity Author {
+			id: uuid primary,
+			posts: [Post] via author_id,
+		}
+
+		entity Tag {
+			id: uuid primary,
+			name: string,
+		}
+
+		entity PostTag {
+			id: uuid primary,
+			post_id: uuid,
+			tag_id: uuid,
+			post: Post,
+			tag: Tag,
+		}
+	`)
+}
+
+func TestLinkBuilder_SQL(t *testing.T) {
+	eng := loadLinkEngine(t)
+
+	lb := NewLinkBuilder(eng.Schema(), "Post", "tags", testPostID).
+		Disconnect(testTagDB).
+		Connect(testTagGo, testTagDB).
+		DryRun()
+
+	result, err := lb.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	if len(result.SQL) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(result.SQL))
+	}
+
+	// Disconnects run first
+	expectedDelete := `DELETE FROM "post_tags" WHERE "post_id" = $1 AND "tag_id" = ANY($2)`
+	if result.SQL[0] != expectedDelete {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expectedDelete, result.SQL[0])
+	}
+
+	expectedInsert := `INSERT INTO "post_tags" ("post_id", "tag_id") VALUES ($1, $2), ($1, $3) ON CONFLICT DO NOTHING`
+	if result.SQL[1] != expectedInsert {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expectedInsert, result.SQL[1])
+	}
+
+	args := lb.statements[1].args
+	if len(args) != 3 || args[0] != testPostID || args[2] != testTagDB {
+		t.Errorf("Unexpected connect args: %v", args)
+	}
+}
+
+func TestLinkBuilder_RejectsNonManyToMany(t *testing.T) {
+	eng := loadLinkEngine(t)
+
+	_, err := NewLinkBuilder(eng.Schema(), "Post", "author", testPostID).
+		Connect(testTagGo).
+		DryRun().
+		Execute(context.Background())
+
+	var verr *engine.ValidationError
+	if !errors.As(err, &verr) || verr.Type != "invalid_relation" {
+		t.Errorf("Expected invalid_relation error, got %v", err)
+	}
+}
+
+func TestLinkBuilder_UnknownRelation(t *testing.T) {
+	eng := loadLinkEngine(t)
+
+	_, err := NewLinkBuilder(eng.Schema(), "Post", "labels", testPostID).
+		Connect(testTagGo).
+		DryRun().
+		Execute(context.Background())
+
+	var rerr *engine.UnknownRelationError
+	if !errors.As(err, &rerr) {
+		t.Fatalf("Expected UnknownRelationError, got %v", err)
+	}
+	if rerr.Code() != "UNKNOWN_RELATION" {
+		t.Errorf("Unexpected code: %s", rerr.Code())
+	}
+}
+
+func TestLinkBuilder_InvalidTargetID(t *testing.T) {
+	eng := loadLinkEngine(t)
+
+	_, err := NewLinkBuilder(eng.Schema(), "Post", "tags", testPostID).
+		Connect("not-a-uuid").
+		DryRun().
+		Execute(context.Background())
+
+	var ferr *engine.FieldFormatError
+	if !errors.As(err, &ferr) {
+		t.Errorf("Expected FieldFormatError, got %v", err)
+	}
+}
+
+func TestLinkBuilder_BindsCoercedKeys(t *testing.T) {
+	eng := loadLinkEngine(t)
+
+	// Pointers are dereferenced by the validator
+	postID, tagID := testPostID, testTagGo
+	lb := NewLinkBuilder(eng.Schema(), "Post", "tags", &postID).
+		Connect(&tagID).
+		Disconnect(testTagDB).
+		DryRun()
+	if _, err := lb.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	want := [][]interface{}{
+		{testPostID, []interface{}{testTagDB}},
+		{testPostID, testTagGo},
+	}
+	for i, stmt := range lb.statements {
+		if !reflect.DeepEqual(stmt.args, want[i]) {
+			t.Errorf("statement %d: args %#v, want %#v", i, stmt.args, want[i])
+		}
+	}
+
+	// Numeric strings for int keys when types are not strict
+	ints := loadEngine(t, `
+		entity Course {
+			id: int primary,
+			students: [Student] through Enrollment,
+		}
+
+		entity Student {
+			id: int primary,
+		}
+
+		entity Enrollment {
+			id: uuid primary,
+			course_id: int,
+			student_id: int,
+			course: Course,
+			student: Student,
+		}
+	`)
+	lb = NewLinkBuilder(ints.Schema(), "Course", "students", "5").Connect("7", 8).DryRun()
+	lb.config.StrictTypes = false
+	if _, err := lb.Execute(context.Background()); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if args := lb.statements[0].args; !reflect.DeepEqual(args, []interface{}{int64(5), int64(7), 8}) {
+		t.Errorf("args %#v, want the coerced keys", args)
+	}
+}
+
+func TestFactory_NewLink(t *testing.T) {
+	eng := loadLinkEngine(t)
+
+	q := &recordingQuerier{}
+	factory := NewFactory(eng).WithQuerier(q).(*Factory)
+
+	result, err := factory.NewLink("Post", "tags", testPostID).
+		Connect(testTagGo).
+		Disconnect(testTagDB).
+		Execute(context.Background())
+	if err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+
+	if len(q.sql) != 2 {
+		t.Fatalf("Expected 2 statements on querier, got %d", len(q.sql))
+	}
+	if result.Connected != 3 || result.Disconnected != 3 {
+		t.Errorf("Expected affected counts from querier, got %+v", result)
+	}
+}
@@ -1,6 +1,12 @@
 package mutation
 
-/* func Register(engine *engine.Engine) {
-	engine.SetMutationFactory(NewFactory(engine.Schema()))
+import "github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+
+// Importing this package makes the SQL factory the default for every engine:
+//
+//	import _ "github.com/chameleon-db/chameleondb/chameleon/pkg/engine/mutation"
+func init() {
+	engine.RegisterMutationFactory(func(eng *engine.Engine) engine.MutationFactory {
+		return NewFactory(eng)
+	})
 }
-*/
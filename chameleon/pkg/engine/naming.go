@@ -0,0 +1,39 @@
+package engine
+
+import (
+	"strings"
+	"unicode"
+)
+
+// EntityToTable converts a PascalCase entity name to the snake_case plural
+// table name used by the Rust SQL and migration generators
+//
+//	User      → users
+//	OrderItem → order_items
+func EntityToTable(entityName string) string {
+	return pascalToSnake(entityName) + "s"
+}
+
+// pascalToSnake converts PascalCase to snake_case ("OrderItem" → "order_item")
+func pascalToSnake(entityName string) string {
+	var b strings.Builder
+	runes := []rune(entityName)
+
+	for i, ch := range runes {
+		if unicode.IsUpper(ch) && i > 0 {
+			// Don't add underscore if previous char was also uppercase
+			// (mirrors pascal_to_snake in chameleon-core/src/sql/naming.rs)
+			if unicode.IsLower(runes[i-1]) {
+				b.WriteRune('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(ch))
+	}
+
+	return b.String()
+}
+
+// QuoteIdent quotes a SQL identifier for PostgreSQL
+func QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
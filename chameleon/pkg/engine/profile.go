@@ -0,0 +1,49 @@
+package engine
+
+import "time"
+
+// ============================================================
+// QUERY PROFILE
+// ============================================================
+
+// QueryProfile breaks down where the time of a query went
+type QueryProfile struct {
+	Marshal  time.Duration // serializing the query (and the schema on first use), parsing the generated SQL
+	Generate time.Duration // Rust SQL generator (FFI)
+	Cached   bool          // SQL came from the compiled query cache
+	Main     StatementProfile
+	Eager    []StatementProfile // in execution order
+	Total    time.Duration      // the whole Execute call
+}
+
+// StatementProfile is the time spent on one SQL statement
+type StatementProfile struct {
+	Path     string        // include path; "" for the main query
+	Database time.Duration // sending the statement and reading results
+	Scan     time.Duration // converting results into Rows
+	Rows     int
+}
+
+// Database returns the time spent in the database over all statements
+func (p *QueryProfile) Database() time.Duration {
+	total := p.Main.Database
+	for _, eager := range p.Eager {
+		total += eager.Database
+	}
+	return total
+}
+
+// Scan returns the time spent converting rows over all statements
+func (p *QueryProfile) Scan() time.Duration {
+	total := p.Main.Scan
+	for _, eager := range p.Eager {
+		total += eager.Scan
+	}
+	return total
+}
+
+// Other returns the time not accounted for by any phase
+// (validation, stitching includes, hooks)
+func (p *QueryProfile) Other() time.Duration {
+	return p.Total - p.Marshal - p.Generate - p.Database() - p.Scan()
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateSQL_Profile(t *testing.T) {
+	e := setupTestEngine(t)
+
+	profile := &QueryProfile{}
+	generated, err := e.Query("User").Include("orders").generateSQL(profile)
+	if err != nil {
+		t.Fatalf("generateSQL failed: %v", err)
+	}
+	if generated.MainQuery == "" {
+		t.Fatal("expected a main query")
+	}
+	if profile.Marshal <= 0 || profile.Generate <= 0 {
+		t.Errorf("expected marshal and generate timings, got %+v", profile)
+	}
+
+	// ToSQL doesn't profile
+	if _, err := e.Query("User").ToSQL(); err != nil {
+		t.Fatalf("ToSQL failed: %v", err)
+	}
+}
+
+func TestQueryProfile_Totals(t *testing.T) {
+	p := &QueryProfile{
+		Marshal:  1 * time.Millisecond,
+		Generate: 2 * time.Millisecond,
+		Main:     StatementProfile{Database: 5 * time.Millisecond, Scan: 1 * time.Millisecond, Rows: 3},
+		Eager: []StatementProfile{
+			{Path: "orders", Database: 3 * time.Millisecond, Scan: 2 * time.Millisecond, Rows: 7},
+		},
+		Total: 15 * time.Millisecond,
+	}
+
+	if got := p.Database(); got != 8*time.Millisecond {
+		t.Errorf("Database() = %v", got)
+	}
+	if got := p.Scan(); got != 3*time.Millisecond {
+		t.Errorf("Scan() = %v", got)
+	}
+	if got := p.Other(); got != 1*time.Millisecond {
+		t.Errorf("Other() = %v", got)
+	}
+}
+
+func TestLogProfile(t *testing.T) {
+	p := &QueryProfile{
+		Marshal:  100 * time.Microsecond,
+		Generate: 300 * time.Microsecond,
+		Main:     StatementProfile{Database: 2 * time.Millisecond, Scan: 50 * time.Microsecond, Rows: 3},
+		Eager: []StatementProfile{
+			{Path: "orders", Database: time.Millisecond, Scan: 20 * time.Microsecond, Rows: 7},
+		},
+		Total: 4 * time.Millisecond,
+	}
+
+	var buf bytes.Buffer
+	dc := &DebugContext{Level: DebugTrace, Writer: &buf}
+	dc.LogProfile("User", p)
+
+	out := buf.String()
+	assertContains(t, out, "│ Query Profile (User)")
+	assertContains(t, out, "│ Generate:  300µs (FFI)")
+	assertContains(t, out, "│ main:      db 2ms, scan 50µs (3 rows)")
+	assertContains(t, out, "│ orders:    db 1ms, scan 20µs (7 rows)")
+	assertContains(t, out, "│ Total:     4ms")
+
+	// EnableTiming alone prints one line
+	buf.Reset()
+	dc = &DebugContext{Level: DebugNone, Writer: &buf, EnableTiming: true}
+	dc.LogProfile("User", p)
+	if got := buf.String(); got != "[TIMING] User: 4ms (generate 400µs, db 3ms, scan 70µs)\n" {
+		t.Errorf("timing line = %q", got)
+	}
+
+	// EnableProfiling prints the breakdown below DebugTrace
+	buf.Reset()
+	dc = &DebugContext{Level: DebugSQL, Writer: &buf, EnableProfiling: true}
+	dc.LogProfile("User", p)
+	assertContains(t, buf.String(), "│ Query Profile (User)")
+
+	// Nothing by default
+	buf.Reset()
+	dc = &DebugContext{Level: DebugSQL, Writer: &buf}
+	dc.LogProfile("User", p)
+	if strings.TrimSpace(buf.String()) != "" {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
@@ -4,9 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
-
-	"github.com/chameleon-db/chameleondb/chameleon/internal/ffi"
 )
 
 // --- Query types (mirror Rust Query AST) ---
@@ -23,6 +22,8 @@ type FilterCondition struct {
 	Field FieldPath   `json:"field"`
 	Op    string      `json:"op"` // "Eq", "Neq", "Gt", etc.
 	Value FilterValue `json:"value"`
+
+	raw interface{} // value as given to Cond, bound as a parameter
 }
 
 type FieldPath struct {
@@ -32,6 +33,12 @@ type FieldPath struct {
 type FilterExpr struct {
 	Condition *FilterCondition `json:"Condition,omitempty"`
 	Binary    *BinaryExpr      `json:"Binary,omitempty"`
+	Not       *FilterExpr      `json:"Not,omitempty"`
+}
+
+// IsEmpty reports whether the expression holds no condition
+func (f FilterExpr) IsEmpty() bool {
+	return f.Condition == nil && f.Binary == nil && f.Not == nil
 }
 
 type BinaryExpr struct {
@@ -41,7 +48,8 @@ type BinaryExpr struct {
 }
 
 type IncludePath struct {
-	Path []string `json:"path"`
+	Path   []string `json:"path"`
+	Fields []string `json:"fields,omitempty"` // empty = all fields
 }
 
 type OrderByClause struct {
@@ -52,17 +60,42 @@ type OrderByClause struct {
 // QueryJSON is the serialization format matching Rust's Query
 type QueryJSON struct {
 	Entity   string          `json:"entity"`
+	Select   []string        `json:"select,omitempty"`
 	Filters  []FilterExpr    `json:"filters"`
 	Includes []IncludePath   `json:"includes"`
 	OrderBy  []OrderByClause `json:"order_by"`
-	Limit    *uint64         `json:"limit"`
-	Offset   *uint64         `json:"offset"`
+	Limit    *RowCount       `json:"limit"`
+	Offset   *RowCount       `json:"offset"`
+
+	// Aggregation (see aggregate.go)
+	Aggregates []Aggregate  `json:"aggregates,omitempty"`
+	GroupBy    []FieldPath  `json:"group_by,omitempty"`
+	Having     []FilterExpr `json:"having,omitempty"`
+}
+
+// RowCount is a LIMIT or OFFSET: a count, or once the query is
+// parameterized, the parameter carrying it ({"Param": n})
+type RowCount struct {
+	Count uint64
+	Param int // 0 = literal Count
+}
+
+// MarshalJSON writes the count as a number, a parameter as {"Param": n}
+func (c RowCount) MarshalJSON() ([]byte, error) {
+	if c.Param > 0 {
+		return json.Marshal(FilterValue{"Param": c.Param})
+	}
+	return json.Marshal(c.Count)
 }
 
 // GeneratedSQL mirrors Rust's GeneratedSQL
 type GeneratedSQL struct {
 	MainQuery    string     `json:"main_query"`
 	EagerQueries [][]string `json:"eager_queries"`
+
+	// Values bound to MainQuery's $1..$n. Empty for ToSQL, which
+	// inlines them for display.
+	Args []interface{} `json:"-"`
 }
 
 type EagerQuery struct {
@@ -78,6 +111,12 @@ type QueryBuilder struct {
 	query      QueryJSON
 	entityName string
 
+	// Transaction to run on (nil = pooled connection)
+	tx *Tx
+
+	// Rows per include batch in Stream (0 = defaultChunkSize)
+	chunkSize int
+
 	// Debug override (optional)
 	debugLevel *DebugLevel // nil = use engine default
 }
@@ -100,23 +139,92 @@ func (e *Engine) Query(entity string) *QueryBuilder {
 // op: "eq", "neq", "gt", "gte", "lt", "lte", "like"
 // value: string, int, float, or bool
 func (qb *QueryBuilder) Filter(field string, op string, value interface{}) *QueryBuilder {
-	rustOp := goOpToRust(op)
+	return qb.Where(Cond(field, op, value))
+}
+
+// Where adds a composed filter expression
+// Top-level expressions are combined with AND, like Filter:
+//
+//	qb.Where(engine.Or(
+//		engine.Cond("status", "eq", "paid"),
+//		engine.And(engine.Cond("status", "eq", "pending"), engine.Cond("total", "gt", 100)),
+//	))
+//
+// Relation conditions inside Or/Not are checked with EXISTS, so rows
+// without related records are not dropped.
+func (qb *QueryBuilder) Where(expr FilterExpr) *QueryBuilder {
+	if expr.IsEmpty() {
+		return qb
+	}
+	qb.query.Filters = append(qb.query.Filters, expr)
+	return qb
+}
 
-	qb.query.Filters = append(qb.query.Filters, FilterExpr{
+// --- Filter composition ---
+
+// Cond builds a single condition, with the same arguments as Filter
+func Cond(field string, op string, value interface{}) FilterExpr {
+	return FilterExpr{
 		Condition: &FilterCondition{
 			Field: parseFieldPath(field),
-			Op:    rustOp,
+			Op:    goOpToRust(op),
 			Value: goValueToFilter(value),
+			raw:   value,
 		},
-	})
+	}
+}
+
+// And combines expressions so that all of them must hold
+func And(exprs ...FilterExpr) FilterExpr {
+	return combine("And", exprs)
+}
+
+// Or combines expressions so that at least one of them must hold
+func Or(exprs ...FilterExpr) FilterExpr {
+	return combine("Or", exprs)
+}
+
+// Not negates an expression
+func Not(expr FilterExpr) FilterExpr {
+	if expr.IsEmpty() {
+		return expr
+	}
+	return FilterExpr{Not: &expr}
+}
+
+// combine left-folds expressions into nested binary nodes,
+// skipping empty ones: (a OP b) OP c
+func combine(op string, exprs []FilterExpr) FilterExpr {
+	var result FilterExpr
+	for _, expr := range exprs {
+		if expr.IsEmpty() {
+			continue
+		}
+		if result.IsEmpty() {
+			result = expr
+			continue
+		}
+		result = FilterExpr{Binary: &BinaryExpr{Left: result, Op: op, Right: expr}}
+	}
+	return result
+}
+
+// Select loads only the given fields instead of every column
+// Keys needed by Include are still loaded behind the scenes.
+func (qb *QueryBuilder) Select(fields ...string) *QueryBuilder {
+	qb.query.Select = append(qb.query.Select, fields...)
 	return qb
 }
 
 // Include adds eager loading for a relation
 // Supports nested paths: "orders", "orders.items"
-func (qb *QueryBuilder) Include(path string) *QueryBuilder {
+// Optional fields restrict the columns loaded for the relation:
+//
+//	qb.Include("orders", "id", "total")
+func (qb *QueryBuilder) Include(path string, fields ...string) *QueryBuilder {
 	qb.query.Includes = append(qb.query.Includes, IncludePath{
-		Path: splitPath(path),
+		Path:   splitPath(path),
+		Fields: fields,
 	})
 	return qb
 }
@@ -137,82 +245,163 @@ func (qb *QueryBuilder) OrderBy(field string, direction string) *QueryBuilder {
 
 // Limit sets the maximum number of results
 func (qb *QueryBuilder) Limit(n uint64) *QueryBuilder {
-	qb.query.Limit = &n
+	qb.query.Limit = &RowCount{Count: n}
 	return qb
 }
 
 // Offset sets the number of results to skip
 func (qb *QueryBuilder) Offset(n uint64) *QueryBuilder {
-	qb.query.Offset = &n
+	qb.query.Offset = &RowCount{Count: n}
 	return qb
 }
 
 // ToSQL generates SQL without executing
 // Useful for debugging and testing
 func (qb *QueryBuilder) ToSQL() (*GeneratedSQL, error) {
-	if qb.engine.schema == nil {
+	return qb.generateSQL(nil)
+}
+
+// generateSQL is ToSQL, timing serialization and FFI generation into
+// profile when it is not nil
+func (qb *QueryBuilder) generateSQL(profile *QueryProfile) (*GeneratedSQL, error) {
+	schema := qb.engine.Schema()
+	if schema == nil {
 		return nil, fmt.Errorf("no schema loaded")
 	}
 
+	if err := qb.validateProjection(schema); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
 	// Serialize query
-	queryJSON, err := json.Marshal(qb.query)
+	enc := qb.engine.Encoding()
+	payload, release, err := encodeQuery(&qb.query, enc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to serialize query: %w", err)
 	}
+	defer release()
+
+	if profile != nil {
+		profile.Marshal += time.Since(start)
+	}
+	return qb.runGenerator(schema, payload, enc, profile)
+}
 
-	// Serialize schema
-	schemaJSON, err := json.Marshal(qb.engine.schema)
+// runGenerator generates SQL for a serialized query with the Rust generator
+func (qb *QueryBuilder) runGenerator(schema *Schema, payload []byte, enc Encoding, profile *QueryProfile) (*GeneratedSQL, error) {
+	start := time.Now()
+
+	// Schema held by the Rust core (loaded on first use)
+	core, err := qb.engine.core(schema)
 	if err != nil {
-		return nil, fmt.Errorf("failed to serialize schema: %w", err)
+		return nil, err
 	}
 
-	// Call Rust SQL generator via FFI
-	resultJSON, err := ffi.GenerateSQL(string(queryJSON), string(schemaJSON))
+	marshalled := time.Now()
+	var decoding time.Duration
+
+	// Call Rust SQL generator via FFI, parsing the result in place
+	var result GeneratedSQL
+	var parseErr error
+	err = core.GenerateSQLEncoded(enc.ffi(), payload, func(out []byte) error {
+		decodeStart := time.Now()
+		if enc == EncodingMsgPack {
+			parseErr = decodeGeneratedSQL(out, &result)
+		} else {
+			parseErr = json.Unmarshal(out, &result)
+		}
+		decoding = time.Since(decodeStart)
+		return parseErr
+	})
+	if parseErr != nil {
+		return nil, fmt.Errorf("failed to parse generated SQL: %w", parseErr)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("SQL generation failed: %w", err)
 	}
 
-	// Parse result
-	var result GeneratedSQL
-	if err := json.Unmarshal([]byte(resultJSON), &result); err != nil {
-		return nil, fmt.Errorf("failed to parse generated SQL: %w", err)
+	if profile != nil {
+		profile.Marshal += marshalled.Sub(start) + decoding
+		profile.Generate += time.Since(marshalled) - decoding
 	}
 
 	return &result, nil
 }
 
-// Execute generates SQL and runs it against the database
 // Execute generates SQL and runs it against the database
 func (qb *QueryBuilder) Execute(ctx context.Context) (*QueryResult, error) {
 	if qb.engine.executor == nil {
 		return nil, fmt.Errorf("executor not initialized - call engine.Connect() first")
 	}
+	if qb.isAggregate() {
+		return nil, fmt.Errorf("query has aggregates - use Aggregate() instead of Execute()")
+	}
 
 	start := time.Now()
+	profile := &QueryProfile{}
 
-	// Generate SQL
-	generated, err := qb.ToSQL()
+	// Generate SQL (or reuse it, see compile)
+	generated, err := qb.compile(profile)
 	if err != nil {
 		return nil, err
 	}
 
-	// Debug: Log SQL before execution
-	debugCtx := qb.getDebugContext()
-	debugCtx.LogSQL(generated.MainQuery)
-
 	// Execute via Executor (which handles everything: main query + eager loading)
-	result, err := qb.engine.executor.Execute(ctx, qb)
+	// SQL and traces are logged by the debug context, as a query hook
+	result, err := qb.engine.executor.Execute(ctx, qb, generated)
 	if err != nil {
 		return nil, err
 	}
 
-	// Debug: Log trace
-	duration := time.Since(start)
-	debugCtx.LogQuery(generated.MainQuery, duration, len(result.Rows))
+	profile.Main = result.Profile.Main
+	profile.Eager = result.Profile.Eager
+	profile.Total = time.Since(start)
+	result.Profile = profile
+
+	debugCtx := qb.getDebugContext()
+	debugCtx.LogProfile(qb.query.Entity, profile)
+
+	// Debug: Plans, after the query so EXPLAIN failures never hide results
+	if debugCtx.Level >= DebugExplain {
+		plans, err := qb.engine.executor.Explain(ctx, qb, generated, debugCtx.ExplainAnalyze)
+		if err != nil {
+			debugCtx.Log(DebugExplain, "%v", err)
+		} else {
+			debugCtx.LogExplain(plans)
+		}
+	}
 
 	return result, nil
 }
 
+// validateProjection checks selected fields against the schema
+// Unknown include paths are left to the SQL generator to report.
+func (qb *QueryBuilder) validateProjection(schema *Schema) error {
+	validator := NewValidator(schema, DefaultValidatorConfig())
+
+	if len(qb.query.Select) > 0 {
+		if err := validator.ValidateSelect(qb.query.Entity, qb.query.Select); err != nil {
+			return err
+		}
+	}
+
+	for _, include := range qb.query.Includes {
+		if len(include.Fields) == 0 {
+			continue
+		}
+		step, err := planEagerStep(schema, qb.query.Entity, strings.Join(include.Path, "."))
+		if err != nil {
+			continue
+		}
+		if err := validator.ValidateSelect(step.Target.Name, include.Fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // --- Debugging ---
 // Debug enables debug mode for this query
 func (qb *QueryBuilder) Debug() *QueryBuilder {
@@ -228,12 +417,22 @@ func (qb *QueryBuilder) DebugTrace() *QueryBuilder {
 	return qb
 }
 
+// DebugExplain enables the full trace plus the query plans for this query
+func (qb *QueryBuilder) DebugExplain() *QueryBuilder {
+	level := DebugExplain
+	qb.debugLevel = &level
+	return qb
+}
+
 func (qb *QueryBuilder) getDebugContext() *DebugContext {
 	if qb.debugLevel != nil {
 		return &DebugContext{
-			Level:       *qb.debugLevel,
-			Writer:      qb.engine.Debug.Writer,
-			ColorOutput: qb.engine.Debug.ColorOutput,
+			Level:           *qb.debugLevel,
+			Writer:          qb.engine.Debug.Writer,
+			ColorOutput:     qb.engine.Debug.ColorOutput,
+			EnableTiming:    qb.engine.Debug.EnableTiming,
+			EnableProfiling: qb.engine.Debug.EnableProfiling,
+			ExplainAnalyze:  qb.engine.Debug.ExplainAnalyze,
 		}
 	}
 	return qb.engine.Debug
@@ -292,6 +491,8 @@ func goValueToFilter(value interface{}) FilterValue {
 		return FilterValue{"Float": v}
 	case bool:
 		return FilterValue{"Bool": v}
+	case time.Time:
+		return FilterValue{"String": v.Format(time.RFC3339Nano)}
 	case nil:
 		return FilterValue{"Null": nil}
 	default:
@@ -1,10 +1,12 @@
 package engine
 
 import (
+	"encoding/json"
+	"errors"
 	"testing"
 )
 
-func setupTestEngine(t *testing.T) *Engine {
+func setupTestEngine(t testing.TB) *Engine {
 	t.Helper()
 
 	e := NewEngine()
@@ -96,6 +98,9 @@ func TestQueryBuilder_Include(t *testing.T) {
 	if len(result.EagerQueries) == 0 {
 		t.Fatal("Expected eager queries for include")
 	}
+
+	// Parent keys are bound, never inlined
+	assertContains(t, result.EagerQueries[0][1], "WHERE user_id = ANY($1)")
 }
 
 func TestQueryBuilder_NestedInclude(t *testing.T) {
@@ -114,6 +119,58 @@ func TestQueryBuilder_NestedInclude(t *testing.T) {
 	}
 }
 
+func TestQueryBuilder_ManyToMany(t *testing.T) {
+	e := setupManyToManyEngine(t)
+
+	result, err := e.Query("Post").
+		Filter("tags.name", "eq", "go").
+		Include("tags").
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL failed: %v", err)
+	}
+
+	assertContains(t, result.MainQuery, "INNER JOIN post_tags ON post_tags.post_id = posts.id")
+	assertContains(t, result.MainQuery, "INNER JOIN tags ON tags.id = post_tags.tag_id")
+	assertContains(t, result.MainQuery, "tags.name = 'go'")
+
+	if len(result.EagerQueries) != 1 {
+		t.Fatalf("Expected 1 eager query, got %d", len(result.EagerQueries))
+	}
+	assertContains(t, result.EagerQueries[0][1], "WHERE post_tags.post_id = ANY($1)")
+}
+
+func TestQueryBuilder_Select(t *testing.T) {
+	e := setupTestEngine(t)
+
+	result, err := e.Query("User").
+		Select("email", "name").
+		Include("orders", "total").
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL failed: %v", err)
+	}
+
+	// Primary key stays selected so orders can be matched to users
+	assertContains(t, result.MainQuery, "SELECT email, name, id\nFROM users")
+	assertContains(t, result.EagerQueries[0][1], "SELECT total, user_id\nFROM orders")
+}
+
+func TestQueryBuilder_SelectUnknownField(t *testing.T) {
+	e := setupTestEngine(t)
+
+	_, err := e.Query("User").Select("email", "phone").ToSQL()
+	var fieldErr *UnknownFieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "phone" {
+		t.Fatalf("Expected UnknownFieldError for 'phone', got %v", err)
+	}
+
+	_, err = e.Query("User").Include("orders", "amount").ToSQL()
+	if !errors.As(err, &fieldErr) || fieldErr.Entity != "Order" {
+		t.Fatalf("Expected UnknownFieldError on Order, got %v", err)
+	}
+}
+
 func TestQueryBuilder_FilterOnRelation(t *testing.T) {
 	e := setupTestEngine(t)
 
@@ -128,6 +185,90 @@ func TestQueryBuilder_FilterOnRelation(t *testing.T) {
 	assertContains(t, result.MainQuery, "DISTINCT")
 }
 
+func TestFilterComposition_JSON(t *testing.T) {
+	expr := Or(
+		Cond("status", "eq", "paid"),
+		Not(Cond("total", "lt", 10)),
+	)
+
+	data, err := json.Marshal(expr)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	want := `{"Binary":{"left":{"Condition":{"field":{"segments":["status"]},"op":"Eq","value":{"String":"paid"}}},` +
+		`"op":"Or",` +
+		`"right":{"Not":{"Condition":{"field":{"segments":["total"]},"op":"Lt","value":{"Int":10}}}}}}`
+	if string(data) != want {
+		t.Errorf("Unexpected JSON\nwant: %s\ngot:  %s", want, data)
+	}
+}
+
+func TestFilterComposition_Folding(t *testing.T) {
+	if !And().IsEmpty() || !Or(FilterExpr{}).IsEmpty() || !Not(FilterExpr{}).IsEmpty() {
+		t.Error("Expected empty expressions to stay empty")
+	}
+
+	single := Cond("status", "eq", "paid")
+	if got := And(single, FilterExpr{}); got.Condition == nil {
+		t.Errorf("Expected single expression to be returned as-is, got %+v", got)
+	}
+
+	// (a OR b) OR c
+	folded := Or(Cond("a", "eq", 1), Cond("b", "eq", 2), Cond("c", "eq", 3))
+	if folded.Binary == nil || folded.Binary.Left.Binary == nil || folded.Binary.Right.Condition == nil {
+		t.Errorf("Expected left-folded binary tree, got %+v", folded)
+	}
+}
+
+func TestQueryBuilder_WhereOr(t *testing.T) {
+	e := setupTestEngine(t)
+
+	result, err := e.Query("Order").
+		Where(Or(
+			Cond("status", "eq", "paid"),
+			And(Cond("status", "eq", "pending"), Cond("total", "gt", 100)),
+		)).
+		Filter("total", "lt", 1000).
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL failed: %v", err)
+	}
+
+	assertContains(t, result.MainQuery,
+		"WHERE (status = 'paid' OR (status = 'pending' AND total > 100)) AND total < 1000")
+}
+
+func TestQueryBuilder_WhereNot(t *testing.T) {
+	e := setupTestEngine(t)
+
+	result, err := e.Query("Order").
+		Where(Not(Or(Cond("total", "lt", 10), Cond("total", "gt", 500)))).
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL failed: %v", err)
+	}
+
+	assertContains(t, result.MainQuery, "WHERE NOT (total < 10 OR total > 500)")
+}
+
+func TestQueryBuilder_WhereRelationInOr(t *testing.T) {
+	e := setupTestEngine(t)
+
+	result, err := e.Query("User").
+		Where(Or(Cond("name", "eq", "Ana"), Cond("orders.total", "gt", 100))).
+		ToSQL()
+	if err != nil {
+		t.Fatalf("ToSQL failed: %v", err)
+	}
+
+	if contains(result.MainQuery, "INNER JOIN") {
+		t.Errorf("Expected no join for a relation inside OR, got:\n%s", result.MainQuery)
+	}
+	assertContains(t, result.MainQuery,
+		"EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id AND orders.total > 100)")
+}
+
 func TestQueryBuilder_OrderByLimitOffset(t *testing.T) {
 	e := setupTestEngine(t)
 
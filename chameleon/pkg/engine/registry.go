@@ -1,18 +1,39 @@
 // engine/registry.go
 package engine
 
-var mutationFactory MutationFactory
+import "sync"
 
-func RegisterMutationFactory(factory MutationFactory) {
-	if mutationFactory != nil {
-		panic("mutation factory already registered")
-	}
-	mutationFactory = factory
+// MutationFactoryConstructor builds a MutationFactory bound to one engine
+//
+// The constructor is called every time the engine loads a schema, so each
+// engine gets its own factory holding its own schema.
+type MutationFactoryConstructor func(eng *Engine) MutationFactory
+
+var (
+	registryMu             sync.RWMutex
+	mutationFactoryBuilder MutationFactoryConstructor
+)
+
+// RegisterMutationFactory sets the constructor used to create the default
+// mutation factory of every engine.
+//
+// The SQL implementation in pkg/engine/mutation registers itself on import.
+// Registering again replaces the previous constructor for engines that load
+// a schema afterwards.
+func RegisterMutationFactory(constructor MutationFactoryConstructor) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	mutationFactoryBuilder = constructor
 }
 
-func getMutationFactory() MutationFactory {
-	if mutationFactory == nil {
-		panic("no mutation factory registered")
+// newDefaultMutationFactory returns nil if no constructor is registered
+func newDefaultMutationFactory(eng *Engine) MutationFactory {
+	registryMu.RLock()
+	constructor := mutationFactoryBuilder
+	registryMu.RUnlock()
+
+	if constructor == nil {
+		return nil
 	}
-	return mutationFactory
+	return constructor(eng)
 }
@@ -42,14 +42,32 @@ func (r Row) Int(field string) int64 {
 	}
 }
 
+// Many returns the children of a to-many relation stitched onto this row
+// by Include (empty if none were found or the relation wasn't included)
+func (r Row) Many(relation string) []Row {
+	rows, _ := r[relation].([]Row)
+	return rows
+}
+
+// One returns the related row of a to-one relation stitched onto this row
+// by Include, or nil if there is none
+func (r Row) One(relation string) Row {
+	row, _ := r[relation].(Row)
+	return row
+}
+
 // QueryResult holds the result of a query execution
 type QueryResult struct {
 	// Entity name this result belongs to
 	Entity string
 	// Rows returned by the main query
 	Rows []Row
-	// Eager-loaded relations: relation name → rows
+	// Eager-loaded relations: include path ("orders", "orders.items") → all
+	// rows loaded at that level. Each parent row also carries its own
+	// children under the relation name (see Row.Many / Row.One).
 	Relations map[string][]Row
+	// Time spent per phase: SQL generation, each statement, scanning
+	Profile *QueryProfile
 }
 
 // Count returns the number of rows in the main result
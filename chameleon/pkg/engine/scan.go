@@ -0,0 +1,266 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ============================================================
+// TYPED SCANNING
+// ============================================================
+//
+// Rows are mapped onto structs by column name. The column of a field is
+// taken from its `cham` tag, or derived from the field name in snake_case:
+//
+//	type User struct {
+//		ID     string  `cham:"id"`
+//		Email  string  // → "email"
+//		Age    *int64  // NULL → nil
+//		Orders []Order `cham:"orders"` // included relation
+//		Secret string  `cham:"-"`      // never scanned
+//	}
+//
+// Columns without a matching field are ignored, fields without a column
+// (not selected, not included) keep their zero value.
+
+// Scan maps a single row onto a new T
+func Scan[T any](row Row) (T, error) {
+	var out T
+	err := scanInto(reflect.ValueOf(&out).Elem(), row, reflect.TypeFor[T]().Name())
+	return out, err
+}
+
+// ScanAll maps every row of a result onto a new T
+func ScanAll[T any](result *QueryResult) ([]T, error) {
+	out := make([]T, len(result.Rows))
+	name := reflect.TypeFor[T]().Name()
+
+	for i, row := range result.Rows {
+		if err := scanInto(reflect.ValueOf(&out[i]).Elem(), row, fmt.Sprintf("%s[%d]", name, i)); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// TypedQuery is a QueryBuilder whose results are scanned into T
+//
+//	q := engine.QueryAs[User](eng, "User")
+//	q.Filter("age", "gte", 18).Include("orders")
+//	users, err := q.All(ctx)
+type TypedQuery[T any] struct {
+	*QueryBuilder
+}
+
+// QueryAs starts a query whose rows are scanned into T
+func QueryAs[T any](e *Engine, entity string) *TypedQuery[T] {
+	return &TypedQuery[T]{QueryBuilder: e.Query(entity)}
+}
+
+// All executes the query and scans every row into T
+func (q *TypedQuery[T]) All(ctx context.Context) ([]T, error) {
+	result, err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ScanAll[T](result)
+}
+
+// Each streams the query and scans each row into T before calling fn
+func (q *TypedQuery[T]) Each(ctx context.Context, fn func(T) error) error {
+	return q.Stream(ctx, func(row Row) error {
+		item, err := Scan[T](row)
+		if err != nil {
+			return err
+		}
+		return fn(item)
+	})
+}
+
+// ─────────────────────────────────────────────────────────────
+// Reflection
+// ─────────────────────────────────────────────────────────────
+
+// structField maps a column onto a struct field
+type structField struct {
+	Column string
+	Index  []int
+	Name   string
+}
+
+// structFields caches the column mapping per struct type
+var structFields sync.Map // reflect.Type → []structField
+
+func fieldsOf(t reflect.Type) []structField {
+	if cached, ok := structFields.Load(t); ok {
+		return cached.([]structField)
+	}
+
+	var fields []structField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		column := f.Tag.Get("cham")
+		if column == "-" {
+			continue
+		}
+		if column == "" {
+			column = pascalToSnake(f.Name)
+		}
+		fields = append(fields, structField{Column: column, Index: f.Index, Name: f.Name})
+	}
+
+	structFields.Store(t, fields)
+	return fields
+}
+
+// scanInto maps row onto the struct (or pointer to struct) dst
+func scanInto(dst reflect.Value, row Row, path string) error {
+	if dst.Kind() == reflect.Pointer {
+		if row == nil {
+			dst.SetZero()
+			return nil
+		}
+		dst.Set(reflect.New(dst.Type().Elem()))
+		dst = dst.Elem()
+	}
+	if dst.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot scan rows into %s: not a struct", dst.Type())
+	}
+
+	for _, f := range fieldsOf(dst.Type()) {
+		value, ok := row[f.Column]
+		if !ok {
+			continue
+		}
+		if err := assignValue(dst.FieldByIndex(f.Index), value, f.Column, path+"."+f.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assignValue stores a column value in a field, converting only where no
+// information is lost
+func assignValue(dst reflect.Value, value interface{}, column, path string) error {
+	mismatch := func() error {
+		from := "NULL"
+		if value != nil {
+			from = reflect.TypeOf(value).String()
+		}
+		return &ScanError{Column: column, Field: path, From: from, To: dst.Type().String()}
+	}
+
+	if value == nil {
+		switch dst.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+			dst.SetZero()
+			return nil
+		}
+		return mismatch() // nullable columns need a pointer field
+	}
+
+	// Included relations
+	switch v := value.(type) {
+	case []Row:
+		if dst.Kind() == reflect.Slice && isStructLike(dst.Type().Elem()) {
+			out := reflect.MakeSlice(dst.Type(), len(v), len(v))
+			for i, child := range v {
+				if err := scanInto(out.Index(i), child, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			dst.Set(out)
+			return nil
+		}
+	case Row:
+		if isStructLike(dst.Type()) {
+			return scanInto(dst, v, path)
+		}
+	}
+
+	if dst.Kind() == reflect.Pointer {
+		elem := reflect.New(dst.Type().Elem())
+		if err := assignValue(elem.Elem(), value, column, path); err != nil {
+			return err
+		}
+		dst.Set(elem)
+		return nil
+	}
+
+	src := reflect.ValueOf(value)
+	if src.Type().AssignableTo(dst.Type()) {
+		dst.Set(src)
+		return nil
+	}
+
+	switch v := value.(type) {
+	case pgtype.Numeric:
+		// decimal columns
+		switch dst.Kind() {
+		case reflect.Float32, reflect.Float64:
+			f, err := v.Float64Value()
+			if err != nil || !f.Valid {
+				return mismatch()
+			}
+			dst.SetFloat(f.Float64)
+			return nil
+		case reflect.String:
+			text, err := v.MarshalJSON()
+			if err != nil {
+				return mismatch()
+			}
+			dst.SetString(string(text))
+			return nil
+		}
+	case [16]byte:
+		// uuid columns into string fields
+		if dst.Kind() == reflect.String {
+			dst.SetString(uuid.UUID(v).String())
+			return nil
+		}
+	}
+
+	switch {
+	case isInt(src.Kind()) && isInt(dst.Kind()):
+		n := src.Int()
+		if dst.OverflowInt(n) {
+			return mismatch()
+		}
+		dst.SetInt(n)
+		return nil
+	case isInt(src.Kind()) && isFloat(dst.Kind()):
+		dst.SetFloat(float64(src.Int()))
+		return nil
+	case isFloat(src.Kind()) && isFloat(dst.Kind()):
+		dst.SetFloat(src.Float())
+		return nil
+	case src.Kind() == dst.Kind() && src.Type().ConvertibleTo(dst.Type()):
+		// Named types of the same kind (uuid.UUID from [16]byte, custom strings)
+		dst.Set(src.Convert(dst.Type()))
+		return nil
+	}
+
+	return mismatch()
+}
+
+func isStructLike(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != reflect.TypeFor[pgtype.Numeric]()
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isFloat(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type scanItem struct {
+	Quantity int32
+	Price    float64
+}
+
+type scanOrder struct {
+	ID    uuid.UUID `cham:"id"`
+	Total float64
+	Items []scanItem `cham:"items"`
+	User  *scanUser  `cham:"user"`
+}
+
+type scanUser struct {
+	ID        string `cham:"id"`
+	Email     string
+	Age       *int
+	CreatedAt time.Time
+	Orders    []scanOrder `cham:"orders"`
+	Internal  string      `cham:"-"`
+}
+
+func TestScan_Fields(t *testing.T) {
+	id := [16]byte{0x11, 0x11}
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	user, err := Scan[scanUser](Row{
+		"id":         id,
+		"email":      "ana@mail.com",
+		"age":        int32(25),
+		"created_at": created,
+		"internal":   "ignored",
+		"extra":      "no field",
+	})
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+
+	if user.ID != uuid.UUID(id).String() {
+		t.Errorf("ID = %q, want %q", user.ID, uuid.UUID(id).String())
+	}
+	if user.Email != "ana@mail.com" {
+		t.Errorf("Email = %q", user.Email)
+	}
+	if user.Age == nil || *user.Age != 25 {
+		t.Errorf("Age = %v, want 25", user.Age)
+	}
+	if !user.CreatedAt.Equal(created) {
+		t.Errorf("CreatedAt = %v", user.CreatedAt)
+	}
+	if user.Internal != "" {
+		t.Errorf("Expected `cham:\"-\"` field to be skipped, got %q", user.Internal)
+	}
+}
+
+func TestScan_NullIntoPointer(t *testing.T) {
+	user, err := Scan[scanUser](Row{"email": "charlie@mail.com", "age": nil})
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	if user.Age != nil {
+		t.Errorf("Expected nil Age, got %v", *user.Age)
+	}
+}
+
+func TestScan_Mismatch(t *testing.T) {
+	cases := map[string]Row{
+		"int into string field": {"email": 42},
+		"NULL into non-pointer": {"email": nil},
+		"float into int":        {"age": 25.5},
+	}
+	for name, row := range cases {
+		_, err := Scan[scanUser](row)
+		var scanErr *ScanError
+		if !errors.As(err, &scanErr) {
+			t.Errorf("%s: expected ScanError, got %v", name, err)
+		}
+	}
+
+	_, err := Scan[scanItem](Row{"quantity": int64(1) << 40})
+	var scanErr *ScanError
+	if !errors.As(err, &scanErr) || scanErr.Field != "scanItem.Quantity" {
+		t.Errorf("Expected overflow ScanError on scanItem.Quantity, got %v", err)
+	}
+}
+
+func TestScanAll_Relations(t *testing.T) {
+	orderID := [16]byte{0xaa}
+	result := &QueryResult{
+		Rows: []Row{
+			{
+				"email": "ana@mail.com",
+				"orders": []Row{
+					{
+						"id":    orderID,
+						"total": pgtype.Numeric{Int: big.NewInt(15050), Exp: -2, Valid: true},
+						"items": []Row{{"quantity": int32(2), "price": pgtype.Numeric{Int: big.NewInt(5025), Exp: -2, Valid: true}}},
+						"user":  Row{"email": "ana@mail.com"},
+					},
+				},
+			},
+			{"email": "charlie@mail.com", "orders": []Row{}},
+		},
+	}
+
+	users, err := ScanAll[scanUser](result)
+	if err != nil {
+		t.Fatalf("ScanAll failed: %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("Expected 2 users, got %d", len(users))
+	}
+
+	ana := users[0]
+	if len(ana.Orders) != 1 {
+		t.Fatalf("Expected 1 order, got %d", len(ana.Orders))
+	}
+	order := ana.Orders[0]
+	if order.ID != uuid.UUID(orderID) || order.Total != 150.50 {
+		t.Errorf("Unexpected order: %+v", order)
+	}
+	if len(order.Items) != 1 || order.Items[0].Quantity != 2 || order.Items[0].Price != 50.25 {
+		t.Errorf("Unexpected items: %+v", order.Items)
+	}
+	if order.User == nil || order.User.Email != "ana@mail.com" {
+		t.Errorf("Expected belongs-to user, got %+v", order.User)
+	}
+
+	if users[1].Orders == nil || len(users[1].Orders) != 0 {
+		t.Errorf("Expected empty orders slice, got %#v", users[1].Orders)
+	}
+}
+
+func TestScanAll_ErrorPath(t *testing.T) {
+	result := &QueryResult{Rows: []Row{
+		{"email": "ana@mail.com", "orders": []Row{{"total": "not a number"}}},
+	}}
+
+	_, err := ScanAll[scanUser](result)
+	var scanErr *ScanError
+	if !errors.As(err, &scanErr) {
+		t.Fatalf("Expected ScanError, got %v", err)
+	}
+	if scanErr.Field != "scanUser[0].Orders[0].Total" || scanErr.Column != "total" {
+		t.Errorf("Unexpected error location: %v", scanErr)
+	}
+}
@@ -3,6 +3,8 @@ package engine
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 )
 
 // Schema represents the complete database schema
@@ -26,6 +28,8 @@ type Field struct {
 	PrimaryKey bool         `json:"primary_key"`
 	Default    *interface{} `json:"default,omitempty"`
 	Backend    *string      `json:"backend,omitempty"`
+	// Constraints declared in the schema: min, max, length, pattern, check
+	Constraints []FieldConstraint `json:"constraints,omitempty"`
 }
 
 // FieldType represents the type of a field and can be simple or complex
@@ -89,6 +93,69 @@ func (ft FieldType) String() string {
 	return fmt.Sprintf("%s(%v)", ft.Kind, ft.Param)
 }
 
+// FieldConstraint mirrors Rust's FieldConstraint
+// JSON: {"Min": "0"}, {"Length": {"min": 1, "max": 255}}, {"Pattern": "^[a-z]+$"}
+type FieldConstraint struct {
+	Kind   string // "Min", "Max", "Length", "Pattern", "Check"
+	Value  string // bound (Min/Max), regex (Pattern) or SQL expression (Check)
+	MinLen *int   // Length lower bound, if any
+	MaxLen *int   // Length upper bound, if any
+}
+
+type lengthBounds struct {
+	Min *int `json:"min"`
+	Max *int `json:"max"`
+}
+
+// UnmarshalJSON deserializes the externally tagged Rust enum
+func (fc *FieldConstraint) UnmarshalJSON(data []byte) error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil || len(obj) != 1 {
+		return fmt.Errorf("cannot unmarshal FieldConstraint from %s", string(data))
+	}
+
+	for kind, raw := range obj {
+		*fc = FieldConstraint{Kind: kind}
+		if kind == "Length" {
+			var bounds lengthBounds
+			if err := json.Unmarshal(raw, &bounds); err != nil {
+				return fmt.Errorf("invalid Length constraint: %w", err)
+			}
+			fc.MinLen, fc.MaxLen = bounds.Min, bounds.Max
+			return nil
+		}
+		if err := json.Unmarshal(raw, &fc.Value); err != nil {
+			return fmt.Errorf("invalid %s constraint: %w", kind, err)
+		}
+	}
+	return nil
+}
+
+// MarshalJSON serializes FieldConstraint in the Rust enum layout
+func (fc FieldConstraint) MarshalJSON() ([]byte, error) {
+	if fc.Kind == "Length" {
+		return json.Marshal(map[string]lengthBounds{fc.Kind: {Min: fc.MinLen, Max: fc.MaxLen}})
+	}
+	return json.Marshal(map[string]string{fc.Kind: fc.Value})
+}
+
+// String renders the constraint as written in a .cham schema
+func (fc FieldConstraint) String() string {
+	switch fc.Kind {
+	case "Length":
+		bound := func(n *int) string {
+			if n == nil {
+				return ""
+			}
+			return fmt.Sprint(*n)
+		}
+		return fmt.Sprintf("length(%s..%s)", bound(fc.MinLen), bound(fc.MaxLen))
+	case "Pattern", "Check":
+		return fmt.Sprintf("%s(%q)", strings.ToLower(fc.Kind), fc.Value)
+	}
+	return fmt.Sprintf("%s(%s)", strings.ToLower(fc.Kind), fc.Value)
+}
+
 // Relation represents a relationship between entities
 type Relation struct {
 	Name         string       `json:"name"`
@@ -108,6 +175,105 @@ const (
 	RelationManyToMany RelationKind = "ManyToMany"
 )
 
+// PrimaryKey returns the primary key field name ("id" if none is declared)
+func (e *Entity) PrimaryKey() string {
+	for name, field := range e.Fields {
+		if field.PrimaryKey {
+			return name
+		}
+	}
+	return "id"
+}
+
+// ForeignKey resolves the FK column of a relation declared on entity
+//
+// HasMany/HasOne declare it with `via`. A BelongsTo relation (`user: User`)
+// reuses the FK of the inverse relation on the target, falling back to
+// `<relation>_id` (mirrors resolve_foreign_key in chameleon-core/src/sql/generator.rs)
+func (s *Schema) ForeignKey(entity *Entity, rel *Relation) (string, bool) {
+	if rel.ForeignKey != nil {
+		return *rel.ForeignKey, true
+	}
+	if rel.Kind != RelationBelongsTo {
+		return "", false
+	}
+
+	if target := s.GetEntity(rel.TargetEntity); target != nil {
+		var inverse []string
+		for _, r := range target.Relations {
+			if r.Kind != RelationHasMany && r.Kind != RelationHasOne {
+				continue
+			}
+			if r.TargetEntity != entity.Name || r.ForeignKey == nil {
+				continue
+			}
+			if _, ok := entity.Fields[*r.ForeignKey]; ok {
+				inverse = append(inverse, *r.ForeignKey)
+			}
+		}
+		if len(inverse) > 0 {
+			sort.Strings(inverse)
+			return inverse[0], true
+		}
+	}
+
+	conventional := rel.Name + "_id"
+	if _, ok := entity.Fields[conventional]; ok {
+		return conventional, true
+	}
+	return "", false
+}
+
+// ThroughKeys resolves the join entity columns of a ManyToMany relation
+//
+// Returns the columns on the `through` entity pointing at the declaring
+// entity (source) and at the target. Each side comes from the join
+// entity's BelongsTo relation to it, falling back to `<snake_entity>_id`
+// (mirrors Schema::resolve_through_keys in chameleon-core/src/ast/mod.rs)
+//
+//	Post.tags: [Tag] through PostTag → ("post_id", "tag_id")
+func (s *Schema) ThroughKeys(entity *Entity, rel *Relation) (source, target string, ok bool) {
+	if rel.Kind != RelationManyToMany || rel.Through == nil {
+		return "", "", false
+	}
+
+	join := s.GetEntity(*rel.Through)
+	if join == nil {
+		return "", "", false
+	}
+
+	keyTo := func(side string) (string, bool) {
+		var candidates []string
+		for _, r := range join.Relations {
+			if r.Kind != RelationBelongsTo || r.TargetEntity != side {
+				continue
+			}
+			if fk, ok := s.ForeignKey(join, r); ok {
+				candidates = append(candidates, fk)
+			}
+		}
+		if len(candidates) > 0 {
+			sort.Strings(candidates)
+			return candidates[0], true
+		}
+
+		conventional := pascalToSnake(side) + "_id"
+		if _, ok := join.Fields[conventional]; ok {
+			return conventional, true
+		}
+		return "", false
+	}
+
+	source, okSource := keyTo(entity.Name)
+	target, okTarget := keyTo(rel.TargetEntity)
+
+	// Self-referencing relations need two distinct columns
+	if !okSource || !okTarget || source == target {
+		return "", "", false
+	}
+	return source, target, true
+}
+
 // ParseSchemaJSON parses a JSON string into a Schema
 func ParseSchemaJSON(jsonStr string) (*Schema, error) {
 	var schema Schema
@@ -0,0 +1,205 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// defaultChunkSize is how many rows Stream buffers per batch of includes
+const defaultChunkSize = 1000
+
+// errStopStream ends a stream early when an iterator's consumer breaks
+var errStopStream = errors.New("stream stopped")
+
+// cursorSeq names the cursors opened by streams with includes
+var cursorSeq atomic.Uint64
+
+// ChunkSize sets how many rows Stream loads includes for at a time
+// (defaults to 1000). Only rows of the current chunk are held in memory.
+func (qb *QueryBuilder) ChunkSize(n int) *QueryBuilder {
+	qb.chunkSize = n
+	return qb
+}
+
+// Stream runs the query and calls fn for each row as it is read, without
+// collecting the whole result. Returning an error from fn stops the stream
+// and Stream returns that error. Cancelling ctx stops it as well.
+//
+// Rows carry their includes like with Execute; they are loaded per chunk
+// of rows (see ChunkSize).
+func (qb *QueryBuilder) Stream(ctx context.Context, fn func(Row) error) error {
+	if qb.engine.executor == nil {
+		return fmt.Errorf("executor not initialized - call engine.Connect() first")
+	}
+	if qb.isAggregate() {
+		return fmt.Errorf("query has aggregates - use Aggregate() instead of Stream()")
+	}
+
+	generated, err := qb.compile(nil)
+	if err != nil {
+		return err
+	}
+
+	// The query event spans the whole stream
+	ctx, done := TraceQuery(ctx, qb.hook(), &QueryEvent{
+		Operation: OpQuery,
+		Entity:    qb.query.Entity,
+		SQL:       generated.MainQuery,
+		Args:      generated.Args,
+	})
+
+	count := 0
+	err = qb.engine.executor.Stream(ctx, qb, generated, func(row Row) error {
+		count++
+		return fn(row)
+	})
+	if errors.Is(err, errStopStream) {
+		done(count, nil) // the consumer stopped early
+	} else {
+		done(count, err)
+	}
+
+	return err
+}
+
+// Iter returns an iterator over the query's rows, read as they arrive:
+//
+//	for row, err := range db.Query("User").Iter(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+//
+// Breaking out of the loop stops the query. Inside a transaction the
+// remaining rows are read and discarded instead, which keeps the
+// transaction usable.
+func (qb *QueryBuilder) Iter(ctx context.Context) iter.Seq2[Row, error] {
+	return func(yield func(Row, error) bool) {
+		err := qb.Stream(ctx, func(row Row) error {
+			if !yield(row, nil) {
+				return errStopStream
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopStream) {
+			yield(nil, err)
+		}
+	}
+}
+
+// Stream reads the main query row by row and passes each row to fn
+func (ex *Executor) Stream(ctx context.Context, qb *QueryBuilder, generated *GeneratedSQL, fn func(Row) error) error {
+	if !ex.connector.IsConnected() {
+		return fmt.Errorf("not connected to database")
+	}
+
+	if len(generated.EagerQueries) > 0 {
+		return ex.streamChunks(ctx, qb, generated, fn)
+	}
+
+	// Closing rows reads whatever the server still sends, so stopping early
+	// cancels the query first. That also drops the connection, which would
+	// abort a caller's transaction: there the rest is read instead.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := func() {
+		if qb.tx == nil {
+			cancel()
+		}
+	}
+
+	rows, err := ex.querier(qb).Query(ctx, generated.MainQuery, generated.Args...)
+	if err != nil {
+		return fmt.Errorf("main query failed: %w", TranslatePgError(qb.engine.Schema(), err))
+	}
+	defer rows.Close()
+
+	columns := rows.FieldDescriptions()
+	for rows.Next() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		row, err := scanRow(rows, columns)
+		if err != nil {
+			stop()
+			return err
+		}
+		if err := fn(row); err != nil {
+			stop()
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// streamChunks pages through the main query with a cursor and loads
+// includes for each page.
+//
+// Eager queries must run while the main query is still open, which a single
+// connection can't do with a plain result set. A cursor can, so the stream
+// runs on the query's transaction or a dedicated read-only one.
+func (ex *Executor) streamChunks(ctx context.Context, qb *QueryBuilder, generated *GeneratedSQL, fn func(Row) error) error {
+	size := qb.chunkSize
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+
+	var tx pgx.Tx
+	if qb.tx != nil {
+		tx = qb.tx.tx
+	} else {
+		var err error
+		tx, err = ex.connector.Begin(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+		if err != nil {
+			return fmt.Errorf("failed to begin stream: %w", err)
+		}
+		// Read-only: nothing to commit, rollback also closes the cursor
+		defer tx.Rollback(context.Background())
+	}
+
+	cursor := fmt.Sprintf("chameleon_stream_%d", cursorSeq.Add(1))
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DECLARE %s NO SCROLL CURSOR FOR %s", cursor, generated.MainQuery), generated.Args...); err != nil {
+		return fmt.Errorf("main query failed: %w", TranslatePgError(qb.engine.Schema(), err))
+	}
+	if qb.tx != nil {
+		// The caller's transaction outlives the stream
+		defer tx.Exec(context.Background(), "CLOSE "+cursor)
+	}
+
+	fetch := fmt.Sprintf("FETCH FORWARD %d FROM %s", size, cursor)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		chunk, err := ex.executeQuery(ctx, tx, fetch)
+		if err != nil {
+			return fmt.Errorf("main query failed: %w", TranslatePgError(qb.engine.Schema(), err))
+		}
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		if _, err := ex.loadIncludes(ctx, tx, qb, generated.EagerQueries, chunk, nil); err != nil {
+			return err
+		}
+
+		for _, row := range chunk {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+
+		if len(chunk) < size {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStream_NotConnected(t *testing.T) {
+	e := setupTestEngine(t)
+
+	err := e.Query("User").Stream(context.Background(), func(Row) error {
+		t.Fatal("Expected no rows")
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Expected error when not connected")
+	}
+}
+
+func TestIter_YieldsErrorOnce(t *testing.T) {
+	e := setupTestEngine(t)
+
+	var errs int
+	for row, err := range e.Query("User").Iter(context.Background()) {
+		if row != nil {
+			t.Fatalf("Expected no rows, got %v", row)
+		}
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Fatalf("Expected exactly one error, got %d", errs)
+	}
+}
@@ -0,0 +1,243 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// IsolationLevel sets the transaction isolation level
+type IsolationLevel string
+
+const (
+	IsolationDefault         IsolationLevel = "" // server default (read committed)
+	IsolationReadUncommitted IsolationLevel = "read uncommitted"
+	IsolationReadCommitted   IsolationLevel = "read committed"
+	IsolationRepeatableRead  IsolationLevel = "repeatable read"
+	IsolationSerializable    IsolationLevel = "serializable"
+)
+
+// TxOptions configures a transaction
+type TxOptions struct {
+	Isolation  IsolationLevel
+	ReadOnly   bool
+	Deferrable bool // only meaningful for serializable read-only transactions
+}
+
+func (o TxOptions) toPgx() pgx.TxOptions {
+	opts := pgx.TxOptions{
+		IsoLevel: pgx.TxIsoLevel(o.Isolation),
+	}
+	if o.ReadOnly {
+		opts.AccessMode = pgx.ReadOnly
+	}
+	if o.Deferrable {
+		opts.DeferrableMode = pgx.Deferrable
+	}
+	return opts
+}
+
+// Tx is a database transaction
+//
+// Queries and mutations started from a Tx run on the same connection.
+// Nested transactions (Tx.Begin / Tx.Tx) are implemented with savepoints.
+type Tx struct {
+	engine *Engine
+	tx     pgx.Tx
+	depth  int // 0 = top-level transaction, >0 = savepoint
+}
+
+// ─────────────────────────────────────────────────────────────
+// Starting transactions
+// ─────────────────────────────────────────────────────────────
+
+// Begin starts a transaction with default options
+// The caller must Commit or Rollback it
+func (e *Engine) Begin(ctx context.Context) (*Tx, error) {
+	return e.BeginTx(ctx, TxOptions{})
+}
+
+// BeginTx starts a transaction with the given options
+func (e *Engine) BeginTx(ctx context.Context, opts TxOptions) (*Tx, error) {
+	if e.connector == nil {
+		return nil, fmt.Errorf("not connected - call engine.Connect() first")
+	}
+
+	pgxTx, err := e.connector.Begin(ctx, opts.toPgx())
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	return &Tx{engine: e, tx: pgxTx}, nil
+}
+
+// Tx runs fn inside a transaction
+//
+// The transaction is committed if fn returns nil and rolled back if fn
+// returns an error or panics (the panic is re-raised after rollback).
+//
+//	err := eng.Tx(ctx, func(tx *engine.Tx) error {
+//	    order, err := tx.Insert("Order").Set(...).Execute(ctx)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    _, err = tx.Insert("OrderItem").Set("order_id", order.ID).Execute(ctx)
+//	    return err
+//	})
+func (e *Engine) Tx(ctx context.Context, fn func(tx *Tx) error) error {
+	return e.TxWithOptions(ctx, TxOptions{}, fn)
+}
+
+// TxWithOptions is Tx with a custom isolation level / access mode
+func (e *Engine) TxWithOptions(ctx context.Context, opts TxOptions, fn func(tx *Tx) error) error {
+	tx, err := e.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	return tx.run(ctx, fn)
+}
+
+// ─────────────────────────────────────────────────────────────
+// Savepoints
+// ─────────────────────────────────────────────────────────────
+
+// Begin starts a nested transaction backed by a savepoint
+// Rolling it back only undoes the work done since the savepoint
+func (tx *Tx) Begin(ctx context.Context) (*Tx, error) {
+	nested, err := tx.tx.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create savepoint: %w", err)
+	}
+	return &Tx{engine: tx.engine, tx: nested, depth: tx.depth + 1}, nil
+}
+
+// Tx runs fn inside a savepoint of this transaction
+// An error from fn rolls back to the savepoint; the outer
+// transaction stays usable
+func (tx *Tx) Tx(ctx context.Context, fn func(tx *Tx) error) error {
+	nested, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	return nested.run(ctx, fn)
+}
+
+// IsNested returns true if this transaction is a savepoint
+func (tx *Tx) IsNested() bool {
+	return tx.depth > 0
+}
+
+// ─────────────────────────────────────────────────────────────
+// Completion
+// ─────────────────────────────────────────────────────────────
+
+// Commit commits the transaction (or releases the savepoint)
+func (tx *Tx) Commit(ctx context.Context) error {
+	if err := tx.tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit failed: %w", TranslatePgError(tx.engine.Schema(), err))
+	}
+	return nil
+}
+
+// Rollback aborts the transaction (or rolls back to the savepoint)
+// Rolling back an already finished transaction is a no-op, so
+// `defer tx.Rollback(ctx)` is safe after Commit
+func (tx *Tx) Rollback(ctx context.Context) error {
+	err := tx.tx.Rollback(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		return fmt.Errorf("rollback failed: %w", err)
+	}
+	return nil
+}
+
+func (tx *Tx) run(ctx context.Context, fn func(tx *Tx) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback(ctx)
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("%w (%v)", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ─────────────────────────────────────────────────────────────
+// Queries and mutations on the transaction
+// ─────────────────────────────────────────────────────────────
+
+// Query starts a query that runs inside this transaction
+func (tx *Tx) Query(entity string) *QueryBuilder {
+	qb := tx.engine.Query(entity)
+	qb.tx = tx
+	return qb
+}
+
+// Insert starts an INSERT that runs inside this transaction
+func (tx *Tx) Insert(entity string) InsertMutation {
+	return tx.mutations().NewInsert(entity)
+}
+
+// Update starts an UPDATE that runs inside this transaction
+func (tx *Tx) Update(entity string) UpdateMutation {
+	return tx.mutations().NewUpdate(entity)
+}
+
+// Delete starts a DELETE that runs inside this transaction
+func (tx *Tx) Delete(entity string) DeleteMutation {
+	return tx.mutations().NewDelete(entity)
+}
+
+// Link starts a ManyToMany connect/disconnect inside this transaction
+func (tx *Tx) Link(entity string, relation string, id interface{}) LinkMutation {
+	return linkFactory(tx.mutations()).NewLink(entity, relation, id)
+}
+
+func (tx *Tx) mutations() MutationFactory {
+	tx.engine.ensureSchemaLoaded()
+
+	factory, ok := tx.engine.mutationFactory().(TxMutationFactory)
+	if !ok {
+		panic("mutation factory does not support transactions (must implement engine.TxMutationFactory)")
+	}
+	return factory.WithQuerier(tx.Querier())
+}
+
+// Exec runs a raw SQL statement inside this transaction
+func (tx *Tx) Exec(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	return tx.Querier().Exec(ctx, sql, args...)
+}
+
+// Querier returns the raw SQL session bound to this transaction
+func (tx *Tx) Querier() Querier {
+	return txQuerier{tx: tx.tx}
+}
+
+// txQuerier implements Querier on a pgx transaction
+type txQuerier struct {
+	tx pgx.Tx
+}
+
+func (q txQuerier) Query(ctx context.Context, sql string, args ...interface{}) ([]map[string]interface{}, error) {
+	rows, err := q.tx.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	return collectMaps(rows)
+}
+
+func (q txQuerier) Exec(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	tag, err := q.tx.Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, fmt.Errorf("exec failed: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
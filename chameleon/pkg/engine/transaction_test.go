@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestBeginNotConnected(t *testing.T) {
+	e := setupTestEngine(t)
+
+	if _, err := e.Begin(context.Background()); err == nil {
+		t.Fatal("Begin() should fail when engine is not connected")
+	}
+}
+
+func TestTxNotConnectedSkipsCallback(t *testing.T) {
+	e := setupTestEngine(t)
+
+	called := false
+	err := e.Tx(context.Background(), func(tx *Tx) error {
+		called = true
+		return nil
+	})
+
+	if err == nil {
+		t.Fatal("Tx() should fail when engine is not connected")
+	}
+	if called {
+		t.Error("callback should not run without a transaction")
+	}
+}
+
+func TestTxOptionsToPgx(t *testing.T) {
+	opts := TxOptions{
+		Isolation:  IsolationSerializable,
+		ReadOnly:   true,
+		Deferrable: true,
+	}.toPgx()
+
+	if opts.IsoLevel != pgx.Serializable {
+		t.Errorf("Expected serializable, got %q", opts.IsoLevel)
+	}
+	if opts.AccessMode != pgx.ReadOnly {
+		t.Errorf("Expected read only, got %q", opts.AccessMode)
+	}
+	if opts.DeferrableMode != pgx.Deferrable {
+		t.Errorf("Expected deferrable, got %q", opts.DeferrableMode)
+	}
+
+	defaults := TxOptions{}.toPgx()
+	if defaults.IsoLevel != "" || defaults.AccessMode != "" || defaults.DeferrableMode != "" {
+		t.Errorf("Default options should leave server defaults, got %+v", defaults)
+	}
+}
+
+func TestIsolationLevelsMatchPgx(t *testing.T) {
+	levels := map[IsolationLevel]pgx.TxIsoLevel{
+		IsolationReadUncommitted: pgx.ReadUncommitted,
+		IsolationReadCommitted:   pgx.ReadCommitted,
+		IsolationRepeatableRead:  pgx.RepeatableRead,
+		IsolationSerializable:    pgx.Serializable,
+	}
+
+	for level, want := range levels {
+		if got := (TxOptions{Isolation: level}).toPgx().IsoLevel; got != want {
+			t.Errorf("%q: expected %q, got %q", level, want, got)
+		}
+	}
+}
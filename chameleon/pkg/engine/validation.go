@@ -1,11 +1,18 @@
 package engine
 
 import (
+	"context"
 	"fmt"
+	"math/big"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 // ============================================================
@@ -13,8 +20,12 @@ import (
 // ============================================================
 
 type ValidatorConfig struct {
+	// StrictTypes rejects strings for Int/Float/Bool fields and floats for
+	// Int fields; when false they are parsed ("42", "true", 42.0)
 	StrictTypes bool
-	ValidateFK  bool
+	// ValidateFK looks up referenced rows before inserts and updates that
+	// set a foreign key (see ValidateForeignKeys)
+	ValidateFK bool
 }
 
 func DefaultValidatorConfig() ValidatorConfig {
@@ -44,6 +55,9 @@ func NewValidator(schema *Schema, config ValidatorConfig) *Validator {
 // INSERT VALIDATION
 // ============================================================
 
+// ValidateInsertInput checks an insert against the schema
+// Every field problem is reported in a single *ValidationErrors; values are
+// replaced in place by their coerced form (see validateFieldType)
 func (v *Validator) ValidateInsertInput(
 	entity string,
 	fields map[string]interface{},
@@ -56,51 +70,60 @@ func (v *Validator) ValidateInsertInput(
 		}
 	}
 
-	for fieldName, value := range fields {
-		if err := v.validateInsertField(ent, fieldName, value); err != nil {
-			return err
+	var errs ValidationErrors
+	for _, fieldName := range sortedFieldNames(fields) {
+		coerced, err := v.validateInsertField(ent, fieldName, fields[fieldName])
+		if err != nil {
+			errs.Add(err)
+			continue
 		}
+		fields[fieldName] = coerced
 	}
 
-	return v.validateRequiredFields(ent, fields)
+	v.validateRequiredFields(ent, fields, &errs)
+	return errs.Err()
 }
 
 func (v *Validator) validateInsertField(
 	ent *Entity,
 	fieldName string,
 	value interface{},
-) error {
+) (interface{}, error) {
 	field, ok := ent.Fields[fieldName]
 	if !ok {
-		return &UnknownFieldError{
+		return nil, &UnknownFieldError{
 			Entity:    ent.Name,
 			Field:     fieldName,
 			Available: v.getAvailableFields(ent),
 		}
 	}
 
-	if err := v.validateFieldType(field, fieldName, value); err != nil {
-		return err
+	coerced, err := v.validateFieldType(field, fieldName, value)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := v.validateFieldFormat(fieldName, value); err != nil {
-		return err
+	if err := v.validateConstraints(field, fieldName, coerced); err != nil {
+		return nil, err
 	}
 
-	if value == nil && !field.Nullable {
-		return &NotNullError{
+	if coerced == nil && !field.Nullable {
+		return nil, &NotNullError{
 			Field:      fieldName,
 			Suggestion: "Provide a value for this field",
 		}
 	}
 
-	return nil
+	return coerced, nil
 }
 
 // ============================================================
 // UPDATE VALIDATION
 // ============================================================
 
+// ValidateUpdateInput checks an update against the schema
+// Every field problem is reported in a single *ValidationErrors; updated
+// values are replaced in place by their coerced form
 func (v *Validator) ValidateUpdateInput(
 	entity string,
 	filters map[string]interface{},
@@ -122,40 +145,58 @@ func (v *Validator) ValidateUpdateInput(
 		}
 	}
 
-	for fieldName := range filters {
+	if len(updates) == 0 {
+		return &ValidationError{
+			Type:     "empty_update",
+			Expected: "at least one field to set",
+			Message:  "UPDATE has nothing to set; use Set() to choose the new values",
+		}
+	}
+
+	var errs ValidationErrors
+	for _, fieldName := range sortedFieldNames(filters) {
 		if _, ok := ent.Fields[fieldName]; !ok {
-			return &UnknownFieldError{
+			errs.Add(&UnknownFieldError{
 				Entity:    ent.Name,
 				Field:     fieldName,
 				Available: v.getAvailableFields(ent),
-			}
+			})
 		}
 	}
 
-	for fieldName, value := range updates {
+	for _, fieldName := range sortedFieldNames(updates) {
 		field, ok := ent.Fields[fieldName]
 		if !ok {
-			return &UnknownFieldError{
+			errs.Add(&UnknownFieldError{
 				Entity:    ent.Name,
 				Field:     fieldName,
 				Available: v.getAvailableFields(ent),
-			}
+			})
+			continue
 		}
 
 		if field.PrimaryKey {
-			return &ConstraintError{
+			errs.Add(&ConstraintError{
 				Type:       "primary_key",
+				Entity:     ent.Name,
 				Field:      fieldName,
 				Suggestion: "Primary keys cannot be updated",
-			}
+			})
+			continue
 		}
 
-		if err := v.validateFieldType(field, fieldName, value); err != nil {
-			return err
+		coerced, err := v.validateFieldType(field, fieldName, updates[fieldName])
+		if err == nil {
+			err = v.validateConstraints(field, fieldName, coerced)
+		}
+		if err != nil {
+			errs.Add(err)
+			continue
 		}
+		updates[fieldName] = coerced
 	}
 
-	return nil
+	return errs.Err()
 }
 
 // ============================================================
@@ -187,71 +228,307 @@ func (v *Validator) ValidateDeleteInput(
 }
 
 // ============================================================
-// FIELD TYPE VALIDATION
+// LINK VALIDATION (ManyToMany)
 // ============================================================
 
-func (v *Validator) validateFieldType(
-	field *Field,
-	fieldName string,
-	value interface{},
+// ValidateLinkInput checks a connect/disconnect on a ManyToMany relation
+// id is the owner's primary key, targets are the target primary keys; the
+// coerced id is returned and targets are replaced in place by their
+// coerced form
+func (v *Validator) ValidateLinkInput(
+	entity string,
+	relation string,
+	id interface{},
+	targets []interface{},
+) (interface{}, error) {
+	ent := v.schema.GetEntity(entity)
+	if ent == nil {
+		return nil, &UnknownEntityError{
+			Entity:    entity,
+			Available: v.getAvailableEntities(),
+		}
+	}
+
+	rel, ok := ent.Relations[relation]
+	if !ok {
+		return nil, &UnknownRelationError{
+			Entity:    entity,
+			Relation:  relation,
+			Available: v.getAvailableRelations(ent),
+		}
+	}
+
+	if rel.Kind != RelationManyToMany {
+		return nil, &ValidationError{
+			Field:    relation,
+			Type:     "invalid_relation",
+			Value:    string(rel.Kind),
+			Expected: string(RelationManyToMany),
+			Message:  "Only ManyToMany relations can be connected/disconnected; set the foreign key field instead",
+		}
+	}
+
+	if _, _, ok := v.schema.ThroughKeys(ent, rel); !ok {
+		return nil, &ValidationError{
+			Field:    relation,
+			Type:     "invalid_relation",
+			Expected: "through entity with keys to both sides",
+			Message:  fmt.Sprintf("Cannot resolve join keys of '%s.%s'", entity, relation),
+		}
+	}
+
+	pk := ent.PrimaryKey()
+	if field, ok := ent.Fields[pk]; ok {
+		coerced, err := v.validateFieldType(field, pk, id)
+		if err != nil {
+			return nil, err
+		}
+		id = coerced
+	}
+
+	if target := v.schema.GetEntity(rel.TargetEntity); target != nil {
+		targetPK := target.PrimaryKey()
+		if field, ok := target.Fields[targetPK]; ok {
+			for i, t := range targets {
+				coerced, err := v.validateFieldType(field, relation, t)
+				if err != nil {
+					return nil, err
+				}
+				targets[i] = coerced
+			}
+		}
+	}
+
+	return id, nil
+}
+
+// ============================================================
+// FOREIGN KEY VALIDATION
+// ============================================================
+
+// foreignKeyRef is a field of an entity referencing another entity's key
+type foreignKeyRef struct {
+	Field    string
+	Relation string
+	Target   *Entity
+}
+
+// ValidateForeignKeys checks that every foreign key set in values points at
+// an existing row, with one lookup per referenced entity. NULL keys are
+// skipped. It does nothing unless ValidateFK is enabled.
+func (v *Validator) ValidateForeignKeys(
+	ctx context.Context,
+	q Querier,
+	entity string,
+	values map[string]interface{},
 ) error {
-	if value == nil {
-		if field.Nullable {
-			return nil
+	if !v.config.ValidateFK || q == nil {
+		return nil
+	}
+	ent := v.schema.GetEntity(entity)
+	if ent == nil {
+		return nil
+	}
+
+	// Group the keys to look up by referenced entity
+	pending := make(map[string][]foreignKeyRef)
+	var targets []string
+	for _, ref := range v.schema.foreignKeyRefs(ent) {
+		if value, ok := values[ref.Field]; !ok || derefValue(value) == nil {
+			continue
 		}
-		return &NotNullError{
-			Field:      fieldName,
-			Suggestion: "This field cannot be null",
+		if _, seen := pending[ref.Target.Name]; !seen {
+			targets = append(targets, ref.Target.Name)
 		}
+		pending[ref.Target.Name] = append(pending[ref.Target.Name], ref)
 	}
 
-	switch field.Type.Kind {
-	case "UUID":
-		str, ok := value.(string)
-		if !ok || !isValidUUID(str) {
-			return &FieldFormatError{
-				Field:      fieldName,
-				Format:     "UUID",
-				Value:      fmt.Sprintf("%v", value),
-				Suggestion: "Use uuid.New().String()",
+	var errs ValidationErrors
+	for _, name := range targets {
+		refs := pending[name]
+		target := refs[0].Target
+		pk := target.PrimaryKey()
+
+		placeholders := make([]string, len(refs))
+		args := make([]interface{}, len(refs))
+		for i, ref := range refs {
+			placeholders[i] = fmt.Sprintf("$%d", i+1)
+			args[i] = values[ref.Field]
+		}
+
+		rows, err := q.Query(ctx, fmt.Sprintf(
+			"SELECT %s FROM %s WHERE %s IN (%s)",
+			QuoteIdent(pk), QuoteIdent(EntityToTable(target.Name)), QuoteIdent(pk),
+			strings.Join(placeholders, ", "),
+		), args...)
+		if err != nil {
+			return fmt.Errorf("foreign key lookup on %s failed: %w", target.Name, err)
+		}
+
+		found := make(map[string]bool, len(rows))
+		for _, row := range rows {
+			found[keyString(row[pk])] = true
+		}
+
+		for _, ref := range refs {
+			value := values[ref.Field]
+			if found[keyString(value)] {
+				continue
 			}
+			errs.Add(&ConstraintError{
+				Type:       "foreign_key",
+				Entity:     ent.Name,
+				Field:      ref.Field,
+				Relation:   ref.Relation,
+				Value:      value,
+				Suggestion: fmt.Sprintf("No %s with %s = %v exists; create it first or fix '%s'", target.Name, pk, displayKey(value), ref.Field),
+			})
+		}
+	}
+
+	return errs.Err()
+}
+
+// foreignKeyRefs lists the FK fields of ent: its BelongsTo relations, and
+// the `via` keys of HasMany/HasOne relations on other entities pointing at it
+func (s *Schema) foreignKeyRefs(ent *Entity) []foreignKeyRef {
+	var refs []foreignKeyRef
+	seen := make(map[string]bool)
+
+	add := func(field, relation string, target *Entity) {
+		if seen[field] || target == nil {
+			return
+		}
+		if _, ok := ent.Fields[field]; !ok {
+			return
+		}
+		seen[field] = true
+		refs = append(refs, foreignKeyRef{Field: field, Relation: relation, Target: target})
+	}
+
+	for _, name := range sortedRelationNames(ent) {
+		rel := ent.Relations[name]
+		if rel.Kind != RelationBelongsTo {
+			continue
+		}
+		if fk, ok := s.ForeignKey(ent, rel); ok {
+			add(fk, name, s.GetEntity(rel.TargetEntity))
 		}
+	}
 
-	case "String":
-		if _, ok := value.(string); !ok {
-			return &TypeMismatchError{
-				Field:        fieldName,
-				ExpectedType: "string",
-				ReceivedType: fmt.Sprintf("%T", value),
-				Value:        value,
-				Suggestion:   "Pass a string",
+	for _, other := range s.Entities {
+		for _, name := range sortedRelationNames(other) {
+			rel := other.Relations[name]
+			if rel.TargetEntity != ent.Name || rel.ForeignKey == nil {
+				continue
+			}
+			if rel.Kind == RelationHasMany || rel.Kind == RelationHasOne {
+				add(*rel.ForeignKey, other.Name+"."+name, other)
 			}
 		}
 	}
 
-	return nil
+	return refs
+}
+
+func sortedRelationNames(ent *Entity) []string {
+	names := make([]string, 0, len(ent.Relations))
+	for name := range ent.Relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// keyString normalizes a key value so inputs and database rows compare equal
+// ("1111…" vs [16]byte, int vs int32)
+func keyString(value interface{}) string {
+	switch k := derefValue(value).(type) {
+	case [16]byte:
+		return uuid.UUID(k).String()
+	case uuid.UUID:
+		return k.String()
+	case pgtype.UUID:
+		return uuid.UUID(k.Bytes).String()
+	case string:
+		return strings.ToLower(k)
+	default:
+		return fmt.Sprint(k)
+	}
+}
+
+// displayKey renders UUID bytes readably in messages
+func displayKey(value interface{}) interface{} {
+	switch derefValue(value).(type) {
+	case [16]byte, uuid.UUID, pgtype.UUID:
+		return keyString(value)
+	}
+	return value
 }
 
 // ============================================================
-// FORMAT VALIDATION
+// CONSTRAINT VALIDATION
 // ============================================================
 
-func (v *Validator) validateFieldFormat(
+// validateConstraints enforces the field's schema constraints on a coerced
+// value. Check constraints are raw SQL and left to the database.
+func (v *Validator) validateConstraints(
+	field *Field,
 	fieldName string,
 	value interface{},
 ) error {
-	str, ok := value.(string)
-	if !ok || str == "" {
+	if value == nil {
 		return nil
 	}
 
-	if strings.Contains(strings.ToLower(fieldName), "email") {
-		if !isValidEmail(str) {
-			return &FieldFormatError{
-				Field:      fieldName,
-				Format:     "email",
-				Value:      str,
-				Suggestion: "Use a valid email address",
+	for _, c := range field.Constraints {
+		switch c.Kind {
+		case "Min", "Max":
+			if err := checkBound(c, fieldName, value); err != nil {
+				return err
+			}
+
+		case "Length":
+			str, ok := value.(string)
+			if !ok {
+				continue
+			}
+			n := utf8.RuneCountInString(str)
+			if c.MaxLen != nil && n > *c.MaxLen {
+				return &LengthExceededError{Field: fieldName, MaxLen: *c.MaxLen, Actual: n, Value: str}
+			}
+			if c.MinLen != nil && n < *c.MinLen {
+				return &ValidationError{
+					Field:    fieldName,
+					Type:     "length_too_short",
+					Value:    str,
+					Expected: fmt.Sprintf("at least %d characters", *c.MinLen),
+					Message:  fmt.Sprintf("length %d is below the minimum of %d", n, *c.MinLen),
+				}
+			}
+
+		case "Pattern":
+			str, ok := value.(string)
+			if !ok {
+				continue
+			}
+			re, err := compilePattern(c.Value)
+			if err != nil {
+				return &ValidationError{
+					Field:    fieldName,
+					Type:     "invalid_pattern",
+					Value:    c.Value,
+					Expected: "a valid regular expression",
+					Message:  err.Error(),
+				}
+			}
+			if !re.MatchString(str) {
+				return &FieldFormatError{
+					Field:      fieldName,
+					Format:     "pattern " + c.Value,
+					Value:      str,
+					Suggestion: fmt.Sprintf("Use a value matching %s", c.Value),
+				}
 			}
 		}
 	}
@@ -259,6 +536,90 @@ func (v *Validator) validateFieldFormat(
 	return nil
 }
 
+// checkBound compares a numeric value with a min/max bound
+func checkBound(c FieldConstraint, fieldName string, value interface{}) error {
+	bound, ok := new(big.Rat).SetString(c.Value)
+	if !ok {
+		return nil
+	}
+	n, ok := numericRat(value)
+	if !ok {
+		return nil
+	}
+
+	cmp := n.Cmp(bound)
+	if (c.Kind == "Min" && cmp >= 0) || (c.Kind == "Max" && cmp <= 0) {
+		return nil
+	}
+
+	op, word := ">=", "at least"
+	if c.Kind == "Max" {
+		op, word = "<=", "at most"
+	}
+	return &ValidationError{
+		Field:    fieldName,
+		Type:     "out_of_range",
+		Value:    value,
+		Expected: op + " " + c.Value,
+		Message:  fmt.Sprintf("value must be %s %s", word, c.Value),
+	}
+}
+
+// numericRat converts a coerced numeric value to an exact rational
+func numericRat(value interface{}) (*big.Rat, bool) {
+	switch n := value.(type) {
+	case string:
+		return new(big.Rat).SetString(n)
+	case pgtype.Numeric:
+		if !n.Valid || n.NaN || n.Int == nil {
+			return nil, false
+		}
+		r := new(big.Rat).SetInt(n.Int)
+		scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(n.Exp))), nil))
+		if n.Exp >= 0 {
+			return r.Mul(r, scale), true
+		}
+		return r.Quo(r, scale), true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch {
+	case isInt(rv.Kind()):
+		return new(big.Rat).SetInt64(rv.Int()), true
+	case isUint(rv.Kind()):
+		return new(big.Rat).SetInt(new(big.Int).SetUint64(rv.Uint())), true
+	case isFloat(rv.Kind()):
+		r := new(big.Rat)
+		if r.SetFloat64(rv.Float()) == nil {
+			return nil, false
+		}
+		return r, true
+	}
+	return nil, false
+}
+
+// patterns caches compiled Pattern constraints
+var patterns sync.Map // string → *regexp.Regexp
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(pattern, re)
+	return re, nil
+}
+
+func abs(n int32) int32 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
 // ============================================================
 // REQUIRED FIELDS
 // ============================================================
@@ -266,16 +627,38 @@ func (v *Validator) validateFieldFormat(
 func (v *Validator) validateRequiredFields(
 	ent *Entity,
 	provided map[string]interface{},
-) error {
+	errs *ValidationErrors,
+) {
 	for _, field := range ent.Fields {
 		if field.Nullable || field.Default != nil || field.PrimaryKey {
 			continue
 		}
 
 		if _, ok := provided[field.Name]; !ok {
-			return &NotNullError{
+			errs.Add(&NotNullError{
 				Field:      field.Name,
 				Suggestion: "This field is required",
+			})
+		}
+	}
+}
+
+// ValidateSelect checks that every selected name is a field of the entity
+func (v *Validator) ValidateSelect(entity string, fields []string) error {
+	ent := v.schema.GetEntity(entity)
+	if ent == nil {
+		return &UnknownEntityError{
+			Entity:    entity,
+			Available: v.getAvailableEntities(),
+		}
+	}
+
+	for _, name := range fields {
+		if _, ok := ent.Fields[name]; !ok {
+			return &UnknownFieldError{
+				Entity:    entity,
+				Field:     name,
+				Available: v.getAvailableFields(ent),
 			}
 		}
 	}
@@ -294,6 +677,14 @@ func (v *Validator) getAvailableFields(ent *Entity) []string {
 	return fields
 }
 
+func (v *Validator) getAvailableRelations(ent *Entity) []string {
+	var relations []string
+	for name := range ent.Relations {
+		relations = append(relations, name)
+	}
+	return relations
+}
+
 func (v *Validator) getAvailableEntities() []string {
 	var entities []string
 	for _, ent := range v.schema.Entities {
@@ -302,12 +693,17 @@ func (v *Validator) getAvailableEntities() []string {
 	return entities
 }
 
+// sortedFieldNames makes validation independent of map iteration order
+func sortedFieldNames(values map[string]interface{}) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func isValidUUID(s string) bool {
 	_, err := uuid.Parse(s)
 	return err == nil
 }
-
-func isValidEmail(s string) bool {
-	re := regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
-	return re.MatchString(s)
-}
@@ -0,0 +1,395 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func typesSchema() *Schema {
+	return &Schema{Entities: []*Entity{{
+		Name: "Item",
+		Fields: map[string]*Field{
+			"id":        {Name: "id", Type: FieldTypeUUID, PrimaryKey: true},
+			"name":      {Name: "name", Type: FieldTypeString},
+			"count":     {Name: "count", Type: FieldTypeInt, Nullable: true},
+			"ratio":     {Name: "ratio", Type: FieldTypeFloat, Nullable: true},
+			"price":     {Name: "price", Type: FieldTypeDecimal, Nullable: true},
+			"active":    {Name: "active", Type: FieldTypeBool, Nullable: true},
+			"seen_at":   {Name: "seen_at", Type: FieldTypeTimestamp, Nullable: true},
+			"embedding": {Name: "embedding", Type: FieldType{Kind: "Vector", Param: float64(3)}, Nullable: true},
+			"tags":      {Name: "tags", Type: FieldType{Kind: "Array", Param: "String"}, Nullable: true},
+			"dates":     {Name: "dates", Type: FieldType{Kind: "Array", Param: "Timestamp"}, Nullable: true},
+		},
+	}}}
+}
+
+func TestValidateFieldType_Accepts(t *testing.T) {
+	v := NewValidator(typesSchema(), DefaultValidatorConfig())
+	ent := v.schema.GetEntity("Item")
+	seen := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	count := int64(7)
+
+	cases := []struct {
+		field string
+		in    interface{}
+		want  interface{}
+	}{
+		{"id", "11111111-1111-1111-1111-111111111111", "11111111-1111-1111-1111-111111111111"},
+		{"id", [16]byte{1}, [16]byte{1}},
+		{"id", uuid.UUID{1}, uuid.UUID{1}},
+		{"count", 42, 42},
+		{"count", uint8(3), uint8(3)},
+		{"count", &count, int64(7)},
+		{"count", (*int64)(nil), nil},
+		{"ratio", 0.5, 0.5},
+		{"ratio", 2, 2},
+		{"price", 12.5, 12.5},
+		{"price", " 12.50 ", "12.50"},
+		{"price", big.NewRat(3, 8), "0.375"},
+		{"price", big.NewRat(10, 1), "10"},
+		{"price", big.NewInt(99), "99"},
+		{"active", true, true},
+		{"seen_at", seen, seen},
+		{"seen_at", "2026-01-02T03:04:05Z", seen},
+		{"embedding", []float32{0.5, 1, -2}, "[0.5,1,-2]"},
+		{"embedding", []float64{1, 2, 3}, "[1,2,3]"},
+		{"tags", []string{"a", "b"}, []string{"a", "b"}},
+		{"dates", []string{"2026-01-02T03:04:05Z"}, []time.Time{seen}},
+	}
+
+	for _, tc := range cases {
+		got, err := v.validateFieldType(ent.Fields[tc.field], tc.field, tc.in)
+		if err != nil {
+			t.Errorf("%s = %#v: unexpected error %v", tc.field, tc.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s = %#v: got %#v, want %#v", tc.field, tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestValidateFieldType_Rejects(t *testing.T) {
+	v := NewValidator(typesSchema(), DefaultValidatorConfig())
+	ent := v.schema.GetEntity("Item")
+
+	cases := []struct {
+		field    string
+		in       interface{}
+		expected string
+	}{
+		{"name", 42, "string"},
+		{"id", 42, "uuid"},
+		{"count", "42", "int"},
+		{"count", 4.2, "int"},
+		{"count", uint64(1) << 63, "int"},
+		{"ratio", "0.5", "float"},
+		{"price", "12,50", "decimal"},
+		{"price", big.NewRat(1, 3), "decimal"},
+		{"active", "yes", "bool"},
+		{"seen_at", "02/01/2026", "timestamp"},
+		{"seen_at", 1700000000, "timestamp"},
+		{"embedding", []float32{1, 2}, "vector(3)"},
+		{"embedding", "[1,2,3]", "vector(3)"},
+		{"tags", "a,b", "array of String"},
+		{"dates", []interface{}{"nope"}, "timestamp"},
+	}
+
+	for _, tc := range cases {
+		_, err := v.validateFieldType(ent.Fields[tc.field], tc.field, tc.in)
+		var mismatch *TypeMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Errorf("%s = %#v: expected TypeMismatchError, got %v", tc.field, tc.in, err)
+			continue
+		}
+		if mismatch.ExpectedType != tc.expected || mismatch.Suggestion == "" {
+			t.Errorf("%s = %#v: got expected type %q (suggestion %q), want %q",
+				tc.field, tc.in, mismatch.ExpectedType, mismatch.Suggestion, tc.expected)
+		}
+	}
+
+	_, err := v.validateFieldType(ent.Fields["id"], "id", "not-a-uuid")
+	var format *FieldFormatError
+	if !errors.As(err, &format) {
+		t.Errorf("Expected FieldFormatError for an invalid UUID string, got %v", err)
+	}
+}
+
+func TestValidateFieldType_Lenient(t *testing.T) {
+	v := NewValidator(typesSchema(), ValidatorConfig{StrictTypes: false})
+	ent := v.schema.GetEntity("Item")
+
+	cases := []struct {
+		field string
+		in    interface{}
+		want  interface{}
+	}{
+		{"count", "42", int64(42)},
+		{"count", 42.0, int64(42)},
+		{"count", float64(math.MinInt64), int64(math.MinInt64)},
+		{"count", math.Nextafter(1<<63, 0), int64(1<<63 - 1024)},
+		{"ratio", "0.5", 0.5},
+		{"active", "true", true},
+	}
+	for _, tc := range cases {
+		got, err := v.validateFieldType(ent.Fields[tc.field], tc.field, tc.in)
+		if err != nil || got != tc.want {
+			t.Errorf("%s = %#v: got %#v (%v), want %#v", tc.field, tc.in, got, err, tc.want)
+		}
+	}
+
+	if _, err := v.validateFieldType(ent.Fields["count"], "count", 4.2); err == nil {
+		t.Error("Expected non-integral float to be rejected even when not strict")
+	}
+	if _, err := v.validateFieldType(ent.Fields["count"], "count", float64(1<<63)); err == nil {
+		t.Error("Expected 2^63 to be rejected as overflowing int64")
+	}
+}
+
+func TestValidateInsertInput_CoercesInPlace(t *testing.T) {
+	v := NewValidator(typesSchema(), DefaultValidatorConfig())
+	values := map[string]interface{}{
+		"name":    "widget",
+		"seen_at": "2026-01-02T03:04:05Z",
+		"price":   big.NewRat(5, 2),
+	}
+
+	if err := v.ValidateInsertInput("Item", values); err != nil {
+		t.Fatalf("ValidateInsertInput failed: %v", err)
+	}
+	if _, ok := values["seen_at"].(time.Time); !ok {
+		t.Errorf("Expected seen_at coerced to time.Time, got %T", values["seen_at"])
+	}
+	if values["price"] != "2.5" {
+		t.Errorf("Expected price coerced to \"2.5\", got %#v", values["price"])
+	}
+}
+
+func TestValidateInsertInput_CollectsAllErrors(t *testing.T) {
+	v := NewValidator(typesSchema(), DefaultValidatorConfig())
+
+	for i := 0; i < 10; i++ {
+		err := v.ValidateInsertInput("Item", map[string]interface{}{
+			"count":   "many",
+			"seen_at": "yesterday",
+			"color":   "red",
+		})
+
+		var errs *ValidationErrors
+		if !errors.As(err, &errs) {
+			t.Fatalf("Expected ValidationErrors, got %v", err)
+		}
+
+		var fields []string
+		for _, issue := range errs.Issues() {
+			fields = append(fields, issue.Field+":"+issue.Code)
+		}
+		want := []string{"color:UNKNOWN_FIELD", "count:TYPE_MISMATCH", "name:NOT_NULL_VIOLATION", "seen_at:TYPE_MISMATCH"}
+		if !reflect.DeepEqual(fields, want) {
+			t.Fatalf("Issues = %v, want %v", fields, want)
+		}
+
+		var mismatch *TypeMismatchError
+		if !errors.As(err, &mismatch) || mismatch.Field != "count" {
+			t.Errorf("Expected errors.As to reach the first TypeMismatchError, got %v", mismatch)
+		}
+		if !IsConstraintError(err) {
+			t.Error("Expected IsConstraintError to see the collected NotNullError")
+		}
+	}
+}
+
+func TestValidationErrors_JSON(t *testing.T) {
+	v := NewValidator(typesSchema(), DefaultValidatorConfig())
+	err := v.ValidateUpdateInput("Item",
+		map[string]interface{}{"name": "widget"},
+		map[string]interface{}{"id": "11111111-1111-1111-1111-111111111111", "active": "yes"},
+	)
+
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+
+	data, jerr := json.Marshal(errs)
+	if jerr != nil {
+		t.Fatalf("Marshal failed: %v", jerr)
+	}
+	want := `{"code":"VALIDATION_ERRORS","errors":[` +
+		`{"code":"TYPE_MISMATCH","field":"active","message":"expected bool, got string","suggestion":"Pass true or false"},` +
+		`{"code":"PRIMARY_KEY_CONSTRAINT","entity":"Item","field":"id","message":"primary_key constraint violated","suggestion":"Primary keys cannot be updated"}]}`
+	if string(data) != want {
+		t.Errorf("JSON =\n%s\nwant\n%s", data, want)
+	}
+	if errs.Code() != "VALIDATION_ERRORS" || ErrorCode(err) != "VALIDATION_ERRORS" {
+		t.Errorf("Unexpected code: %s", errs.Code())
+	}
+}
+
+func TestValidateConstraints(t *testing.T) {
+	eng := NewEngine()
+	schema, err := eng.LoadSchemaFromString(`
+		entity Product {
+			id: uuid primary,
+			name: string length(2..5),
+			code: string nullable pattern("^[A-Z]{3}$"),
+			price: decimal min(0.01) max(100),
+			stock: int min(0),
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+	v := NewValidator(schema, DefaultValidatorConfig())
+
+	valid := map[string]interface{}{"name": "Añil", "code": "ABC", "price": "99.99", "stock": 0}
+	if err := v.ValidateInsertInput("Product", valid); err != nil {
+		t.Fatalf("Expected valid insert, got %v", err)
+	}
+
+	err = v.ValidateInsertInput("Product", map[string]interface{}{
+		"name":  "too long",
+		"code":  "abc",
+		"price": 0,
+		"stock": -1,
+	})
+	var errs *ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+
+	var got []string
+	for _, issue := range errs.Issues() {
+		got = append(got, issue.Field+":"+issue.Code)
+	}
+	want := []string{"code:FORMAT_ERROR", "name:LENGTH_EXCEEDED", "price:VALIDATION_ERROR", "stock:VALIDATION_ERROR"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Issues = %v, want %v", got, want)
+	}
+
+	var length *LengthExceededError
+	if !errors.As(err, &length) || length.MaxLen != 5 || length.Actual != 8 {
+		t.Errorf("Expected LengthExceededError(5, 8), got %v", length)
+	}
+
+	// Updates are checked too; NULL skips the constraints
+	err = v.ValidateUpdateInput("Product",
+		map[string]interface{}{"stock": 1},
+		map[string]interface{}{"name": "x", "code": nil, "price": big.NewRat(1, 200)},
+	)
+	got = nil
+	if errors.As(err, &errs) {
+		for _, issue := range errs.Issues() {
+			got = append(got, issue.Field+":"+issue.Code)
+		}
+	}
+	want = []string{"name:VALIDATION_ERROR", "price:VALIDATION_ERROR"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Update issues = %v, want %v (%v)", got, want, err)
+	}
+}
+
+func TestValidateInsertInput_NoEmailHeuristic(t *testing.T) {
+	v := NewValidator(typesSchema(), DefaultValidatorConfig())
+	schema := v.schema.GetEntity("Item")
+	schema.Fields["contact_email"] = &Field{Name: "contact_email", Type: FieldTypeString, Nullable: true}
+
+	values := map[string]interface{}{"name": "widget", "contact_email": "not an address"}
+	if err := v.ValidateInsertInput("Item", values); err != nil {
+		t.Errorf("Only declared constraints should apply, got %v", err)
+	}
+}
+
+// fkQuerier answers foreign key lookups from a fixed set of existing keys
+type fkQuerier struct {
+	existing map[string]bool
+	queries  []string
+}
+
+func (q *fkQuerier) Query(ctx context.Context, sql string, args ...interface{}) ([]map[string]interface{}, error) {
+	q.queries = append(q.queries, sql)
+	var rows []map[string]interface{}
+	for _, arg := range args {
+		if q.existing[keyString(arg)] {
+			rows = append(rows, map[string]interface{}{"id": arg})
+		}
+	}
+	return rows, nil
+}
+
+func (q *fkQuerier) Exec(ctx context.Context, sql string, args ...interface{}) (int64, error) {
+	return 0, nil
+}
+
+func TestValidateForeignKeys(t *testing.T) {
+	eng := NewEngine()
+	schema, err := eng.LoadSchemaFromString(`
+		entity User {
+			id: uuid primary,
+			posts: [Post] via author_id,
+			reviews: [Post] via reviewer_id,
+		}
+
+		entity Tag {
+			id: uuid primary,
+		}
+
+		entity Post {
+			id: uuid primary,
+			author_id: uuid,
+			reviewer_id: uuid nullable,
+			tag_id: uuid,
+			tag: Tag,
+		}
+	`)
+	if err != nil {
+		t.Fatalf("Failed to load schema: %v", err)
+	}
+
+	ana := "11111111-1111-1111-1111-111111111111"
+	missing := "99999999-9999-9999-9999-999999999999"
+	tag := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	q := &fkQuerier{existing: map[string]bool{ana: true, tag.String(): true}}
+	v := NewValidator(schema, DefaultValidatorConfig())
+
+	err = v.ValidateForeignKeys(context.Background(), q, "Post", map[string]interface{}{
+		"author_id":   ana,
+		"reviewer_id": missing,
+		"tag_id":      [16]byte(tag),
+	})
+
+	var constraint *ConstraintError
+	if !errors.As(err, &constraint) {
+		t.Fatalf("Expected ConstraintError, got %v", err)
+	}
+	if constraint.Type != "foreign_key" || constraint.Field != "reviewer_id" ||
+		constraint.Relation != "User.reviews" || constraint.Value != missing {
+		t.Errorf("Unexpected error: %+v", constraint)
+	}
+
+	// One lookup per referenced entity: users (author + reviewer) and tags
+	if len(q.queries) != 2 {
+		t.Fatalf("Expected 2 batched lookups, got %d: %v", len(q.queries), q.queries)
+	}
+	assertContains(t, q.queries[0], `SELECT "id" FROM "tags" WHERE "id" IN ($1)`)
+	assertContains(t, q.queries[1], `SELECT "id" FROM "users" WHERE "id" IN ($1, $2)`)
+
+	// NULL keys and disabled validation skip the lookup
+	q.queries = nil
+	if err := v.ValidateForeignKeys(context.Background(), q, "Post", map[string]interface{}{"reviewer_id": nil}); err != nil || len(q.queries) != 0 {
+		t.Errorf("Expected NULL key to be skipped, got %v (%d queries)", err, len(q.queries))
+	}
+
+	lenient := NewValidator(schema, ValidatorConfig{StrictTypes: true})
+	if err := lenient.ValidateForeignKeys(context.Background(), q, "Post", map[string]interface{}{"author_id": missing}); err != nil || len(q.queries) != 0 {
+		t.Errorf("Expected no lookup with ValidateFK disabled, got %v", err)
+	}
+}
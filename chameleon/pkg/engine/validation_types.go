@@ -0,0 +1,436 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ============================================================
+// FIELD TYPE VALIDATION
+// ============================================================
+//
+// validateFieldType checks a value against the field type and returns the
+// value to bind. Values the driver already encodes correctly are returned
+// unchanged; the others are converted:
+//
+//	Timestamp  "2026-01-02T03:04:05Z"  → time.Time
+//	Decimal    *big.Rat, *big.Int      → "12.50"
+//	Vector(n)  []float32, []float64    → "[0.1,0.2,...]"
+//	Array(T)   elements coerced as T
+//	any        *T                      → T (nil pointer → NULL)
+//
+// With StrictTypes disabled, numeric and boolean strings ("42", "true") and
+// integral floats into Int are accepted as well.
+
+func (v *Validator) validateFieldType(
+	field *Field,
+	fieldName string,
+	value interface{},
+) (interface{}, error) {
+	value = derefValue(value)
+
+	if value == nil {
+		if field.Nullable {
+			return nil, nil
+		}
+		return nil, &NotNullError{
+			Field:      fieldName,
+			Suggestion: "This field cannot be null",
+		}
+	}
+
+	return v.coerceValue(field.Type, fieldName, value)
+}
+
+func (v *Validator) coerceValue(ft FieldType, fieldName string, value interface{}) (interface{}, error) {
+	switch ft.Kind {
+	case "UUID":
+		return coerceUUID(fieldName, value)
+	case "String":
+		return coerceString(fieldName, value)
+	case "Int":
+		return v.coerceInt(fieldName, value)
+	case "Float":
+		return v.coerceFloat(fieldName, value)
+	case "Decimal":
+		return coerceDecimal(fieldName, value)
+	case "Bool":
+		return v.coerceBool(fieldName, value)
+	case "Timestamp":
+		return coerceTimestamp(fieldName, value)
+	case "Vector":
+		return coerceVector(ft, fieldName, value)
+	case "Array":
+		return v.coerceArray(ft, fieldName, value)
+	}
+
+	// Unknown kinds are left to the database
+	return value, nil
+}
+
+// ─────────────────────────────────────────────────────────────
+// Scalars
+// ─────────────────────────────────────────────────────────────
+
+func coerceUUID(fieldName string, value interface{}) (interface{}, error) {
+	switch u := value.(type) {
+	case string:
+		if !isValidUUID(u) {
+			return nil, &FieldFormatError{
+				Field:      fieldName,
+				Format:     "UUID",
+				Value:      u,
+				Suggestion: "Use uuid.New().String()",
+			}
+		}
+		return u, nil
+	case [16]byte, uuid.UUID:
+		return value, nil
+	case pgtype.UUID:
+		if u.Valid {
+			return u, nil
+		}
+	}
+
+	return nil, typeMismatch(fieldName, "uuid", value, "Pass a UUID string, uuid.UUID or [16]byte")
+}
+
+func coerceString(fieldName string, value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	// Named string types (type Status string)
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+
+	return nil, typeMismatch(fieldName, "string", value, "Pass a string")
+}
+
+func (v *Validator) coerceInt(fieldName string, value interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(value)
+
+	switch {
+	case isInt(rv.Kind()):
+		return value, nil
+	case isUint(rv.Kind()):
+		if rv.Uint() > math.MaxInt64 {
+			return nil, typeMismatch(fieldName, "int", value, "Value overflows a 64-bit integer")
+		}
+		return value, nil
+	}
+
+	if !v.config.StrictTypes {
+		switch n := value.(type) {
+		case float32, float64:
+			// float64(math.MaxInt64) is 2^63, one past the largest int64
+			f := rv.Float()
+			if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+				return int64(f), nil
+			}
+		case string:
+			if i, err := strconv.ParseInt(strings.TrimSpace(n), 10, 64); err == nil {
+				return i, nil
+			}
+		}
+	}
+
+	suggestion := "Pass an integer (int, int32, int64)"
+	if isFloat(rv.Kind()) {
+		suggestion = "Round the value and convert it with int64(...)"
+	} else if rv.Kind() == reflect.String {
+		suggestion = "Parse the string with strconv.ParseInt"
+	}
+	return nil, typeMismatch(fieldName, "int", value, suggestion)
+}
+
+func (v *Validator) coerceFloat(fieldName string, value interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(value)
+
+	switch {
+	case isFloat(rv.Kind()), isInt(rv.Kind()), isUint(rv.Kind()):
+		return value, nil
+	}
+
+	if !v.config.StrictTypes {
+		if s, ok := value.(string); ok {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+				return f, nil
+			}
+		}
+	}
+
+	return nil, typeMismatch(fieldName, "float", value, "Pass a float64 (or any number)")
+}
+
+var decimalPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+func coerceDecimal(fieldName string, value interface{}) (interface{}, error) {
+	rv := reflect.ValueOf(value)
+
+	switch {
+	case isInt(rv.Kind()), isUint(rv.Kind()):
+		return value, nil
+	case isFloat(rv.Kind()):
+		f := rv.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, typeMismatch(fieldName, "decimal", value, "NaN and Inf are not valid decimals")
+		}
+		return value, nil
+	}
+
+	switch d := value.(type) {
+	case string:
+		if decimalPattern.MatchString(strings.TrimSpace(d)) {
+			return strings.TrimSpace(d), nil
+		}
+		return nil, typeMismatch(fieldName, "decimal", value, `Use a decimal string like "12.50"`)
+	case big.Rat:
+		if s, ok := ratToDecimal(&d); ok {
+			return s, nil
+		}
+		return nil, typeMismatch(fieldName, "decimal", value, "Round the fraction first, e.g. r.FloatString(2)")
+	case big.Int:
+		return d.String(), nil
+	case pgtype.Numeric:
+		if d.Valid {
+			return d, nil
+		}
+	}
+
+	return nil, typeMismatch(fieldName, "decimal", value, `Pass a number, a decimal string ("12.50") or *big.Rat`)
+}
+
+// ratToDecimal renders r exactly, failing for non-terminating fractions (1/3)
+func ratToDecimal(r *big.Rat) (string, bool) {
+	if r.IsInt() {
+		return r.Num().String(), true
+	}
+
+	// r terminates iff its denominator is 2^a·5^b; it then needs max(a, b) digits
+	den := new(big.Int).Set(r.Denom())
+	digits := 0
+	for _, p := range []int64{2, 5} {
+		n, mod := 0, new(big.Int)
+		for {
+			q, m := new(big.Int).QuoRem(den, big.NewInt(p), mod)
+			if m.Sign() != 0 {
+				break
+			}
+			den, n = q, n+1
+		}
+		digits = max(digits, n)
+	}
+	if den.Cmp(big.NewInt(1)) != 0 {
+		return "", false
+	}
+	return r.FloatString(digits), true
+}
+
+func (v *Validator) coerceBool(fieldName string, value interface{}) (interface{}, error) {
+	if reflect.ValueOf(value).Kind() == reflect.Bool {
+		return value, nil
+	}
+
+	if !v.config.StrictTypes {
+		if s, ok := value.(string); ok {
+			if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+				return b, nil
+			}
+		}
+	}
+
+	return nil, typeMismatch(fieldName, "bool", value, "Pass true or false")
+}
+
+func coerceTimestamp(fieldName string, value interface{}) (interface{}, error) {
+	switch t := value.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(t))
+		if err == nil {
+			return parsed, nil
+		}
+		return nil, typeMismatch(fieldName, "timestamp", value, `Use an RFC3339 string like "2026-01-02T15:04:05Z"`)
+	case pgtype.Timestamptz:
+		if t.Valid {
+			return t, nil
+		}
+	case pgtype.Timestamp:
+		if t.Valid {
+			return t, nil
+		}
+	}
+
+	return nil, typeMismatch(fieldName, "timestamp", value, "Pass a time.Time or an RFC3339 string")
+}
+
+// ─────────────────────────────────────────────────────────────
+// Vector and Array
+// ─────────────────────────────────────────────────────────────
+
+// coerceVector checks the dimension and renders the pgvector text form
+func coerceVector(ft FieldType, fieldName string, value interface{}) (interface{}, error) {
+	dim := vectorDimension(ft)
+	expected := fmt.Sprintf("vector(%d)", dim)
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, typeMismatch(fieldName, expected, value, "Pass a []float32 or []float64")
+	}
+	if dim > 0 && rv.Len() != dim {
+		return nil, &TypeMismatchError{
+			Field:        fieldName,
+			ExpectedType: expected,
+			ReceivedType: fmt.Sprintf("vector(%d)", rv.Len()),
+			Value:        value,
+			Suggestion:   fmt.Sprintf("Pass exactly %d components", dim),
+		}
+	}
+
+	parts := make([]string, rv.Len())
+	for i := range parts {
+		elem := rv.Index(i)
+		switch {
+		case isFloat(elem.Kind()):
+			f := elem.Float()
+			if math.IsNaN(f) || math.IsInf(f, 0) {
+				return nil, typeMismatch(fieldName, expected, value, "NaN and Inf are not valid vector components")
+			}
+			parts[i] = strconv.FormatFloat(f, 'g', -1, 64)
+		case isInt(elem.Kind()):
+			parts[i] = strconv.FormatInt(elem.Int(), 10)
+		default:
+			return nil, typeMismatch(fieldName, expected, value, "Pass a []float32 or []float64")
+		}
+	}
+
+	return "[" + strings.Join(parts, ",") + "]", nil
+}
+
+// coerceArray coerces every element as the array's element type
+func (v *Validator) coerceArray(ft FieldType, fieldName string, value interface{}) (interface{}, error) {
+	elemType, ok := arrayElementType(ft)
+	if !ok {
+		return value, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, typeMismatch(fieldName, "array of "+elemType.String(), value,
+			fmt.Sprintf("Pass a slice of %s values", strings.ToLower(elemType.Kind)))
+	}
+
+	out := make([]interface{}, rv.Len())
+	changed := false
+	for i := range out {
+		elem := derefValue(rv.Index(i).Interface())
+		if elem == nil {
+			continue // NULL elements are allowed in Postgres arrays
+		}
+		coerced, err := v.coerceValue(elemType, fmt.Sprintf("%s[%d]", fieldName, i), elem)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = coerced
+		changed = changed || !reflect.DeepEqual(coerced, rv.Index(i).Interface())
+	}
+
+	if !changed {
+		return value, nil
+	}
+	return typedSlice(out), nil
+}
+
+// typedSlice turns []interface{} into []T when every element is a T
+func typedSlice(values []interface{}) interface{} {
+	var elem reflect.Type
+	for _, v := range values {
+		if v == nil {
+			return values
+		}
+		if elem == nil {
+			elem = reflect.TypeOf(v)
+		} else if reflect.TypeOf(v) != elem {
+			return values
+		}
+	}
+	if elem == nil {
+		return values
+	}
+
+	out := reflect.MakeSlice(reflect.SliceOf(elem), len(values), len(values))
+	for i, v := range values {
+		out.Index(i).Set(reflect.ValueOf(v))
+	}
+	return out.Interface()
+}
+
+// vectorDimension reads n from Vector(n); 0 if unknown
+func vectorDimension(ft FieldType) int {
+	switch n := ft.Param.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	}
+	return 0
+}
+
+// arrayElementType reads T from Array(T): "String" or {"Vector": 3}
+func arrayElementType(ft FieldType) (FieldType, bool) {
+	switch p := ft.Param.(type) {
+	case string:
+		return FieldType{Kind: p}, true
+	case FieldType:
+		return p, true
+	case map[string]interface{}:
+		for kind, param := range p {
+			return FieldType{Kind: kind, Param: param}, true
+		}
+	}
+	return FieldType{}, false
+}
+
+// ─────────────────────────────────────────────────────────────
+// Helpers
+// ─────────────────────────────────────────────────────────────
+
+// derefValue follows pointers (*int64, *time.Time); a nil pointer is NULL
+func derefValue(value interface{}) interface{} {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+func typeMismatch(fieldName, expected string, value interface{}, suggestion string) *TypeMismatchError {
+	return &TypeMismatchError{
+		Field:        fieldName,
+		ExpectedType: expected,
+		ReceivedType: fmt.Sprintf("%T", value),
+		Value:        value,
+		Suggestion:   suggestion,
+	}
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uint64
+}
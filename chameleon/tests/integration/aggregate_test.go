@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+)
+
+func TestAggregateCount(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	result, err := eng.Query("User").
+		Filter("age", "gte", 18).
+		Count().
+		Aggregate(ctx)
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+
+	// Charlie has no age
+	if got := result.First().Count(); got != 2 {
+		t.Errorf("Expected 2 users, got %d", got)
+	}
+}
+
+func TestAggregateGroupBy(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	result, err := eng.Query("Order").
+		GroupBy("status").
+		Count().
+		Sum("total").
+		Max("total").
+		OrderBy("status", "asc").
+		Aggregate(ctx)
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+
+	if len(result.Groups) != 2 {
+		t.Fatalf("Expected 2 groups, got %d", len(result.Groups))
+	}
+
+	completed := result.Groups[0]
+	if completed.Key.String("status") != "completed" {
+		t.Fatalf("Expected completed group first, got %v", completed.Key)
+	}
+	if completed.Count() != 2 {
+		t.Errorf("Expected 2 completed orders, got %d", completed.Count())
+	}
+	if completed.Sum("total") != 350.50 {
+		t.Errorf("Expected completed total 350.50, got %v", completed.Sum("total"))
+	}
+	if completed.Max("total") != 200.0 {
+		t.Errorf("Expected max 200, got %#v", completed.Max("total"))
+	}
+}
+
+func TestAggregateOverRelationWithHaving(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	// Ana: 150.50 + 75.00, Bob: 200.00, Charlie: no orders
+	result, err := eng.Query("User").
+		GroupBy("email").
+		Sum("orders.total").
+		Having(engine.Cond("sum_orders_total", "gt", 210)).
+		Aggregate(ctx)
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+
+	if len(result.Groups) != 1 {
+		t.Fatalf("Expected 1 group, got %d", len(result.Groups))
+	}
+	if result.Groups[0].Key.String("email") != "ana@mail.com" {
+		t.Errorf("Expected ana@mail.com, got %v", result.Groups[0].Key)
+	}
+	if result.Groups[0].Sum("orders.total") != 225.50 {
+		t.Errorf("Expected 225.50, got %v", result.Groups[0].Sum("orders.total"))
+	}
+}
+
+func TestAggregateRootAndRelationFields(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	// Ana has two orders: her age must still count once
+	result, err := eng.Query("User").
+		Count().
+		Sum("age").
+		Sum("orders.total").
+		Avg("orders.total").
+		Aggregate(ctx)
+	if err != nil {
+		t.Fatalf("Aggregate failed: %v", err)
+	}
+
+	group := result.First()
+	if group.Count() != 3 {
+		t.Errorf("Expected 3 users, got %d", group.Count())
+	}
+	if group.Sum("age") != 55 {
+		t.Errorf("Expected ages to sum to 55, got %v", group.Sum("age"))
+	}
+	if group.Sum("orders.total") != 425.50 {
+		t.Errorf("Expected orders to sum to 425.50, got %v", group.Sum("orders.total"))
+	}
+	if avg := group.Avg("orders.total"); avg < 141.83 || avg > 141.84 {
+		t.Errorf("Expected an average order of 141.83, got %v", avg)
+	}
+}
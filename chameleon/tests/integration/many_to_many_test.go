@@ -0,0 +1,186 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+	_ "github.com/chameleon-db/chameleondb/chameleon/pkg/engine/mutation"
+)
+
+const (
+	postGo   = "10000000-0000-0000-0000-000000000001"
+	postRust = "10000000-0000-0000-0000-000000000002"
+	tagGo    = "20000000-0000-0000-0000-000000000001"
+	tagRust  = "20000000-0000-0000-0000-000000000002"
+	tagDB    = "20000000-0000-0000-0000-000000000003"
+)
+
+// setupManyToMany migrates the Post ←→ Tag fixture and links:
+//
+//	"Go tips"   → go, db
+//	"Rust FFI"  → rust, db
+func setupManyToMany(t *testing.T) (*engine.Engine, context.Context, func()) {
+	t.Helper()
+
+	eng, ctx, cleanup := setupTestDBWithSchema(t, "../fixtures/m2m_schema.cham")
+	runMigration(t, eng, ctx)
+
+	inserts := []struct {
+		entity string
+		values map[string]interface{}
+	}{
+		{"Post", map[string]interface{}{"id": postGo, "title": "Go tips"}},
+		{"Post", map[string]interface{}{"id": postRust, "title": "Rust FFI"}},
+		{"Tag", map[string]interface{}{"id": tagGo, "name": "go"}},
+		{"Tag", map[string]interface{}{"id": tagRust, "name": "rust"}},
+		{"Tag", map[string]interface{}{"id": tagDB, "name": "db"}},
+	}
+	for _, ins := range inserts {
+		m := eng.Insert(ins.entity)
+		for field, value := range ins.values {
+			m = m.Set(field, value)
+		}
+		if _, err := m.Execute(ctx); err != nil {
+			cleanup()
+			t.Fatalf("Failed to insert %s: %v", ins.entity, err)
+		}
+	}
+
+	if _, err := eng.Link("Post", "tags", postGo).Connect(tagGo, tagDB).Execute(ctx); err != nil {
+		cleanup()
+		t.Fatalf("Failed to link Go post: %v", err)
+	}
+	if _, err := eng.Link("Post", "tags", postRust).Connect(tagRust, tagDB).Execute(ctx); err != nil {
+		cleanup()
+		t.Fatalf("Failed to link Rust post: %v", err)
+	}
+
+	return eng, ctx, cleanup
+}
+
+func TestManyToManyInclude(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupManyToMany(t)
+	defer cleanup()
+
+	result, err := eng.Query("Post").
+		Include("tags").
+		OrderBy("title", "asc").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	expected := map[string][]string{
+		"Go tips":  {"db", "go"},
+		"Rust FFI": {"db", "rust"},
+	}
+	for _, post := range result.Rows {
+		tags := post.Many("tags")
+		want := expected[post.String("title")]
+		if len(tags) != len(want) {
+			t.Fatalf("Expected %d tags for %s, got %d", len(want), post.String("title"), len(tags))
+		}
+		names := map[string]bool{}
+		for _, tag := range tags {
+			names[tag.String("name")] = true
+			if _, leaked := tag["__parent_key"]; leaked {
+				t.Error("Join key alias should not leak into tag rows")
+			}
+		}
+		for _, name := range want {
+			if !names[name] {
+				t.Errorf("Expected tag %s on %s", name, post.String("title"))
+			}
+		}
+	}
+}
+
+func TestManyToManyFilterOnRelation(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupManyToMany(t)
+	defer cleanup()
+
+	result, err := eng.Query("Post").
+		Filter("tags.name", "eq", "go").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if result.Count() != 1 || result.Rows[0].String("title") != "Go tips" {
+		t.Errorf("Expected only 'Go tips', got %v", result.Rows)
+	}
+
+	// Shared tag must not duplicate posts
+	shared, err := eng.Query("Post").
+		Filter("tags.name", "eq", "db").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if shared.Count() != 2 {
+		t.Errorf("Expected 2 posts tagged db, got %d", shared.Count())
+	}
+}
+
+func TestManyToManyDisconnect(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupManyToMany(t)
+	defer cleanup()
+
+	result, err := eng.Link("Post", "tags", postGo).
+		Disconnect(tagDB).
+		Connect(tagRust).
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+	if result.Disconnected != 1 || result.Connected != 1 {
+		t.Errorf("Expected 1 disconnected / 1 connected, got %+v", result)
+	}
+
+	// Inverse side sees the change
+	tags, err := eng.Query("Tag").
+		Filter("name", "eq", "db").
+		Include("posts").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if got := len(tags.Rows[0].Many("posts")); got != 1 {
+		t.Errorf("Expected db tag on 1 post after disconnect, got %d", got)
+	}
+}
+
+func TestManyToManyConnectTwice(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupManyToMany(t)
+	defer cleanup()
+
+	// Already linked by setupManyToMany: the join table's unique key
+	// turns the insert into a no-op
+	result, err := eng.Link("Post", "tags", postGo).Connect(tagGo).Execute(ctx)
+	if err != nil {
+		t.Fatalf("Link failed: %v", err)
+	}
+	if result.Connected != 0 {
+		t.Errorf("Expected an existing link not to be inserted again, got %+v", result)
+	}
+
+	posts, err := eng.Query("Post").
+		Filter("title", "eq", "Go tips").
+		Include("tags").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if got := len(posts.Rows[0].Many("tags")); got != 2 {
+		t.Errorf("Expected 2 tags on 'Go tips', got %d", got)
+	}
+}
@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"testing"
+
+	_ "github.com/chameleon-db/chameleondb/chameleon/pkg/engine/mutation"
+)
+
+func TestMutationInsertReturnsRecord(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+
+	result, err := eng.Insert("User").
+		Set("id", "44444444-4444-4444-4444-444444444444").
+		Set("email", "dana@mail.com").
+		Set("name", "Dana").
+		Set("age", 41).
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Insert failed: %v", err)
+	}
+
+	if result.Affected != 1 {
+		t.Errorf("Expected 1 affected row, got %d", result.Affected)
+	}
+	if result.ID == nil {
+		t.Error("Expected ID from RETURNING")
+	}
+	if result.Record["email"] != "dana@mail.com" {
+		t.Errorf("Expected email in record, got %v", result.Record["email"])
+	}
+
+	check, err := eng.Query("User").Filter("email", "eq", "dana@mail.com").Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if check.Count() != 1 {
+		t.Errorf("Expected inserted user to be queryable, got %d rows", check.Count())
+	}
+}
+
+func TestMutationUpdateReturnsRecords(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	result, err := eng.Update("Order").
+		Filter("status", "eq", "completed").
+		Set("status", "archived").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	if result.Affected != 2 {
+		t.Errorf("Expected 2 updated orders, got %d", result.Affected)
+	}
+	for _, record := range result.Records {
+		if record["status"] != "archived" {
+			t.Errorf("Expected status 'archived', got %v", record["status"])
+		}
+	}
+}
+
+func TestMutationDeleteAffectedRows(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	result, err := eng.Delete("OrderItem").
+		Filter("quantity", "gte", 2).
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if result.Affected != 2 {
+		t.Errorf("Expected 2 deleted items, got %d", result.Affected)
+	}
+}
@@ -1,7 +1,13 @@
 package integration
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+	"github.com/jackc/pgx/v5"
 )
 
 // This test do not can done because the current implementation of the query does not support null values in filters.
@@ -311,8 +317,8 @@ func TestQueryNestedInclude(t *testing.T) {
 		t.Fatal("Expected at least 1 order")
 	}
 
-	// Check items loaded
-	items, ok := result.Relations["items"]
+	// Check items loaded (keyed by full include path)
+	items, ok := result.Relations["orders.items"]
 	if !ok {
 		t.Fatal("items relation not loaded")
 	}
@@ -320,6 +326,110 @@ func TestQueryNestedInclude(t *testing.T) {
 	if len(items) < 1 {
 		t.Error("Expected at least 1 item for ana's orders")
 	}
+
+	// Items are stitched onto the order they belong to
+	for _, order := range result.Rows[0].Many("orders") {
+		for _, item := range order.Many("items") {
+			if item.String("order_id") != order.String("id") {
+				t.Errorf("Item %v attached to wrong order %v", item["id"], order["id"])
+			}
+		}
+	}
+}
+
+func TestQueryIncludeStitchesPerParent(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	result, err := eng.Query("User").
+		Include("orders").
+		OrderBy("email", "asc").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	expected := map[string]int{
+		"ana@mail.com":     2,
+		"bob@mail.com":     1,
+		"charlie@mail.com": 0,
+	}
+	for _, user := range result.Rows {
+		email := user.String("email")
+		if got := len(user.Many("orders")); got != expected[email] {
+			t.Errorf("Expected %d orders for %s, got %d", expected[email], email, got)
+		}
+	}
+}
+
+func TestQueryIncludeBelongsTo(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	result, err := eng.Query("OrderItem").
+		Include("order.user").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if result.Count() != 3 {
+		t.Fatalf("Expected 3 items, got %d", result.Count())
+	}
+
+	// All seeded items belong to ana's orders
+	for _, item := range result.Rows {
+		order := item.One("order")
+		if order == nil {
+			t.Fatalf("Item %v has no order", item["id"])
+		}
+		if order.One("user").String("email") != "ana@mail.com" {
+			t.Errorf("Expected order owner ana@mail.com, got %v", order["user"])
+		}
+	}
+}
+
+func TestQuerySelectWithInclude(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	result, err := eng.Query("User").
+		Select("email").
+		Filter("email", "eq", "ana@mail.com").
+		Include("orders", "total").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if result.Count() != 1 {
+		t.Fatalf("Expected 1 user, got %d", result.Count())
+	}
+
+	ana := result.Rows[0]
+	if _, ok := ana["name"]; ok {
+		t.Error("Expected unselected field 'name' to be absent")
+	}
+	if orders := ana.Many("orders"); len(orders) != 2 {
+		t.Errorf("Expected 2 orders stitched onto ana, got %d", len(orders))
+	} else if _, ok := orders[0]["status"]; ok {
+		t.Error("Expected unselected order field 'status' to be absent")
+	}
 }
 
 func TestQueryFilterOnRelation(t *testing.T) {
@@ -352,3 +462,417 @@ func TestQueryFilterOnRelation(t *testing.T) {
 		}
 	}
 }
+
+func TestQueryWhereOr(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	// completed orders, or pending orders under 100 → all 3 fixture orders
+	result, err := eng.Query("Order").
+		Where(engine.Or(
+			engine.Cond("status", "eq", "completed"),
+			engine.And(
+				engine.Cond("status", "eq", "pending"),
+				engine.Cond("total", "lt", 100),
+			),
+		)).
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if result.Count() != 3 {
+		t.Errorf("Expected 3 orders, got %d", result.Count())
+	}
+}
+
+func TestQueryWhereNot(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	result, err := eng.Query("Order").
+		Where(engine.Not(engine.Cond("status", "eq", "completed"))).
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if result.Count() != 1 {
+		t.Fatalf("Expected 1 non-completed order, got %d", result.Count())
+	}
+	if result.Rows[0].String("status") != "pending" {
+		t.Errorf("Expected pending order, got %s", result.Rows[0].String("status"))
+	}
+}
+
+func TestQueryWhereRelationInOr(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	// Charlie has no orders but matches by name
+	result, err := eng.Query("User").
+		Where(engine.Or(
+			engine.Cond("name", "eq", "Charlie Brown"),
+			engine.Cond("orders.total", "gt", 180),
+		)).
+		OrderBy("email", "asc").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if result.Count() != 2 {
+		t.Fatalf("Expected 2 users, got %d", result.Count())
+	}
+	if result.Rows[0].String("email") != "bob@mail.com" || result.Rows[1].String("email") != "charlie@mail.com" {
+		t.Errorf("Unexpected users: %s, %s", result.Rows[0].String("email"), result.Rows[1].String("email"))
+	}
+}
+
+func TestQueryStream(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	var emails []string
+	err := eng.Query("User").
+		OrderBy("email", "asc").
+		Stream(ctx, func(row engine.Row) error {
+			emails = append(emails, row.String("email"))
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+
+	if len(emails) != 3 || emails[0] != "ana@mail.com" {
+		t.Errorf("Unexpected rows: %v", emails)
+	}
+}
+
+func TestQueryStreamEarlyStop(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	stop := errors.New("stop")
+	seen := 0
+	err := eng.Query("User").Stream(ctx, func(engine.Row) error {
+		seen++
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("Expected callback error, got %v", err)
+	}
+	if seen != 1 {
+		t.Errorf("Expected 1 row before stopping, got %d", seen)
+	}
+
+	// Breaking out of the iterator stops it too
+	seen = 0
+	for _, err := range eng.Query("User").Iter(ctx) {
+		if err != nil {
+			t.Fatalf("Iter failed: %v", err)
+		}
+		seen++
+		break
+	}
+	if seen != 1 {
+		t.Errorf("Expected 1 row before break, got %d", seen)
+	}
+}
+
+func TestQueryStreamCancelled(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	err := eng.Query("User").Stream(cancelCtx, func(engine.Row) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestQueryStreamEarlyStopLargeResult(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+
+	conn, err := pgx.Connect(ctx, testConfig().ConnectionString())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	_, err = conn.Exec(ctx, `
+		INSERT INTO users (id, email, name, age, created_at)
+		SELECT md5(i::text)::uuid, 'user' || i || '@mail.com', 'User ' || i, i % 90, NOW()
+		FROM generate_series(1, 200000) AS i
+	`)
+	conn.Close(ctx)
+	if err != nil {
+		t.Fatalf("Failed to insert users: %v", err)
+	}
+
+	start := time.Now()
+	total := 0
+	err = eng.Query("User").Stream(ctx, func(engine.Row) error {
+		total++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream failed: %v", err)
+	}
+	full := time.Since(start)
+	if total != 200000 {
+		t.Fatalf("Expected 200000 rows, got %d", total)
+	}
+
+	// Breaking after one row must not read the other 199999
+	start = time.Now()
+	for _, err := range eng.Query("User").Iter(ctx) {
+		if err != nil {
+			t.Fatalf("Iter failed: %v", err)
+		}
+		break
+	}
+	if early := time.Since(start); early > full/2 {
+		t.Errorf("Breaking early took %v, reading everything %v", early, full)
+	}
+
+	// The cancelled connection is replaced; the engine keeps working
+	result, err := eng.Query("User").Filter("email", "eq", "user1@mail.com").Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query after early stop failed: %v", err)
+	}
+	if result.Count() != 1 {
+		t.Errorf("Expected 1 user, got %d", result.Count())
+	}
+}
+
+func TestQueryStreamIncludesInChunks(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	orders := map[string]int{}
+	for row, err := range eng.Query("User").Include("orders").ChunkSize(1).Iter(ctx) {
+		if err != nil {
+			t.Fatalf("Iter failed: %v", err)
+		}
+		orders[row.String("email")] = len(row.Many("orders"))
+	}
+
+	want := map[string]int{"ana@mail.com": 2, "bob@mail.com": 1, "charlie@mail.com": 0}
+	for email, n := range want {
+		if orders[email] != n {
+			t.Errorf("Expected %d orders for %s, got %d", n, email, orders[email])
+		}
+	}
+
+	// Same inside a transaction
+	err := eng.Tx(ctx, func(tx *engine.Tx) error {
+		count := 0
+		err := tx.Query("User").Include("orders").ChunkSize(2).Stream(ctx, func(engine.Row) error {
+			count++
+			return nil
+		})
+		if count != 3 {
+			t.Errorf("Expected 3 users in transaction, got %d", count)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Tx stream failed: %v", err)
+	}
+}
+
+func TestQueryExplain(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	plans, err := eng.Query("User").
+		Filter("email", "eq", "ana@mail.com").
+		Include("orders").
+		Include("orders.items").
+		Explain(ctx, true)
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+
+	if plans.Main == nil || plans.Main.Root == nil {
+		t.Fatal("main plan missing")
+	}
+	if !plans.Analyzed || plans.Main.ExecutionTime <= 0 {
+		t.Errorf("expected ANALYZE timings, got %+v", plans.Main)
+	}
+
+	if len(plans.Eager) != 2 || plans.Eager[0].Path != "orders" || plans.Eager[1].Path != "orders.items" {
+		t.Fatalf("expected plans for orders and orders.items, got %d", len(plans.Eager))
+	}
+	for _, plan := range plans.Eager {
+		if plan.Root == nil || plan.Root.NodeType == "" {
+			t.Errorf("eager plan %s has no root node", plan.Path)
+		}
+	}
+}
+
+// recordingHook collects completed query events
+type recordingHook struct {
+	events []engine.QueryEvent
+}
+
+func (h *recordingHook) BeforeQuery(ctx context.Context, event *engine.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *recordingHook) AfterQuery(ctx context.Context, event *engine.QueryEvent) {
+	h.events = append(h.events, *event)
+}
+
+func TestQueryHooks(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	hook := &recordingHook{}
+	eng.AddHook(hook)
+
+	result, err := eng.Query("User").
+		Filter("email", "eq", "ana@mail.com").
+		Include("orders").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	if len(hook.events) != 2 {
+		t.Fatalf("Expected main and eager events, got %d", len(hook.events))
+	}
+
+	main, eager := hook.events[0], hook.events[1]
+	if main.Operation != engine.OpQuery || main.Entity != "User" || main.Rows != len(result.Rows) {
+		t.Errorf("Unexpected main event: %+v", main)
+	}
+	if eager.Operation != engine.OpEager || eager.Entity != "Order" || eager.Path != "orders" {
+		t.Errorf("Unexpected eager event: %+v", eager)
+	}
+	if eager.Rows != len(result.Relations["orders"]) || len(eager.Args) != 1 {
+		t.Errorf("Eager event should carry the rows and the parent keys: %+v", eager)
+	}
+}
+
+func TestQueryProfile(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	result, err := eng.Query("User").
+		Include("orders").
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+
+	p := result.Profile
+	if p == nil {
+		t.Fatal("Expected a profile on the result")
+	}
+	if p.Generate <= 0 || p.Main.Database <= 0 || p.Total <= 0 {
+		t.Errorf("Expected timings for every phase: %+v", p)
+	}
+	if p.Main.Rows != len(result.Rows) {
+		t.Errorf("Expected %d main rows in profile, got %d", len(result.Rows), p.Main.Rows)
+	}
+	if len(p.Eager) != 1 || p.Eager[0].Path != "orders" {
+		t.Errorf("Expected one eager profile for orders, got %+v", p.Eager)
+	}
+}
+
+func TestQueryCache(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	for _, email := range []string{"ana@mail.com", "bob@mail.com"} {
+		result, err := eng.Query("User").
+			Filter("email", "eq", email).
+			Execute(ctx)
+		if err != nil {
+			t.Fatalf("Query failed: %v", err)
+		}
+		if len(result.Rows) != 1 || result.Rows[0].String("email") != email {
+			t.Errorf("Expected the user %s, got %v", email, result.Rows)
+		}
+	}
+
+	stats := eng.QueryCacheStats()
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Expected one miss then one hit, got %+v", stats)
+	}
+
+	// LIKE and IN values are bound too
+	result, err := eng.Query("User").
+		Filter("name", "like", "o").
+		Filter("age", "in", []int{25, 30}).
+		Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(result.Rows) != 1 || result.Rows[0].String("email") != "bob@mail.com" {
+		t.Errorf("Expected only Bob, got %v", result.Rows)
+	}
+}
@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+)
+
+type scanOrder struct {
+	ID     string  `cham:"id"`
+	Total  float64 `cham:"total"`
+	Status string  `cham:"status"`
+}
+
+type scanUser struct {
+	ID        string      `cham:"id"`
+	Email     string      `cham:"email"`
+	Age       *int        `cham:"age"`
+	CreatedAt time.Time   `cham:"created_at"`
+	Orders    []scanOrder `cham:"orders"`
+}
+
+func TestQueryAsWithInclude(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	q := engine.QueryAs[scanUser](eng, "User")
+	q.Include("orders").OrderBy("email", "asc")
+
+	users, err := q.All(ctx)
+	if err != nil {
+		t.Fatalf("QueryAs failed: %v", err)
+	}
+
+	if len(users) != 3 {
+		t.Fatalf("Expected 3 users, got %d", len(users))
+	}
+
+	ana := users[0]
+	if ana.ID != "11111111-1111-1111-1111-111111111111" {
+		t.Errorf("Unexpected ID: %s", ana.ID)
+	}
+	if ana.Age == nil || *ana.Age != 25 {
+		t.Errorf("Expected age 25, got %v", ana.Age)
+	}
+	if len(ana.Orders) != 2 {
+		t.Errorf("Expected 2 orders, got %d", len(ana.Orders))
+	}
+
+	charlie := users[2]
+	if charlie.Age != nil {
+		t.Errorf("Expected nil age, got %d", *charlie.Age)
+	}
+}
+
+func TestQueryAsTypeMismatch(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	type badUser struct {
+		Email int `cham:"email"`
+	}
+
+	_, err := engine.QueryAs[badUser](eng, "User").All(ctx)
+	if err == nil {
+		t.Fatal("Expected ScanError for string column into int field")
+	}
+}
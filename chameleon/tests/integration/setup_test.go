@@ -43,12 +43,18 @@ func getEnvInt(key string, fallback int) int {
 // setupTestDB creates a fresh engine with loaded schema and DB connection
 func setupTestDB(t *testing.T) (*engine.Engine, context.Context, func()) {
 	t.Helper()
+	return setupTestDBWithSchema(t, "../fixtures/test_schema.cham")
+}
+
+// setupTestDBWithSchema is setupTestDB with a custom fixture schema
+func setupTestDBWithSchema(t *testing.T, schemaPath string) (*engine.Engine, context.Context, func()) {
+	t.Helper()
 
 	ctx := context.Background()
 
 	// Load schema
 	eng := engine.NewEngine()
-	_, err := eng.LoadSchemaFromFile("../fixtures/test_schema.cham")
+	_, err := eng.LoadSchemaFromFile(schemaPath)
 	if err != nil {
 		t.Fatalf("Failed to load test schema: %v", err)
 	}
@@ -94,7 +100,7 @@ func dropAllTables(t *testing.T, ctx context.Context, config engine.ConnectorCon
 	defer conn.Close(ctx)
 
 	// Drop tables in reverse dependency order
-	tables := []string{"order_items", "orders", "users"}
+	tables := []string{"post_tags", "tags", "posts", "order_items", "orders", "users"}
 	for _, table := range tables {
 		_, err := conn.Exec(ctx, fmt.Sprintf("DROP TABLE IF EXISTS %s CASCADE", table))
 		if err != nil {
@@ -0,0 +1,169 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chameleon-db/chameleondb/chameleon/pkg/engine"
+	_ "github.com/chameleon-db/chameleondb/chameleon/pkg/engine/mutation"
+)
+
+func TestTxCommitsOrderWithItems(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	err := eng.Tx(ctx, func(tx *engine.Tx) error {
+		order, err := tx.Insert("Order").
+			Set("id", "99999999-9999-9999-9999-999999999999").
+			Set("total", 42.5).
+			Set("status", "pending").
+			Set("user_id", "33333333-3333-3333-3333-333333333333").
+			Execute(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Insert("OrderItem").
+			Set("id", "88888888-8888-8888-8888-888888888888").
+			Set("quantity", 1).
+			Set("price", 42.5).
+			Set("order_id", order.ID).
+			Execute(ctx)
+		if err != nil {
+			return err
+		}
+
+		// Reads inside the transaction see uncommitted writes
+		inTx, err := tx.Query("Order").Filter("status", "eq", "pending").Execute(ctx)
+		if err != nil {
+			return err
+		}
+		if inTx.Count() != 2 {
+			t.Errorf("Expected 2 pending orders inside tx, got %d", inTx.Count())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Tx failed: %v", err)
+	}
+
+	items, err := eng.Query("OrderItem").Filter("order_id", "eq", "99999999-9999-9999-9999-999999999999").Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if items.Count() != 1 {
+		t.Errorf("Expected committed item, got %d", items.Count())
+	}
+}
+
+func TestTxRollsBackOnError(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	errBoom := errors.New("boom")
+	err := eng.Tx(ctx, func(tx *engine.Tx) error {
+		if _, err := tx.Delete("OrderItem").Execute(ctx); err != nil {
+			return err
+		}
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("Expected callback error, got %v", err)
+	}
+
+	items, err := eng.Query("OrderItem").Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if items.Count() != 3 {
+		t.Errorf("Expected delete to be rolled back (3 items), got %d", items.Count())
+	}
+}
+
+func TestTxSavepointRollback(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	err := eng.Tx(ctx, func(tx *engine.Tx) error {
+		if _, err := tx.Update("User").Filter("email", "eq", "ana@mail.com").Set("name", "Ana G.").Execute(ctx); err != nil {
+			return err
+		}
+
+		// Nested failure only undoes the savepoint
+		nestedErr := tx.Tx(ctx, func(sp *engine.Tx) error {
+			if !sp.IsNested() {
+				t.Error("Expected nested transaction")
+			}
+			if _, err := sp.Delete("OrderItem").Execute(ctx); err != nil {
+				return err
+			}
+			return errors.New("undo savepoint")
+		})
+		if nestedErr == nil {
+			t.Error("Expected savepoint error")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Tx failed: %v", err)
+	}
+
+	users, err := eng.Query("User").Filter("email", "eq", "ana@mail.com").Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if users.Count() != 1 || users.Rows[0].String("name") != "Ana G." {
+		t.Errorf("Expected outer update to be committed, got %v", users.Rows)
+	}
+
+	items, err := eng.Query("OrderItem").Execute(ctx)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if items.Count() != 3 {
+		t.Errorf("Expected savepoint delete to be rolled back (3 items), got %d", items.Count())
+	}
+}
+
+func TestTxManualBeginWithIsolation(t *testing.T) {
+	skipIfNoDocker(t)
+
+	eng, ctx, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	runMigration(t, eng, ctx)
+	insertTestData(t, ctx, testConfig())
+
+	tx, err := eng.BeginTx(ctx, engine.TxOptions{Isolation: engine.IsolationSerializable})
+	if err != nil {
+		t.Fatalf("BeginTx failed: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Querier().Query(ctx, "SHOW transaction_isolation")
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0]["transaction_isolation"] != "serializable" {
+		t.Errorf("Expected serializable isolation, got %v", rows)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Commit failed: %v", err)
+	}
+}